@@ -0,0 +1,153 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import (
+	"context"
+	"math"
+	"slices"
+)
+
+// aStar finds least-cost paths in a visibility graph with the A* search
+// algorithm. Its open set and bookkeeping maps are kept between searches,
+// so that repeated searches do not allocate once the buffers have grown
+// to the size of the graph, apart from the resulting path.
+// An aStar must not be used by multiple goroutines concurrently.
+type aStar struct {
+	open   distQueue
+	cost   map[Point]float64
+	prev   map[Point]Point
+	closed map[Point]bool
+}
+
+// ctxCheckInterval is the number of nodes that the A* search expands
+// between two checks for the cancellation of its context.
+const ctxCheckInterval = 64
+
+// findPath finds the least-cost path between start and dest in graph g
+// using the cost function d and the cost heuristic function h.
+// It returns nil if no path was found.
+func (a *aStar) findPath(g graph[Point], start, dest Point, d, h func(a, b Point) float64) []Point {
+	path, _ := a.findPathContext(context.Background(), g, start, dest, d, h)
+	return path
+}
+
+// findPathContext is like findPath, but stops the search and returns the
+// error of ctx if ctx is cancelled.
+func (a *aStar) findPathContext(ctx context.Context, g graph[Point], start, dest Point, d, h func(a, b Point) float64) ([]Point, error) {
+	return a.findPathBudget(ctx, g, start, dest, d, h, math.Inf(1))
+}
+
+// findPathBudget is like findPathContext, but gives up and returns nil as
+// soon as the cost of every remaining path, as estimated by h, exceeds
+// maxCost. Nodes beyond the budget are never expanded.
+func (a *aStar) findPathBudget(ctx context.Context, g graph[Point], start, dest Point, d, h func(a, b Point) float64, maxCost float64) ([]Point, error) {
+	if a.cost == nil {
+		a.cost = make(map[Point]float64)
+		a.prev = make(map[Point]Point)
+		a.closed = make(map[Point]bool)
+	}
+	clear(a.cost)
+	clear(a.prev)
+	clear(a.closed)
+	a.open = a.open[:0]
+
+	a.cost[start] = 0
+	a.open.push(distItem{node: start, dist: h(start, dest)})
+	for expanded := 0; len(a.open) > 0; {
+		it := a.open.pop()
+		n := it.node
+		if a.closed[n] {
+			continue
+		}
+		if it.dist > maxCost {
+			// The open set is ordered by estimated cost, so no
+			// remaining path can stay within the budget.
+			return nil, nil
+		}
+		if n == dest {
+			return a.trace(start, dest), nil
+		}
+		a.closed[n] = true
+		if expanded++; expanded%ctxCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		for nb := range g.Neighbours(n) {
+			c := a.cost[n] + d(n, nb)
+			// Of equally cheap ways to reach a node, the one from the
+			// lexicographically smallest predecessor is taken, so that
+			// ties are broken independently of the order of the edges.
+			if old, ok := a.cost[nb]; ok && (old < c || old == c && (nb == start || comparePoints(a.prev[nb], n) <= 0)) {
+				continue
+			}
+			est := c + h(nb, dest)
+			if est > maxCost {
+				continue
+			}
+			a.cost[nb] = c
+			a.prev[nb] = n
+			a.open.push(distItem{node: nb, dist: est})
+		}
+	}
+	return nil, nil
+}
+
+// trace follows the predecessors back from dest to start and returns the
+// path from start to dest.
+func (a *aStar) trace(start, dest Point) []Point {
+	n := 1
+	for pt := dest; pt != start; pt = a.prev[pt] {
+		n++
+	}
+	path := make([]Point, 0, n)
+	for pt := dest; pt != start; pt = a.prev[pt] {
+		path = append(path, pt)
+	}
+	path = append(path, start)
+	slices.Reverse(path)
+	return path
+}
+
+// push adds an item to the queue. Unlike heap.Push it does not box the item
+// in an interface value and therefore does not allocate once the queue has
+// grown to its working size.
+func (q *distQueue) push(it distItem) {
+	*q = append(*q, it)
+	h := *q
+	for i := len(h) - 1; i > 0; {
+		parent := (i - 1) / 2
+		if !h.Less(i, parent) {
+			break
+		}
+		h.Swap(i, parent)
+		i = parent
+	}
+}
+
+// pop removes and returns the item with the least dist from the queue.
+func (q *distQueue) pop() distItem {
+	h := *q
+	n := len(h) - 1
+	h.Swap(0, n)
+	for i := 0; ; {
+		least := i
+		if l := 2*i + 1; l < n && h.Less(l, least) {
+			least = l
+		}
+		if r := 2*i + 2; r < n && h.Less(r, least) {
+			least = r
+		}
+		if least == i {
+			break
+		}
+		h.Swap(i, least)
+		i = least
+	}
+	it := h[n]
+	*q = h[:n]
+	return it
+}
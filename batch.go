@@ -0,0 +1,106 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// A PathRequest describes a single path query submitted to PathBatch.
+type PathRequest struct {
+	Start, Dest Point
+}
+
+// A PathResultItem holds the result of a PathRequest.
+// Path is nil if no path exists, with the same semantics as Pathfinder.Path.
+type PathResultItem struct {
+	Path []Point
+}
+
+// PathBatch finds the shortest paths for many requests at once. The requests
+// are distributed across a pool of worker goroutines, each with its own
+// scratch buffers. The returned results are aligned by index with reqs.
+//
+// Unlike Path, PathBatch does not update the graph returned by
+// VisibilityGraph.
+func (p *Pathfinder) PathBatch(reqs []PathRequest) []PathResultItem {
+	results := make([]PathResultItem, len(reqs))
+	workers := min(runtime.GOMAXPROCS(0), len(reqs))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var s scratch
+			for i := range jobs {
+				results[i].Path, _, _ = p.findPath(context.Background(), reqs[i].Start, reqs[i].Dest, &s)
+			}
+		}()
+	}
+	for i := range reqs {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// PathsFrom finds the shortest paths from start to each of the destinations,
+// e.g. to show the travel distances from the player to several markers.
+// The lines of sight from start to the corners of the polygons are only
+// determined once and shared by the searches, as with WithStart. The
+// results are keyed by the requested destination and equal the results of
+// separate Path calls, including the clamping of destinations outside of
+// the polygon set.
+//
+// Unlike Path, PathsFrom does not update the graph returned by
+// VisibilityGraph.
+func (p *Pathfinder) PathsFrom(start Point, dests []Point) map[Point][]Point {
+	paths := make(map[Point][]Point, len(dests))
+	sp := p.WithStart(start)
+	for _, dest := range dests {
+		if _, ok := paths[dest]; ok {
+			continue
+		}
+		paths[dest] = sp.Path(dest)
+	}
+	return paths
+}
+
+// A StartBoundPathfinder finds paths from a fixed start point, e.g. the
+// spawn point shared by many agents with different targets. It is created
+// by Pathfinder.WithStart.
+//
+// A StartBoundPathfinder is not safe for concurrent use by multiple
+// goroutines. It must not be used after the polygon set or the Graph of its
+// Pathfinder has changed; call WithStart again instead.
+type StartBoundPathfinder struct {
+	p     *Pathfinder
+	start Point
+	sight map[Point][2]bool
+}
+
+// WithStart returns a StartBoundPathfinder for paths from start. The lines
+// of sight between start and the corners of the polygons are determined at
+// most once and reused by all of its path queries, so each query only has
+// to link the destination into the visibility graph.
+func (p *Pathfinder) WithStart(start Point) *StartBoundPathfinder {
+	return &StartBoundPathfinder{p: p, start: start, sight: make(map[Point][2]bool)}
+}
+
+// Path finds the shortest path from the bound start point to dest. The
+// result equals the result of Pathfinder.Path, but unlike it, Path does
+// not update the graph returned by VisibilityGraph.
+func (sp *StartBoundPathfinder) Path(dest Point) []Point {
+	s := sp.p.getScratch()
+	s.sightOrigin, s.sight = sp.start, sp.sight
+	path, _, _ := sp.p.findPath(context.Background(), sp.start, dest, s)
+	s.sight = nil
+	sp.p.scratchPool.Put(s)
+	return path
+}
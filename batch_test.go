@@ -0,0 +1,83 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+func TestPathfinderPathBatch(t *testing.T) {
+	pathfinder := pathfind.NewPathfinder(polygonU)
+	var reqs []pathfind.PathRequest
+	for x := 1.0; x < 30; x += 3 {
+		for y := 1.0; y < 20; y += 3 {
+			reqs = append(reqs, pathfind.PathRequest{
+				Start: pathfind.Pt(5, 5),
+				Dest:  pathfind.Pt(x, y),
+			})
+		}
+	}
+	results := pathfinder.PathBatch(reqs)
+	if len(results) != len(reqs) {
+		t.Fatalf("PathBatch returned %d results, want %d", len(results), len(reqs))
+	}
+	for i, req := range reqs {
+		want := pathfinder.Path(req.Start, req.Dest)
+		if got := results[i].Path; !reflect.DeepEqual(got, want) {
+			t.Errorf("PathBatch result %d for %v -> %v\n got: %v\nwant: %v",
+				i, req.Start, req.Dest, got, want)
+		}
+	}
+}
+
+func TestPathfinderPathBatchEmpty(t *testing.T) {
+	pathfinder := pathfind.NewPathfinder(polygonU)
+	if results := pathfinder.PathBatch(nil); len(results) != 0 {
+		t.Errorf("PathBatch(nil) = %v, want empty result", results)
+	}
+}
+
+func TestPathfinderPathsFrom(t *testing.T) {
+	pathfinder := pathfind.NewPathfinder(polygonU)
+	start := pathfind.Pt(5, 5)
+	var dests []pathfind.Point
+	for x := -2.0; x < 33; x += 3 {
+		for y := -2.0; y < 23; y += 3 {
+			dests = append(dests, pathfind.Pt(x, y))
+		}
+	}
+	paths := pathfinder.PathsFrom(start, dests)
+	if len(paths) != len(dests) {
+		t.Fatalf("PathsFrom returned %d paths, want %d", len(paths), len(dests))
+	}
+	for _, dest := range dests {
+		want := pathfinder.Path(start, dest)
+		if got := paths[dest]; !reflect.DeepEqual(got, want) {
+			t.Errorf("PathsFrom path to %v\n got: %v\nwant: %v", dest, got, want)
+		}
+	}
+}
+
+func TestPathfinderWithStart(t *testing.T) {
+	pathfinder := pathfind.NewPathfinder(polygonO)
+	start := pathfind.Pt(20, 5)
+	sp := pathfinder.WithStart(start)
+	for _, dest := range []pathfind.Point{
+		pathfind.Pt(20, 35),
+		pathfind.Pt(35, 20),
+		pathfind.Pt(20, 20),
+		pathfind.Pt(20, 35),
+		pathfind.Pt(50, 50),
+		pathfind.Pt(5, 20),
+	} {
+		want := pathfinder.Path(start, dest)
+		if got := sp.Path(dest); !reflect.DeepEqual(got, want) {
+			t.Errorf("WithStart(%v).Path(%v)\n got: %v\nwant: %v", start, dest, got, want)
+		}
+	}
+}
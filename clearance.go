@@ -0,0 +1,118 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import (
+	"math"
+
+	"github.com/fzipp/pathfind/internal/poly"
+)
+
+// clearanceEps is the tolerance for the distance between a path and the
+// polygon edges with WithClearance, which absorbs rounding errors, e.g. for
+// a path along a mitred corner, which is exactly at the clearance distance.
+const clearanceEps = 1e-9
+
+// maxClearPushes is the maximum number of times that keepClear pushes
+// a point away from the nearest polygon edge.
+const maxClearPushes = 8
+
+// mitredVertices returns the vertices of ring with the given indices moved
+// outward by the clearance r: each vertex is moved along the bisector of
+// its corner to the point that has the distance r from both adjacent edges.
+func mitredVertices(ring []Point, indices []int, r float64) []Point {
+	var vs []Point
+	for _, j := range indices {
+		v := ring[j]
+		u1 := ring[(j+len(ring)-1)%len(ring)].Sub(v).Norm()
+		u2 := ring[(j+1)%len(ring)].Sub(v).Norm()
+		bisector := u1.Add(u2)
+		l := bisector.Len()
+		// The sine of half the angle between the edges.
+		sin := u1.Dist(u2) / 2
+		if l == 0 || sin == 0 {
+			continue
+		}
+		k := -r / sin / l
+		vs = append(vs, v.Add(bisector.Mul(k)))
+	}
+	return vs
+}
+
+// wallEdges returns the edges of the polygons that bound the accessible
+// area. Polygons that are not part of the polygon set ps, such as weighted
+// regions, are skipped.
+func wallEdges(polygons [][]Point, ps poly.PolygonSet) [][2]Point {
+	var walls [][2]Point
+	for i, polygon := range polygons {
+		if len(ps[i]) == 0 {
+			continue
+		}
+		ring := openRing(polygon)
+		for j, a := range ring {
+			walls = append(walls, [2]Point{a, ring[(j+1)%len(ring)]})
+		}
+	}
+	return walls
+}
+
+// visible reports whether b is in line of sight of a and, with a clearance,
+// whether the line segment between them keeps the clearance to the walls.
+func (p *Pathfinder) visible(a, b Point) bool {
+	return p.inSight(a, b) && (p.opts.clearance <= 0 || p.hasClearance(a, b))
+}
+
+// hasClearance reports whether the line segment from a to b keeps at least
+// the clearance distance to all walls.
+func (p *Pathfinder) hasClearance(a, b Point) bool {
+	return wallDist(p.walls, a, b) >= p.opts.clearance-clearanceEps
+}
+
+// wallDist returns the smallest distance between the line segment from a to
+// b and the walls.
+func wallDist(walls [][2]Point, a, b Point) float64 {
+	d := math.Inf(1)
+	for _, w := range walls {
+		if _, ok := SegmentsIntersect(a, b, w[0], w[1]); ok {
+			return 0
+		}
+		u, v := closestPoints(a, b, w[0], w[1])
+		d = min(d, nodeDist(u, v))
+	}
+	return d
+}
+
+// keepClear pushes pt away from the walls that are closer than the
+// clearance distance, as far as it takes to restore the clearance. The
+// result is false if no such position is found near pt.
+func (p *Pathfinder) keepClear(pt Point) (Point, bool) {
+	return keepClear(p.polygonSet, p.walls, pt, p.opts.clearance, p.opts.margin)
+}
+
+// keepClear is like the method of the same name for the polygon set ps
+// with the given walls, clearance and margin.
+func keepClear(ps poly.PolygonSet, walls [][2]Point, pt Point, clearance, margin float64) (Point, bool) {
+	level := containmentLevel(ps, pt)
+	for range maxClearPushes {
+		nearest, d := pt, math.Inf(1)
+		for _, w := range walls {
+			c := closestOnSegment(w[0], w[1], pt)
+			if dc := nodeDist(c, pt); dc < d {
+				nearest, d = c, dc
+			}
+		}
+		if d >= clearance-clearanceEps {
+			break
+		}
+		dir := pt.Sub(nearest)
+		if d == 0 {
+			dir = ensureInside(ps, pt, margin).Sub(nearest)
+		}
+		pt = nearest.Add(dir.Norm().Mul(clearance))
+	}
+	ok := wallDist(walls, pt, pt) >= clearance-clearanceEps && strictlyInside(ps, pt) &&
+		containmentLevel(ps, pt) == level
+	return pt, ok
+}
@@ -0,0 +1,65 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+func TestPathfinderWithClearance(t *testing.T) {
+	tests := []struct {
+		name        string
+		radius      float64
+		start, dest pathfind.Point
+		want        []pathfind.Point
+	}{
+		{
+			name:   "through the wide passage",
+			radius: 1,
+			start:  pathfind.Pt(20, 10),
+			dest:   pathfind.Pt(80, 10),
+			want:   []pathfind.Point{pathfind.Pt(20, 10), pathfind.Pt(39, 4), pathfind.Pt(61, 4), pathfind.Pt(80, 10)},
+		},
+		{
+			name:   "lower passage too narrow",
+			radius: 3,
+			start:  pathfind.Pt(20, 10),
+			dest:   pathfind.Pt(80, 10),
+			want:   []pathfind.Point{pathfind.Pt(20, 10), pathfind.Pt(37, 53), pathfind.Pt(63, 53), pathfind.Pt(80, 10)},
+		},
+		{
+			name:   "both passages too narrow",
+			radius: 6,
+			start:  pathfind.Pt(20, 10),
+			dest:   pathfind.Pt(80, 10),
+			want:   nil,
+		},
+		{
+			name:   "destination pushed away from wall",
+			radius: 1,
+			start:  pathfind.Pt(80, 10),
+			dest:   pathfind.Pt(80, 0.5),
+			want:   []pathfind.Point{pathfind.Pt(80, 10), pathfind.Pt(80, 1)},
+		},
+		{
+			name:   "destination pushed out of corner",
+			radius: 2,
+			start:  pathfind.Pt(80, 10),
+			dest:   pathfind.Pt(99, 59),
+			want:   []pathfind.Point{pathfind.Pt(80, 10), pathfind.Pt(98, 58)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pathfinder := pathfind.NewPathfinder(polygonTwoPassages, pathfind.WithClearance(tt.radius))
+			got := pathfinder.Path(tt.start, tt.dest)
+			if !pathNearEq(got, tt.want) {
+				t.Errorf("Path(%v, %v) = %v, want %v", tt.start, tt.dest, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import (
+	"context"
+	"math"
+)
+
+// PathWithCost is like Path, but also returns the length of the path, e.g.
+// to choose between destinations or to move an agent at constant speed.
+// The length is measured along the returned waypoints, so it is the
+// distance that is actually travelled when following the path. With the
+// WithMetric option it is measured with the given cost function instead.
+// PathWithCost returns nil and zero if no path exists.
+func (p *Pathfinder) PathWithCost(start, dest Point) ([]Point, float64) {
+	path := p.Path(start, dest)
+	if p.opts.cost != nil {
+		return path, pathCost(path, p.opts.cost)
+	}
+	return path, pathCost(path, nodeDist)
+}
+
+// PathWithinCost is like Path, but gives up on the search as soon as it is
+// certain that the cheapest path from start to dest costs more than maxCost,
+// e.g. for an agent that only pursues targets within a certain travel
+// distance. This is faster than finding the full path and checking its
+// length afterwards, because the search does not explore beyond the budget.
+// The cost is the length of the path, or the cost given by WithMetric or
+// the region weights of a weighted Pathfinder, measured along the path
+// through the polygon corners before it is moved away from them by a small
+// margin. It reports whether a path within the budget exists; if not, the
+// returned path is nil.
+//
+// Unlike PathWithin, which bounds the area that a path may cross,
+// PathWithinCost bounds its cost.
+func (p *Pathfinder) PathWithinCost(start, dest Point, maxCost float64) ([]Point, bool) {
+	s := p.getScratch()
+	defer p.scratchPool.Put(s)
+	path, _, _ := p.searchPath(context.Background(), start, dest, maxCost, s)
+	if path == nil {
+		return nil, false
+	}
+	offsetPath(p.polygonSet, path, p.opts.margin)
+	return p.simplifyPath(path), true
+}
+
+// ManhattanDist returns the Manhattan distance between a and b, the sum of
+// the absolute differences of their coordinates. It is the length of the
+// shortest connection on a 4-connected grid and can be used with WithMetric
+// both as cost and as heuristic function.
+func ManhattanDist(a, b Point) float64 {
+	return math.Abs(a.X-b.X) + math.Abs(a.Y-b.Y)
+}
+
+// ChebyshevDist returns the Chebyshev distance between a and b, the larger
+// of the absolute differences of their coordinates. It is the length of the
+// shortest connection on an 8-connected grid with diagonal steps of the same
+// cost as straight steps and can be used with WithMetric both as cost and
+// as heuristic function.
+func ChebyshevDist(a, b Point) float64 {
+	return max(math.Abs(a.X-b.X), math.Abs(a.Y-b.Y))
+}
+
+// PathWithCostValue finds the least-cost path from start to dest like Path,
+// but with the edge cost function cost and the cost heuristic function
+// heuristic instead of the Euclidean distance. It returns the path together
+// with its total cost, the sum of cost over the path segments between the
+// nodes of the visibility graph. With the Euclidean distance as cost the
+// total cost is the length of the path.
+//
+// For the search to find the least-cost path, heuristic must never
+// overestimate the cost between two points. A nil heuristic always
+// estimates zero, which turns the A* search into Dijkstra's algorithm.
+// PathWithCostValue returns nil and zero if no path exists.
+func (p *Pathfinder) PathWithCostValue(start, dest Point, cost, heuristic func(a, b Point) float64) ([]Point, float64) {
+	start, dest, ok := p.prepareQuery(start, dest)
+	if !ok {
+		return nil, 0
+	}
+	if heuristic == nil {
+		heuristic = func(a, b Point) float64 { return 0 }
+	}
+	s := p.getScratch()
+	defer p.scratchPool.Put(s)
+	vis, _ := p.prepareVisibilityGraph(context.Background(), start, dest, s)
+	path := s.search.findPath(vis, start, dest, cost, heuristic)
+	if path == nil {
+		return nil, 0
+	}
+	total := pathCost(path, cost)
+	return p.finishPath(p.polygonSet, path, p.visible), total
+}
+
+// pathCost returns the sum of the cost function d over the segments of path.
+func pathCost(path []Point, d func(a, b Point) float64) float64 {
+	var c float64
+	for i := 1; i < len(path); i++ {
+		c += d(path[i-1], path[i])
+	}
+	return c
+}
@@ -0,0 +1,159 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+func TestPathfinderPathWithCost(t *testing.T) {
+	tests := []struct {
+		name        string
+		start, dest pathfind.Point
+		wantCost    float64
+	}{
+		{name: "line of sight", start: pathfind.Pt(5, 5), dest: pathfind.Pt(5, 15), wantCost: 10},
+		{name: "around corners", start: pathfind.Pt(5, 5), dest: pathfind.Pt(25, 5), wantCost: 2*math.Sqrt(50) + 10},
+		{name: "no path", start: pathfind.Pt(15, 5), dest: pathfind.Pt(25, 5), wantCost: 0},
+	}
+	pathfinder := pathfind.NewPathfinder(polygonU)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, cost := pathfinder.PathWithCost(tt.start, tt.dest)
+			if want := pathfinder.Path(tt.start, tt.dest); !pathNearEq(path, want) {
+				t.Errorf("PathWithCost(%v, %v) path = %v, want %v", tt.start, tt.dest, path, want)
+			}
+			if math.Abs(cost-tt.wantCost) > 0.01 {
+				t.Errorf("PathWithCost(%v, %v) cost = %g, want %g", tt.start, tt.dest, cost, tt.wantCost)
+			}
+		})
+	}
+}
+
+func TestPathfinderPathWithinCost(t *testing.T) {
+	aroundCorners := 2*math.Sqrt(50) + 10
+	tests := []struct {
+		name        string
+		start, dest pathfind.Point
+		maxCost     float64
+		wantOK      bool
+	}{
+		{name: "line of sight within budget", start: pathfind.Pt(5, 5), dest: pathfind.Pt(5, 15), maxCost: 10, wantOK: true},
+		{name: "line of sight beyond budget", start: pathfind.Pt(5, 5), dest: pathfind.Pt(5, 15), maxCost: 9.9, wantOK: false},
+		{name: "around corners within budget", start: pathfind.Pt(5, 5), dest: pathfind.Pt(25, 5), maxCost: aroundCorners + 0.1, wantOK: true},
+		{name: "around corners beyond budget", start: pathfind.Pt(5, 5), dest: pathfind.Pt(25, 5), maxCost: aroundCorners - 0.1, wantOK: false},
+		{name: "beyond straight-line estimate", start: pathfind.Pt(5, 5), dest: pathfind.Pt(25, 5), maxCost: 15, wantOK: false},
+		{name: "no path", start: pathfind.Pt(15, 5), dest: pathfind.Pt(25, 5), maxCost: 100, wantOK: false},
+	}
+	pathfinder := pathfind.NewPathfinder(polygonU)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, ok := pathfinder.PathWithinCost(tt.start, tt.dest, tt.maxCost)
+			if ok != tt.wantOK {
+				t.Fatalf("PathWithinCost(%v, %v, %g) ok = %t, want %t", tt.start, tt.dest, tt.maxCost, ok, tt.wantOK)
+			}
+			var want []pathfind.Point
+			if ok {
+				want = pathfinder.Path(tt.start, tt.dest)
+			}
+			if !pathNearEq(path, want) {
+				t.Errorf("PathWithinCost(%v, %v, %g) path = %v, want %v", tt.start, tt.dest, tt.maxCost, path, want)
+			}
+		})
+	}
+}
+
+func TestPathfinderPathWithCostValue(t *testing.T) {
+	dist := func(a, b pathfind.Point) float64 {
+		return math.Hypot(a.X-b.X, a.Y-b.Y)
+	}
+	// Moving through the upper half of the map costs ten times more.
+	swamp := func(a, b pathfind.Point) float64 {
+		if (a.Y+b.Y)/2 < 20 {
+			return 10 * dist(a, b)
+		}
+		return dist(a, b)
+	}
+	start, dest := pathfind.Pt(20, 10), pathfind.Pt(80, 10)
+	tests := []struct {
+		name      string
+		cost      func(a, b pathfind.Point) float64
+		heuristic func(a, b pathfind.Point) float64
+		want      []pathfind.Point
+		wantCost  float64
+	}{
+		{
+			name:      "Euclidean distance",
+			cost:      dist,
+			heuristic: dist,
+			want:      []pathfind.Point{start, pathfind.Pt(40, 5), pathfind.Pt(60, 5), dest},
+			wantCost:  2*math.Sqrt(425) + 20,
+		},
+		{
+			name:     "weighted without heuristic",
+			cost:     swamp,
+			want:     []pathfind.Point{start, pathfind.Pt(40, 50), pathfind.Pt(60, 50), dest},
+			wantCost: 2*math.Sqrt(2000) + 20,
+		},
+	}
+	pathfinder := pathfind.NewPathfinder(polygonTwoPassages)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, cost := pathfinder.PathWithCostValue(start, dest, tt.cost, tt.heuristic)
+			if !pathNearEq(got, tt.want) || math.Abs(cost-tt.wantCost) > 1e-9 {
+				t.Errorf("PathWithCostValue(%v, %v)\n got: %v, %g\nwant: %v, %g",
+					start, dest, got, cost, tt.want, tt.wantCost)
+			}
+		})
+	}
+}
+
+func TestPathfinderWithMetric(t *testing.T) {
+	tests := []struct {
+		name        string
+		metric      func(a, b pathfind.Point) float64
+		start, dest pathfind.Point
+		want        []pathfind.Point
+		wantCost    float64
+	}{
+		{
+			name:     "Manhattan distance around hole",
+			metric:   pathfind.ManhattanDist,
+			start:    pathfind.Pt(20, 25),
+			dest:     pathfind.Pt(80, 25),
+			want:     []pathfind.Point{pathfind.Pt(20, 25), pathfind.Pt(40, 5), pathfind.Pt(60, 5), pathfind.Pt(80, 25)},
+			wantCost: 100,
+		},
+		{
+			name:     "Chebyshev distance around hole",
+			metric:   pathfind.ChebyshevDist,
+			start:    pathfind.Pt(20, 25),
+			dest:     pathfind.Pt(80, 25),
+			want:     []pathfind.Point{pathfind.Pt(20, 25), pathfind.Pt(40, 5), pathfind.Pt(60, 5), pathfind.Pt(80, 25)},
+			wantCost: 60,
+		},
+		{
+			name:     "Manhattan distance in line of sight",
+			metric:   pathfind.ManhattanDist,
+			start:    pathfind.Pt(10, 10),
+			dest:     pathfind.Pt(30, 40),
+			want:     []pathfind.Point{pathfind.Pt(10, 10), pathfind.Pt(30, 40)},
+			wantCost: 50,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pathfinder := pathfind.NewPathfinder(polygonTwoPassages, pathfind.WithMetric(tt.metric, tt.metric))
+			got, cost := pathfinder.PathWithCost(tt.start, tt.dest)
+			if !pathNearEq(got, tt.want) || math.Abs(cost-tt.wantCost) > 0.01 {
+				t.Errorf("PathWithCost(%v, %v)\n got: %v, %g\nwant: %v, %g",
+					tt.start, tt.dest, got, cost, tt.want, tt.wantCost)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+// VisibilityCover returns a small set of positions from which every concave
+// vertex of the polygon set is visible, e.g. for placing sentinels so that
+// they watch all corners of a map. The candidate positions are the concave
+// vertices themselves, slightly moved into the accessible area.
+//
+// Finding the smallest such set is NP-hard, so VisibilityCover uses the
+// greedy set cover heuristic: it repeatedly picks the candidate that sees
+// the most vertices that are not yet covered. The result is at most a
+// logarithmic factor larger than the optimum.
+func (p *Pathfinder) VisibilityCover() []Point {
+	uncovered := make(map[Point]bool, len(p.concaveVertices))
+	for _, v := range p.concaveVertices {
+		uncovered[v] = true
+	}
+	var cover []Point
+	for len(uncovered) > 0 {
+		var best Point
+		bestCount := 0
+		for _, c := range p.concaveVertices {
+			count := 0
+			if uncovered[c] {
+				count++
+			}
+			for _, v := range p.cachedGraph[c] {
+				if uncovered[v] {
+					count++
+				}
+			}
+			if count > bestCount {
+				best, bestCount = c, count
+			}
+		}
+		delete(uncovered, best)
+		for _, v := range p.cachedGraph[best] {
+			delete(uncovered, v)
+		}
+		cover = append(cover, offsetFromBoundary(p.polygonSet, best, p.opts.margin))
+	}
+	return cover
+}
@@ -0,0 +1,43 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+func TestPathfinderVisibilityCover(t *testing.T) {
+	tests := []struct {
+		name     string
+		polygons [][]pathfind.Point
+		want     []pathfind.Point
+	}{
+		{
+			name:     "both inner corners of the U see each other",
+			polygons: polygonU,
+			want:     []pathfind.Point{pathfind.Pt(10, 10)},
+		},
+		{
+			name:     "no corner of the diamond sees the opposite corner",
+			polygons: polygonO,
+			want:     []pathfind.Point{pathfind.Pt(20, 10), pathfind.Pt(30, 20)},
+		},
+		{
+			name:     "no concave vertices",
+			polygons: [][]pathfind.Point{polygonO[0]},
+			want:     nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pathfind.NewPathfinder(tt.polygons).VisibilityCover()
+			if !pathNearEq(got, tt.want) {
+				t.Errorf("VisibilityCover()\n got: %v\nwant: %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import "math"
+
+// PathDeviation returns how far the paths a and b deviate from each other,
+// e.g. to decide whether a recomputed path differs enough from the previous
+// one to issue a new movement command. The deviation is the largest distance
+// of a vertex of one path from the polyline of the other path, a variant of
+// the Hausdorff distance. It is zero for paths that run along the same
+// polyline, even if one of them has additional collinear vertices.
+//
+// The deviation of two empty paths is zero, and the deviation of an empty
+// and a non-empty path is positive infinity.
+func PathDeviation(a, b []Point) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		if len(a) == len(b) {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	return max(directedDeviation(a, b), directedDeviation(b, a))
+}
+
+// directedDeviation returns the largest distance of a vertex of path a
+// from the polyline of path b.
+func directedDeviation(a, b []Point) float64 {
+	var dev float64
+	for _, pt := range a {
+		d := nodeDist(pt, b[0])
+		for i := 1; i < len(b); i++ {
+			d = min(d, segmentPointDist(b[i-1], b[i], pt))
+		}
+		dev = max(dev, d)
+	}
+	return dev
+}
+
+// NearestPointOnPath returns the point on the polyline of path that is
+// closest to pt, e.g. to find where an agent that drifted off its route
+// should rejoin it. It also returns the index i of the segment from path[i]
+// to path[i+1] that the point lies on, and the distance of the point from
+// pt. If several points are equally close, the one on the first such
+// segment is returned.
+//
+// For a path with a single point, that point and the index 0 are returned.
+// For an empty path, or if a coordinate of pt is NaN or infinite, the
+// index is -1 and the distance is positive infinity.
+func NearestPointOnPath(path []Point, pt Point) (Point, int, float64) {
+	if len(path) == 0 || !pt.isFinite() {
+		return Point{}, -1, math.Inf(1)
+	}
+	nearest, index, dist := path[0], 0, nodeDist(path[0], pt)
+	for i := 1; i < len(path); i++ {
+		c := closestOnSegment(path[i-1], path[i], pt)
+		if d := nodeDist(c, pt); d < dist {
+			nearest, index, dist = c, i-1, d
+		}
+	}
+	return nearest, index, dist
+}
@@ -0,0 +1,149 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+func TestPathDeviation(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []pathfind.Point
+		want float64
+	}{
+		{
+			name: "identical",
+			a:    []pathfind.Point{pathfind.Pt(0, 0), pathfind.Pt(10, 0), pathfind.Pt(10, 10)},
+			b:    []pathfind.Point{pathfind.Pt(0, 0), pathfind.Pt(10, 0), pathfind.Pt(10, 10)},
+			want: 0,
+		},
+		{
+			name: "additional collinear vertex",
+			a:    []pathfind.Point{pathfind.Pt(0, 0), pathfind.Pt(10, 0)},
+			b:    []pathfind.Point{pathfind.Pt(0, 0), pathfind.Pt(4, 0), pathfind.Pt(10, 0)},
+			want: 0,
+		},
+		{
+			name: "detour",
+			a:    []pathfind.Point{pathfind.Pt(0, 0), pathfind.Pt(10, 0)},
+			b:    []pathfind.Point{pathfind.Pt(0, 0), pathfind.Pt(5, 3), pathfind.Pt(10, 0)},
+			want: 3,
+		},
+		{
+			name: "shifted",
+			a:    []pathfind.Point{pathfind.Pt(0, 0), pathfind.Pt(10, 0)},
+			b:    []pathfind.Point{pathfind.Pt(0, 1), pathfind.Pt(10, 1)},
+			want: 1,
+		},
+		{
+			name: "single points",
+			a:    []pathfind.Point{pathfind.Pt(0, 0)},
+			b:    []pathfind.Point{pathfind.Pt(3, 4)},
+			want: 5,
+		},
+		{
+			name: "both empty",
+			want: 0,
+		},
+		{
+			name: "one empty",
+			a:    []pathfind.Point{pathfind.Pt(0, 0)},
+			want: math.Inf(1),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pathfind.PathDeviation(tt.a, tt.b)
+			if got != tt.want && math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("PathDeviation(%v, %v) = %g, want %g", tt.a, tt.b, got, tt.want)
+			}
+			if rev := pathfind.PathDeviation(tt.b, tt.a); rev != got {
+				t.Errorf("PathDeviation(%v, %v) = %g, want %g", tt.b, tt.a, rev, got)
+			}
+		})
+	}
+}
+
+func TestNearestPointOnPath(t *testing.T) {
+	path := []pathfind.Point{pathfind.Pt(0, 0), pathfind.Pt(10, 0), pathfind.Pt(10, 10)}
+	tests := []struct {
+		name      string
+		path      []pathfind.Point
+		pt        pathfind.Point
+		wantPoint pathfind.Point
+		wantIndex int
+		wantDist  float64
+	}{
+		{
+			name:      "on first segment",
+			path:      path,
+			pt:        pathfind.Pt(4, 3),
+			wantPoint: pathfind.Pt(4, 0),
+			wantIndex: 0,
+			wantDist:  3,
+		},
+		{
+			name:      "on second segment",
+			path:      path,
+			pt:        pathfind.Pt(12, 6),
+			wantPoint: pathfind.Pt(10, 6),
+			wantIndex: 1,
+			wantDist:  2,
+		},
+		{
+			name:      "before start",
+			path:      path,
+			pt:        pathfind.Pt(-3, -4),
+			wantPoint: pathfind.Pt(0, 0),
+			wantIndex: 0,
+			wantDist:  5,
+		},
+		{
+			name:      "corner, first segment wins",
+			path:      path,
+			pt:        pathfind.Pt(13, -3),
+			wantPoint: pathfind.Pt(10, 0),
+			wantIndex: 0,
+			wantDist:  math.Sqrt(18),
+		},
+		{
+			name:      "on the path",
+			path:      path,
+			pt:        pathfind.Pt(10, 7),
+			wantPoint: pathfind.Pt(10, 7),
+			wantIndex: 1,
+			wantDist:  0,
+		},
+		{
+			name:      "single point",
+			path:      []pathfind.Point{pathfind.Pt(1, 1)},
+			pt:        pathfind.Pt(4, 5),
+			wantPoint: pathfind.Pt(1, 1),
+			wantIndex: 0,
+			wantDist:  5,
+		},
+		{
+			name:      "empty path",
+			path:      nil,
+			pt:        pathfind.Pt(4, 5),
+			wantPoint: pathfind.Point{},
+			wantIndex: -1,
+			wantDist:  math.Inf(1),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, index, dist := pathfind.NearestPointOnPath(tt.path, tt.pt)
+			if got.Dist(tt.wantPoint) > 1e-9 || index != tt.wantIndex || !(math.Abs(dist-tt.wantDist) <= 1e-9 || dist == tt.wantDist) {
+				t.Errorf("NearestPointOnPath(%v, %v) = %v, %d, %g, want %v, %d, %g",
+					tt.path, tt.pt, got, index, dist, tt.wantPoint, tt.wantIndex, tt.wantDist)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import (
+	"container/heap"
+	"slices"
+)
+
+// dijkstra computes the least costs from the nearest of the source nodes to
+// the nodes of graph g using Dijkstra's algorithm with the cost function d.
+// All sources start at cost 0. The search stops as soon as a node for which
+// done returns true is settled; done may be nil to search the whole graph.
+// The returned prev map records the predecessor of each reached node on its
+// least-cost path, which can be followed back to one of the sources.
+func dijkstra(g graph[Point], sources []Point, d func(a, b Point) float64, done func(Point) bool) (dist map[Point]float64, prev map[Point]Point) {
+	dist = make(map[Point]float64)
+	prev = make(map[Point]Point)
+	settled := make(map[Point]bool)
+	pq := &distQueue{}
+	for _, s := range sources {
+		if _, ok := dist[s]; !ok {
+			dist[s] = 0
+			heap.Push(pq, distItem{node: s})
+		}
+	}
+	for pq.Len() > 0 {
+		it := heap.Pop(pq).(distItem)
+		n := it.node
+		if settled[n] {
+			continue
+		}
+		settled[n] = true
+		if done != nil && done(n) {
+			break
+		}
+		for _, nb := range g[n] {
+			c := it.dist + d(n, nb)
+			if old, ok := dist[nb]; !ok || c < old {
+				dist[nb] = c
+				prev[nb] = n
+				heap.Push(pq, distItem{node: nb, dist: c})
+			}
+		}
+	}
+	return dist, prev
+}
+
+// tracePath follows the predecessors in prev back from node n and returns
+// the path ending at n.
+func tracePath(prev map[Point]Point, n Point) []Point {
+	path := []Point{n}
+	for {
+		p, ok := prev[n]
+		if !ok {
+			break
+		}
+		path = append(path, p)
+		n = p
+	}
+	slices.Reverse(path)
+	return path
+}
+
+// A distItem is a node in the priority queue of Dijkstra's algorithm.
+type distItem struct {
+	node Point
+	dist float64
+}
+
+// distQueue is a min-heap of distItems ordered by dist. Items with equal
+// dist are ordered by their nodes with comparePoints, so that the order in
+// which they are taken from the queue does not depend on the order in which
+// they were added. It implements heap.Interface.
+type distQueue []distItem
+
+func (q distQueue) Len() int      { return len(q) }
+func (q distQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q distQueue) Less(i, j int) bool {
+	if q[i].dist != q[j].dist {
+		return q[i].dist < q[j].dist
+	}
+	return comparePoints(q[i].node, q[j].node) < 0
+}
+
+func (q *distQueue) Push(x any) {
+	*q = append(*q, x.(distItem))
+}
+
+func (q *distQueue) Pop() any {
+	old := *q
+	n := len(old)
+	it := old[n-1]
+	*q = old[:n-1]
+	return it
+}
@@ -0,0 +1,84 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import "math"
+
+// DistanceFromAny returns the length of the shortest path from the nearest
+// of the sources to pt, e.g. the walking distance to the nearest guard post.
+// All sources are searched simultaneously, so this is cheaper than finding
+// a path from each source separately. Sources that are not in the same
+// nested area as pt are ignored. Unlike Path, pt is not clamped to the
+// polygon set. If pt cannot be reached from any source, the result is +Inf.
+// With weighted regions or WithMetric, the result is the cost of the path
+// as for Path.
+func (p *Pathfinder) DistanceFromAny(sources []Point, pt Point) float64 {
+	if !pt.isFinite() {
+		return math.Inf(1)
+	}
+	level := containmentLevel(p.polygonSet, pt)
+	var reachable []Point
+	for _, s := range sources {
+		if s.isFinite() && containmentLevel(p.polygonSet, s) == level {
+			reachable = append(reachable, s)
+		}
+	}
+	if len(reachable) == 0 {
+		return math.Inf(1)
+	}
+	vis := copyGraph(p.cachedGraph)
+	p.linkIntoGraph(vis, pt, nil)
+	for _, s := range reachable {
+		p.linkIntoGraph(vis, s, []Point{pt})
+	}
+	cost, _ := p.travelCost()
+	dist, _ := dijkstra(vis, reachable, cost, func(n Point) bool {
+		return n == pt
+	})
+	if d, ok := dist[pt]; ok {
+		return d
+	}
+	return math.Inf(1)
+}
+
+// linkIntoGraph links pt in both directions with each concave vertex and
+// each of the other points that is in line of sight of pt.
+func (p *Pathfinder) linkIntoGraph(vis graph[Point], pt Point, others []Point) {
+	for _, list := range [][]Point{p.concaveVertices, others} {
+		for _, b := range list {
+			if b != pt && p.inSight(pt, b) {
+				vis.link(pt, b).link(b, pt)
+			}
+		}
+	}
+}
+
+// DistanceToExit returns the walking distance from start to the nearest of
+// the exits, e.g. doors in the outline of a building for an evacuation
+// simulation, together with the index of this exit. Each exit is a line
+// segment, which is reached at the point closest to the last turning point
+// of the path. Unlike a straight-line distance, obstacles between start and
+// the exits are walked around. If no exit can be reached, the result is
+// +Inf and -1. With weighted regions or WithMetric, the distance is the
+// cost of the path as for Path.
+func (p *Pathfinder) DistanceToExit(start Point, exits [][2]Point) (float64, int) {
+	if !start.isFinite() {
+		return math.Inf(1), -1
+	}
+	vis := copyGraph(p.cachedGraph)
+	p.linkIntoGraph(vis, start, nil)
+	cost, _ := p.travelCost()
+	dist, _ := dijkstra(vis, []Point{start}, cost, nil)
+	best, index := math.Inf(1), -1
+	for i, e := range exits {
+		for n, d := range dist {
+			x := closestOnSegment(e[0], e[1], n)
+			if total := d + cost(n, x); total < best && p.inSight(n, x) {
+				best, index = total, i
+			}
+		}
+	}
+	return best, index
+}
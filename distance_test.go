@@ -0,0 +1,111 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+func TestPathfinderDistanceFromAny(t *testing.T) {
+	tests := []struct {
+		name    string
+		sources []pathfind.Point
+		pt      pathfind.Point
+		want    float64
+	}{
+		{
+			name:    "single source in line of sight",
+			sources: []pathfind.Point{pathfind.Pt(5, 5)},
+			pt:      pathfind.Pt(5, 15),
+			want:    10,
+		},
+		{
+			name:    "single source around corners",
+			sources: []pathfind.Point{pathfind.Pt(5, 5)},
+			pt:      pathfind.Pt(25, 5),
+			want:    2*math.Sqrt(50) + 10,
+		},
+		{
+			name:    "nearest of two sources",
+			sources: []pathfind.Point{pathfind.Pt(5, 5), pathfind.Pt(25, 15)},
+			pt:      pathfind.Pt(25, 5),
+			want:    10,
+		},
+		{
+			name:    "point is a source",
+			sources: []pathfind.Point{pathfind.Pt(5, 5), pathfind.Pt(25, 15)},
+			pt:      pathfind.Pt(25, 15),
+			want:    0,
+		},
+		{
+			name:    "no sources",
+			sources: nil,
+			pt:      pathfind.Pt(25, 15),
+			want:    math.Inf(1),
+		},
+		{
+			name:    "point outside of polygons",
+			sources: []pathfind.Point{pathfind.Pt(5, 5)},
+			pt:      pathfind.Pt(15, 5),
+			want:    math.Inf(1),
+		},
+	}
+	pathfinder := pathfind.NewPathfinder(polygonU)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pathfinder.DistanceFromAny(tt.sources, tt.pt)
+			if math.Abs(got-tt.want) > 1e-9 && got != tt.want {
+				t.Errorf("DistanceFromAny(%v, %v) = %g, want %g", tt.sources, tt.pt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathfinderDistanceToExit(t *testing.T) {
+	top := [2]pathfind.Point{pathfind.Pt(20, 0), pathfind.Pt(30, 0)}
+	bottom := [2]pathfind.Point{pathfind.Pt(0, 20), pathfind.Pt(30, 20)}
+	tests := []struct {
+		name      string
+		start     pathfind.Point
+		exits     [][2]pathfind.Point
+		want      float64
+		wantIndex int
+	}{
+		{
+			name:      "nearest exit",
+			start:     pathfind.Pt(5, 5),
+			exits:     [][2]pathfind.Point{top, bottom},
+			want:      15,
+			wantIndex: 1,
+		},
+		{
+			name:      "exit around corners",
+			start:     pathfind.Pt(5, 5),
+			exits:     [][2]pathfind.Point{top},
+			want:      math.Sqrt(50) + 20,
+			wantIndex: 0,
+		},
+		{
+			name:      "no exits",
+			start:     pathfind.Pt(5, 5),
+			exits:     nil,
+			want:      math.Inf(1),
+			wantIndex: -1,
+		},
+	}
+	pathfinder := pathfind.NewPathfinder(polygonU)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, index := pathfinder.DistanceToExit(tt.start, tt.exits)
+			if (math.Abs(got-tt.want) > 1e-9 && got != tt.want) || index != tt.wantIndex {
+				t.Errorf("DistanceToExit(%v, %v) = %g, %d, want %g, %d",
+					tt.start, tt.exits, got, index, tt.want, tt.wantIndex)
+			}
+		})
+	}
+}
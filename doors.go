@@ -0,0 +1,100 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+// PathWithDoors finds the shortest path from start to dest like Path, but
+// treats the doors with the indices in closedDoors as walls that the path
+// must not cross. Doors are registered with the WithDoors option; all other
+// doors are open and do not block the path. Indices without a registered
+// door are ignored. The cached visibility graph is not modified, so doors
+// can be opened and closed between queries without rebuilding anything.
+//
+// The end points of closed doors that lie strictly inside the accessible
+// area are additional turning points, so a path can go around
+// a free-standing door.
+func (p *Pathfinder) PathWithDoors(start, dest Point, closedDoors []int) []Point {
+	var closed [][2]Point
+	for _, i := range closedDoors {
+		if i >= 0 && i < len(p.opts.doors) {
+			closed = append(closed, p.opts.doors[i])
+		}
+	}
+	passable := func(a, b Point) bool {
+		for _, d := range closed {
+			if blocksSegment(d[0], d[1], a, b) {
+				return false
+			}
+		}
+		return true
+	}
+	visible := func(a, b Point) bool {
+		return passable(a, b) && p.visible(a, b)
+	}
+
+	start, dest, ok := p.prepareQuery(start, dest)
+	if !ok {
+		return nil
+	}
+	if p.straightIsCheapest() && visible(start, dest) {
+		return p.finishPath(p.polygonSet, []Point{start, dest}, visible)
+	}
+
+	nodes := append([]Point(nil), p.concaveVertices...)
+	vis := make(graph[Point])
+	for a, adj := range p.cachedGraph {
+		for _, b := range adj {
+			if passable(a, b) {
+				vis.link(a, b)
+			}
+		}
+	}
+	isNode := make(map[Point]bool, len(nodes))
+	for _, v := range nodes {
+		isNode[v] = true
+	}
+	for _, d := range closed {
+		for _, a := range d {
+			if isNode[a] || !strictlyInside(p.polygonSet, a) {
+				continue
+			}
+			for _, b := range nodes {
+				if visible(a, b) {
+					vis.link(a, b).link(b, a)
+				}
+			}
+			nodes = append(nodes, a)
+			isNode[a] = true
+		}
+	}
+	for _, b := range append(nodes, dest) {
+		if visible(start, b) {
+			vis.link(start, b).link(b, start)
+		}
+	}
+	for _, b := range nodes {
+		if visible(dest, b) {
+			vis.link(dest, b).link(b, dest)
+		}
+	}
+
+	cost, heuristic := p.travelCost()
+	var search aStar
+	return p.finishPath(p.polygonSet, search.findPath(vis, start, dest, cost, heuristic), visible)
+}
+
+// blocksSegment reports whether the door from d1 to d2 blocks the line
+// segment from a to b. Unlike SegmentsIntersect, a segment that passes
+// through an end point of the door is blocked as well, since a closed door
+// usually ends at a wall and leaves no gap. A segment that merely touches
+// the door without passing to its other side is not blocked.
+func blocksSegment(d1, d2, a, b Point) bool {
+	d := d2.Sub(d1)
+	sa, sb := cross(d, a.Sub(d1)), cross(d, b.Sub(d1))
+	if sa*sb >= 0 {
+		return false
+	}
+	e := b.Sub(a)
+	return cross(e, d1.Sub(a))*cross(e, d2.Sub(a)) <= 0
+}
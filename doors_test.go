@@ -0,0 +1,75 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+func TestPathfinderPathWithDoors(t *testing.T) {
+	// Door 0 closes the narrow passage above the hole of polygonTwoPassages,
+	// door 1 closes the wide passage below it.
+	doors := [][2]pathfind.Point{
+		{pathfind.Pt(50, 0), pathfind.Pt(50, 5)},
+		{pathfind.Pt(50, 50), pathfind.Pt(50, 60)},
+	}
+	pathfinder := pathfind.NewPathfinder(polygonTwoPassages, pathfind.WithDoors(doors))
+	start, dest := pathfind.Pt(20, 10), pathfind.Pt(80, 10)
+	upper := []pathfind.Point{start, pathfind.Pt(40, 5), pathfind.Pt(60, 5), dest}
+	lower := []pathfind.Point{start, pathfind.Pt(40, 50), pathfind.Pt(60, 50), dest}
+	tests := []struct {
+		name        string
+		closedDoors []int
+		want        []pathfind.Point
+	}{
+		{
+			name:        "all doors open",
+			closedDoors: nil,
+			want:        upper,
+		},
+		{
+			name:        "upper door closed",
+			closedDoors: []int{0},
+			want:        lower,
+		},
+		{
+			name:        "lower door closed",
+			closedDoors: []int{1},
+			want:        upper,
+		},
+		{
+			name:        "all doors closed",
+			closedDoors: []int{0, 1},
+			want:        nil,
+		},
+		{
+			name:        "unknown door is ignored",
+			closedDoors: []int{2, -1},
+			want:        upper,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pathfinder.PathWithDoors(start, dest, tt.closedDoors)
+			if !pathNearEq(got, tt.want) {
+				t.Errorf("PathWithDoors(%v, %v, %v)\n got: %v\nwant: %v",
+					start, dest, tt.closedDoors, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathfinderPathWithDoorsFreeStanding(t *testing.T) {
+	door := [2]pathfind.Point{pathfind.Pt(5, 20), pathfind.Pt(30, 20)}
+	pathfinder := pathfind.NewPathfinder(polygonSquare, pathfind.WithDoors([][2]pathfind.Point{door}))
+	start, dest := pathfind.Pt(20, 5), pathfind.Pt(20, 35)
+	got := pathfinder.PathWithDoors(start, dest, []int{0})
+	want := []pathfind.Point{start, pathfind.Pt(30, 20), dest}
+	if !pathNearEq(got, want) {
+		t.Errorf("PathWithDoors(%v, %v, [0])\n got: %v\nwant: %v", start, dest, got, want)
+	}
+}
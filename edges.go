@@ -0,0 +1,98 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import "math"
+
+// PathBetweenEdges finds the shortest path from any point on the line
+// segment from a1 to a2 to any point on the line segment from b1 to b2,
+// e.g. for docking or boarding, where the exact positions along the edges
+// are flexible. The first and last points of the path are the optimized
+// points on the respective edges: the points closest to the first and last
+// turning point of the path on the parts of the edges that these turning
+// points see, or to each other if the edges are in line of sight. Parts of
+// the edges outside of the accessible area are ignored. If the edges
+// cross, the path consists of the crossing point twice.
+//
+// PathBetweenEdges returns nil if the edges are in different areas of the
+// polygon set or if no path between them exists.
+func (p *Pathfinder) PathBetweenEdges(a1, a2, b1, b2 Point) []Point {
+	if !allFinite(a1, a2, b1, b2) {
+		return nil
+	}
+	midA := Point{X: (a1.X + a2.X) / 2, Y: (a1.Y + a2.Y) / 2}
+	midB := Point{X: (b1.X + b2.X) / 2, Y: (b1.Y + b2.Y) / 2}
+	if containmentLevel(p.polygonSet, midA) != containmentLevel(p.polygonSet, midB) {
+		return nil
+	}
+	if x, ok := SegmentsIntersect(a1, a2, b1, b2); ok {
+		return []Point{x, x}
+	}
+	if u, v := closestPoints(a1, a2, b1, b2); p.inSight(u, v) {
+		return []Point{u, v}
+	}
+
+	// The edges are represented by two nodes outside of the polygon set.
+	// A vertex is linked to the point nearest to it on the part of an edge
+	// that it sees, and the edge costs are the distances to these points.
+	// A vertex only sees parts of an edge in its own area.
+	partsA := p.accessibleParts(a1, a2, -1)
+	partsB := p.accessibleParts(b1, b2, -1)
+	edges := p.edges()
+	edgeA, edgeB := Point{X: math.Inf(-1)}, Point{X: math.Inf(1)}
+	onA := make(map[Point]Point)
+	onB := make(map[Point]Point)
+	vis := copyGraph(p.cachedGraph)
+	for _, v := range p.concaveVertices {
+		if x, ok := nearestInParts(v, a1, a2, visibleParts(v, a1, a2, partsA, edges)); ok && p.inSight(x, v) {
+			onA[v] = x
+			vis.link(edgeA, v)
+		}
+		if x, ok := nearestInParts(v, b1, b2, visibleParts(v, b1, b2, partsB, edges)); ok && p.inSight(v, x) {
+			onB[v] = x
+			vis.link(v, edgeB)
+		}
+	}
+	travelCost, _ := p.travelCost()
+	cost := func(a, b Point) float64 {
+		switch {
+		case a == edgeA:
+			return travelCost(onA[b], b)
+		case b == edgeB:
+			return travelCost(a, onB[a])
+		}
+		return travelCost(a, b)
+	}
+	dist, prev := dijkstra(vis, []Point{edgeA}, cost, func(n Point) bool {
+		return n == edgeB
+	})
+	if _, ok := dist[edgeB]; !ok {
+		return nil
+	}
+	path := tracePath(prev, edgeB)
+	path[0] = onA[path[1]]
+	path[len(path)-1] = onB[path[len(path)-2]]
+	offsetPath(p.polygonSet, path, p.opts.margin)
+	return path
+}
+
+// nearestInParts returns the point nearest to pt on the given parts of the
+// line segment from a to b, which are intervals of the parameter t of the
+// points a+t(b-a). The result is false if there are no parts.
+func nearestInParts(pt, a, b Point, parts [][2]float64) (Point, bool) {
+	g := b.Sub(a)
+	t := 0.0
+	if gg := g.Dot(g); gg > 0 {
+		t = pt.Sub(a).Dot(g) / gg
+	}
+	best, bestDist := Point{}, math.Inf(1)
+	for _, part := range parts {
+		x := a.Add(g.Mul(max(part[0], min(part[1], t))))
+		if d := nodeDist(pt, x); d < bestDist {
+			best, bestDist = x, d
+		}
+	}
+	return best, len(parts) > 0
+}
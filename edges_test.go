@@ -0,0 +1,95 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+func TestPathfinderPathBetweenEdges(t *testing.T) {
+	tests := []struct {
+		name     string
+		polygons [][]pathfind.Point
+		a1, a2   pathfind.Point
+		b1, b2   pathfind.Point
+		want     []pathfind.Point
+	}{
+		{
+			name:     "around corners",
+			polygons: polygonU,
+			a1:       pathfind.Pt(0, 5),
+			a2:       pathfind.Pt(10, 5),
+			b1:       pathfind.Pt(20, 5),
+			b2:       pathfind.Pt(30, 5),
+			want: []pathfind.Point{
+				pathfind.Pt(10, 5),
+				pathfind.Pt(10, 10),
+				pathfind.Pt(20, 10),
+				pathfind.Pt(20, 5),
+			},
+		},
+		{
+			name:     "in line of sight",
+			polygons: polygonSquare,
+			a1:       pathfind.Pt(0, 10),
+			a2:       pathfind.Pt(10, 10),
+			b1:       pathfind.Pt(20, 0),
+			b2:       pathfind.Pt(30, 10),
+			want:     []pathfind.Point{pathfind.Pt(10, 10), pathfind.Pt(20, 0)},
+		},
+		{
+			name:     "crossing",
+			polygons: polygonSquare,
+			a1:       pathfind.Pt(0, 10),
+			a2:       pathfind.Pt(20, 10),
+			b1:       pathfind.Pt(10, 0),
+			b2:       pathfind.Pt(10, 20),
+			want:     []pathfind.Point{pathfind.Pt(10, 10), pathfind.Pt(10, 10)},
+		},
+		{
+			// The point of the first edge nearest to the corner of the
+			// divider lies in the triangular hole, so the path starts
+			// where the edge leaves the hole instead of going around it.
+			name: "nearest point hidden",
+			polygons: [][]pathfind.Point{
+				{
+					pathfind.Pt(0, 0), pathfind.Pt(18, 0), pathfind.Pt(18, 30), pathfind.Pt(22, 30),
+					pathfind.Pt(22, 0), pathfind.Pt(40, 0), pathfind.Pt(40, 40), pathfind.Pt(0, 40),
+				},
+				{pathfind.Pt(6, 27), pathfind.Pt(12, 30), pathfind.Pt(6, 33)},
+			},
+			a1: pathfind.Pt(10, 20),
+			a2: pathfind.Pt(10, 38),
+			b1: pathfind.Pt(25, 5),
+			b2: pathfind.Pt(35, 5),
+			want: []pathfind.Point{
+				pathfind.Pt(10, 31),
+				pathfind.Pt(22, 30),
+				pathfind.Pt(25, 5),
+			},
+		},
+		{
+			name:     "different areas",
+			polygons: polygonO,
+			a1:       pathfind.Pt(18, 20),
+			a2:       pathfind.Pt(22, 20),
+			b1:       pathfind.Pt(2, 2),
+			b2:       pathfind.Pt(5, 2),
+			want:     nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pathfinder := pathfind.NewPathfinder(tt.polygons)
+			got := pathfinder.PathBetweenEdges(tt.a1, tt.a2, tt.b1, tt.b2)
+			if !pathNearEq(got, tt.want) {
+				t.Errorf("PathBetweenEdges(%v, %v, %v, %v)\n got: %v\nwant: %v",
+					tt.a1, tt.a2, tt.b1, tt.b2, got, tt.want)
+			}
+		})
+	}
+}
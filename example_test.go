@@ -43,7 +43,9 @@ func ExamplePathfinder_Path() {
 
 	pathfinder := pathfind.NewPathfinder(polygons)
 	path := pathfinder.Path(start, destination)
-	fmt.Println(path)
+	for _, pt := range path {
+		fmt.Printf("(%.0f,%.0f) ", pt.X, pt.Y)
+	}
 	// Output:
-	// [(5,5) (10,10) (30,15) (40,15) (45,10)]
+	// (5,5) (10,10) (30,15) (40,15) (45,10)
 }
@@ -0,0 +1,108 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import (
+	"math"
+	"testing"
+)
+
+// FuzzPathfinder feeds arbitrary polygon sets, including self-intersecting
+// and degenerate ones, and arbitrary start and destination points to the
+// Pathfinder and checks that it does not panic.
+func FuzzPathfinder(f *testing.F) {
+	f.Add([]byte{4, 0, 0, 30, 0, 30, 20, 0, 20}, 5.0, 5.0, 25.0, 5.0)
+	f.Add([]byte{4, 0, 0, 40, 0, 40, 40, 0, 40, 4, 20, 10, 30, 20, 20, 30, 10, 20}, 15.0, 10.0, 30.0, 30.0)
+	f.Add([]byte{2, 0, 0, 10, 10, 1, 5, 5, 0}, 1.0, 1.0, 9.0, 9.0)
+	f.Add([]byte{3, 0, 0, 10, 0, 10, 10}, math.NaN(), 1.0, math.Inf(1), 9.0)
+	f.Fuzz(func(t *testing.T, data []byte, sx, sy, dx, dy float64) {
+		polygons := fuzzPolygons(data)
+		start, dest := Pt(sx, sy), Pt(dx, dy)
+		p := NewPathfinder(polygons)
+		p.Path(start, dest)
+		p.Path(dest, start)
+	})
+}
+
+// fuzzPolygons decodes a polygon set from data. Each polygon is encoded as
+// its number of vertices, followed by the x and y coordinates of each vertex.
+// The input is truncated, because the cost of building the visibility graph
+// grows with the cube of the number of vertices.
+func fuzzPolygons(data []byte) [][]Point {
+	data = data[:min(len(data), 200)]
+	var polygons [][]Point
+	for len(data) > 0 {
+		n := int(data[0] % 8)
+		data = data[1:]
+		var ps []Point
+		for ; n > 0 && len(data) >= 2; n-- {
+			ps = append(ps, Pt(float64(data[0]), float64(data[1])))
+			data = data[2:]
+		}
+		polygons = append(polygons, ps)
+	}
+	return polygons
+}
+
+// FuzzPathInside finds paths in a square area with non-overlapping holes of
+// random size and checks that each path that is found stays within the
+// accessible area.
+func FuzzPathInside(f *testing.F) {
+	f.Add([]byte{9, 2, 2, 10, 10}, uint8(10), uint8(10), uint8(250), uint8(250))
+	f.Add([]byte{0, 1, 1, 30, 30, 1, 1, 1, 30, 30, 2, 5, 5, 5, 5}, uint8(0), uint8(0), uint8(255), uint8(0))
+	f.Fuzz(func(t *testing.T, data []byte, sx, sy, dx, dy uint8) {
+		polygons := fuzzHoles(data)
+		p := NewPathfinder(polygons)
+		start, dest := Pt(float64(sx), float64(sy)), Pt(float64(dx), float64(dy))
+		if containmentLevel(p.polygonSet, start) != 1 {
+			// start is on a hole or outside the area.
+			return
+		}
+		path := p.Path(start, dest)
+		if len(path) < 2 {
+			t.Fatalf("no path from %v to %v in %v", start, dest, polygons)
+		}
+		for i := 1; i < len(path); i++ {
+			a, b := p2v(path[i-1]), p2v(path[i])
+			if !inLineOfSight(p.polygonSet, a, b) {
+				t.Fatalf("segment from %v to %v of path %v leaves the area %v",
+					path[i-1], path[i], path, polygons)
+			}
+		}
+	})
+}
+
+// fuzzHoles decodes a square area with rectangular holes from data. The
+// area is divided into a grid of 8x8 cells, and each hole lies inside
+// a different cell, so that holes never overlap. Each hole is encoded as
+// its cell index, followed by its insets from the left, top, right and
+// bottom of the cell. The number of holes is limited to keep the cost of
+// building the visibility graph low.
+func fuzzHoles(data []byte) [][]Point {
+	const size, cell, maxHoles = 256, 32, 12
+	data = data[:min(len(data), 5*maxHoles)]
+	polygons := [][]Point{{Pt(0, 0), Pt(size, 0), Pt(size, size), Pt(0, size)}}
+	used := make(map[byte]bool)
+	for len(data) >= 5 {
+		c := data[0] % 64
+		left := float64(1 + data[1]%15)
+		top := float64(1 + data[2]%15)
+		right := float64(1 + data[3]%15)
+		bottom := float64(1 + data[4]%15)
+		data = data[5:]
+		if used[c] {
+			continue
+		}
+		used[c] = true
+		x, y := float64(c%8)*cell, float64(c/8)*cell
+		polygons = append(polygons, []Point{
+			Pt(x+left, y+top),
+			Pt(x+cell-right, y+top),
+			Pt(x+cell-right, y+cell-bottom),
+			Pt(x+left, y+cell-bottom),
+		})
+	}
+	return polygons
+}
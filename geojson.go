@@ -0,0 +1,103 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// geoJSONObject holds the members of the GeoJSON objects that FromGeoJSON
+// reads. Only the members that belong to the object's type are set.
+type geoJSONObject struct {
+	Type        string          `json:"type"`
+	Features    []geoJSONObject `json:"features"`
+	Geometry    *geoJSONObject  `json:"geometry"`
+	Geometries  []geoJSONObject `json:"geometries"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// FromGeoJSON reads the polygons of a GeoJSON document (RFC 7946), e.g.
+// level data exported from a GIS tool. The document can be a
+// FeatureCollection, a Feature, a GeometryCollection or a single geometry.
+// Each ring of the Polygon and MultiPolygon geometries becomes a polygon of
+// the result, in the order of the document: the exterior ring of a polygon
+// is followed by its interior rings, which are holes by the even-odd
+// nesting of the Pathfinder. The rings keep their vertex order and remain
+// closed, i.e. their last position equals the first one.
+//
+// Z coordinates and any further elements of a position are ignored.
+// Features without geometry are skipped. Other geometry types, such as
+// Point or LineString, result in an error.
+func FromGeoJSON(r io.Reader) ([][]Point, error) {
+	var obj geoJSONObject
+	if err := json.NewDecoder(r).Decode(&obj); err != nil {
+		return nil, fmt.Errorf("could not parse GeoJSON: %w", err)
+	}
+	var polygons [][]Point
+	if err := appendGeoJSON(&polygons, obj); err != nil {
+		return nil, err
+	}
+	return polygons, nil
+}
+
+// appendGeoJSON appends the rings of the polygons of a GeoJSON object to
+// polygons.
+func appendGeoJSON(polygons *[][]Point, obj geoJSONObject) error {
+	switch obj.Type {
+	case "FeatureCollection":
+		for _, f := range obj.Features {
+			if err := appendGeoJSON(polygons, f); err != nil {
+				return err
+			}
+		}
+	case "Feature":
+		if obj.Geometry != nil {
+			return appendGeoJSON(polygons, *obj.Geometry)
+		}
+	case "GeometryCollection":
+		for _, g := range obj.Geometries {
+			if err := appendGeoJSON(polygons, g); err != nil {
+				return err
+			}
+		}
+	case "Polygon":
+		var rings [][][]float64
+		if err := json.Unmarshal(obj.Coordinates, &rings); err != nil {
+			return fmt.Errorf("invalid Polygon coordinates: %w", err)
+		}
+		return appendGeoJSONRings(polygons, rings)
+	case "MultiPolygon":
+		var multi [][][][]float64
+		if err := json.Unmarshal(obj.Coordinates, &multi); err != nil {
+			return fmt.Errorf("invalid MultiPolygon coordinates: %w", err)
+		}
+		for _, rings := range multi {
+			if err := appendGeoJSONRings(polygons, rings); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported GeoJSON type %q", obj.Type)
+	}
+	return nil
+}
+
+// appendGeoJSONRings appends the linear rings of a GeoJSON polygon to
+// polygons.
+func appendGeoJSONRings(polygons *[][]Point, rings [][][]float64) error {
+	for _, ring := range rings {
+		polygon := make([]Point, len(ring))
+		for i, pos := range ring {
+			if len(pos) < 2 {
+				return fmt.Errorf("position with %d coordinates, want at least 2", len(pos))
+			}
+			polygon[i] = Pt(pos[0], pos[1])
+		}
+		*polygons = append(*polygons, polygon)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+func TestFromGeoJSON(t *testing.T) {
+	const doc = `{
+		"type": "FeatureCollection",
+		"features": [
+			{
+				"type": "Feature",
+				"properties": {"name": "hall"},
+				"geometry": {
+					"type": "Polygon",
+					"coordinates": [
+						[[0, 0], [40, 0], [40, 40], [0, 40], [0, 0]],
+						[[10, 10], [10, 30], [30, 30], [30, 10], [10, 10]]
+					]
+				}
+			},
+			{"type": "Feature", "properties": {}, "geometry": null},
+			{
+				"type": "Feature",
+				"geometry": {
+					"type": "MultiPolygon",
+					"coordinates": [
+						[[[50, 0, 7], [60, 0, 7], [60, 10, 7], [50, 0, 7]]],
+						[[[70, 0], [80, 0], [80, 10], [70, 0]]]
+					]
+				}
+			}
+		]
+	}`
+	want := [][]pathfind.Point{
+		{pathfind.Pt(0, 0), pathfind.Pt(40, 0), pathfind.Pt(40, 40), pathfind.Pt(0, 40), pathfind.Pt(0, 0)},
+		{pathfind.Pt(10, 10), pathfind.Pt(10, 30), pathfind.Pt(30, 30), pathfind.Pt(30, 10), pathfind.Pt(10, 10)},
+		{pathfind.Pt(50, 0), pathfind.Pt(60, 0), pathfind.Pt(60, 10), pathfind.Pt(50, 0)},
+		{pathfind.Pt(70, 0), pathfind.Pt(80, 0), pathfind.Pt(80, 10), pathfind.Pt(70, 0)},
+	}
+	got, err := pathfind.FromGeoJSON(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("FromGeoJSON returned error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FromGeoJSON\n got: %v\nwant: %v", got, want)
+	}
+}
+
+func TestFromGeoJSONErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+	}{
+		{name: "invalid JSON", doc: `{"type": "Polygon",`},
+		{name: "unsupported geometry", doc: `{"type": "LineString", "coordinates": [[0, 0], [1, 1]]}`},
+		{name: "unsupported geometry in feature", doc: `{"type": "Feature", "geometry": {"type": "Point", "coordinates": [0, 0]}}`},
+		{name: "invalid coordinates", doc: `{"type": "Polygon", "coordinates": [[0, 0], [1, 1]]}`},
+		{name: "short position", doc: `{"type": "Polygon", "coordinates": [[[0, 0], [1], [1, 1], [0, 0]]]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := pathfind.FromGeoJSON(strings.NewReader(tt.doc)); err == nil {
+				t.Errorf("FromGeoJSON(%s) = %v, want error", tt.doc, got)
+			}
+		})
+	}
+}
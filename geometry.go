@@ -0,0 +1,115 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import "math"
+
+// Centroid returns the centroid (center of mass) of the accessible area,
+// where the areas of the holes are subtracted. It can be used e.g. for
+// default camera framing or spawn placement. If the accessible area is
+// zero, e.g. for degenerate polygons, the center of the bounding rectangle
+// of the polygons is returned instead.
+//
+// Note that the centroid of a non-convex area may lie outside of it.
+func (p *Pathfinder) Centroid() Point {
+	var area, mx, my float64
+	for i, ps := range p.polygons {
+		if len(p.polygonSet[i]) == 0 {
+			continue
+		}
+		a, c := polygonCentroid(openRing(ps))
+		a = math.Abs(a)
+		if p.depths[i]%2 == 1 {
+			a = -a
+		}
+		area += a
+		mx += a * c.X
+		my += a * c.Y
+	}
+	if math.Abs(area) < 1e-12 {
+		b := boundingRect(p.polygons)
+		return Point{X: (b.min.X + b.max.X) / 2, Y: (b.min.Y + b.max.Y) / 2}
+	}
+	return Point{X: mx / area, Y: my / area}
+}
+
+// Bounds returns the bounding rectangle of all polygons of the Pathfinder
+// by its minimum and maximum corner, e.g. to set up a camera or a spatial
+// partitioning of the map. It includes the frame added by
+// WithInvertedNesting and the weighted regions, but not the walls given by
+// WithWalls. For a Pathfinder without polygons both corners are the origin.
+func (p *Pathfinder) Bounds() (min, max Point) {
+	b := boundingRect(p.polygons)
+	return b.min, b.max
+}
+
+// NavigableArea returns the total size of the accessible area, i.e. the
+// areas of all area polygons minus the areas of the holes.
+func (p *Pathfinder) NavigableArea() float64 {
+	var area float64
+	for i := range p.polygons {
+		if len(p.polygonSet[i]) == 0 {
+			continue
+		}
+		if p.depths[i]%2 == 1 {
+			area -= p.polygonArea(i)
+		} else {
+			area += p.polygonArea(i)
+		}
+	}
+	return area
+}
+
+// ReachableFraction returns the fraction of the navigable area that is
+// reachable from start, a value between 0 and 1. The reachable area is the
+// region returned by ReachableRegion. On a fully connected map the fraction
+// is 1; a value well below 1 hints at accidentally walled-off parts of
+// a level. The result is 0 if start is not inside the accessible area or if
+// the navigable area is zero.
+func (p *Pathfinder) ReachableFraction(start Point) float64 {
+	if !start.isFinite() {
+		return 0
+	}
+	areas := p.reachableAreas(start)
+	total := p.NavigableArea()
+	if areas == nil || total <= 0 {
+		return 0
+	}
+	var reachable float64
+	for _, area := range areas {
+		reachable += p.polygonArea(area)
+		for i, parent := range p.parents {
+			if parent == area {
+				reachable -= p.polygonArea(i)
+			}
+		}
+	}
+	return reachable / total
+}
+
+// polygonArea returns the unsigned area of the polygon with index i.
+func (p *Pathfinder) polygonArea(i int) float64 {
+	a, _ := polygonCentroid(openRing(p.polygons[i]))
+	return math.Abs(a)
+}
+
+// polygonCentroid returns the signed area of a polygon, positive for
+// the vertex orientation of the polygons the Pathfinder expects, and its
+// centroid. The centroid of a polygon with zero area is the zero Point.
+func polygonCentroid(ps []Point) (area float64, centroid Point) {
+	var cx, cy float64
+	for i, a := range ps {
+		b := ps[(i+1)%len(ps)]
+		c := cross(a, b)
+		area += c
+		cx += (a.X + b.X) * c
+		cy += (a.Y + b.Y) * c
+	}
+	area /= 2
+	if area == 0 {
+		return 0, Point{}
+	}
+	return area, Point{X: cx / (6 * area), Y: cy / (6 * area)}
+}
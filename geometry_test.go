@@ -0,0 +1,156 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+func TestPathfinderCentroid(t *testing.T) {
+	tests := []struct {
+		name     string
+		polygons [][]pathfind.Point
+		want     pathfind.Point
+	}{
+		{
+			name:     "symmetric hole",
+			polygons: polygonO,
+			want:     pathfind.Pt(20, 20),
+		},
+		{
+			name: "asymmetric hole",
+			polygons: [][]pathfind.Point{
+				{pathfind.Pt(0, 0), pathfind.Pt(10, 0), pathfind.Pt(10, 10), pathfind.Pt(0, 10)},
+				{pathfind.Pt(1, 1), pathfind.Pt(5, 1), pathfind.Pt(5, 9), pathfind.Pt(1, 9)},
+			},
+			want: pathfind.Pt(404.0/68, 5),
+		},
+		{
+			name: "opposite winding",
+			polygons: [][]pathfind.Point{
+				{pathfind.Pt(0, 10), pathfind.Pt(10, 10), pathfind.Pt(10, 0), pathfind.Pt(0, 0)},
+				{pathfind.Pt(1, 1), pathfind.Pt(5, 1), pathfind.Pt(5, 9), pathfind.Pt(1, 9)},
+			},
+			want: pathfind.Pt(404.0/68, 5),
+		},
+		{
+			name: "zero area",
+			polygons: [][]pathfind.Point{
+				{pathfind.Pt(0, 0), pathfind.Pt(10, 4), pathfind.Pt(5, 2)},
+			},
+			want: pathfind.Pt(5, 2),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pathfind.NewPathfinder(tt.polygons).Centroid()
+			if math.Abs(got.X-tt.want.X) > 1e-9 || math.Abs(got.Y-tt.want.Y) > 1e-9 {
+				t.Errorf("Centroid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathfinderBounds(t *testing.T) {
+	tests := []struct {
+		name     string
+		polygons [][]pathfind.Point
+		opts     []pathfind.Option
+		min, max pathfind.Point
+	}{
+		{
+			name:     "single area",
+			polygons: polygonU,
+			min:      pathfind.Pt(0, 0),
+			max:      pathfind.Pt(30, 20),
+		},
+		{
+			name:     "area with hole",
+			polygons: [][]pathfind.Point{polygonO[1], polygonO[0]},
+			min:      pathfind.Pt(0, 0),
+			max:      pathfind.Pt(40, 40),
+		},
+		{
+			name:     "inverted nesting frame",
+			polygons: [][]pathfind.Point{polygonO[1]},
+			opts:     []pathfind.Option{pathfind.WithInvertedNesting()},
+			min:      pathfind.Pt(-10, -10),
+			max:      pathfind.Pt(50, 50),
+		},
+		{
+			name:     "no polygons",
+			polygons: nil,
+			min:      pathfind.Pt(0, 0),
+			max:      pathfind.Pt(0, 0),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pathfinder := pathfind.NewPathfinder(tt.polygons, tt.opts...)
+			gotMin, gotMax := pathfinder.Bounds()
+			if gotMin != tt.min || gotMax != tt.max {
+				t.Errorf("Bounds() = %v, %v, want %v, %v", gotMin, gotMax, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestPathfinderReachableFraction(t *testing.T) {
+	pathfinder := pathfind.NewPathfinder(polygonIslands)
+	if got, want := pathfinder.NavigableArea(), 2100.0; got != want {
+		t.Errorf("NavigableArea() = %g, want %g", got, want)
+	}
+	tests := []struct {
+		name  string
+		start pathfind.Point
+		want  float64
+	}{
+		{
+			name:  "left island with hole",
+			start: pathfind.Pt(2, 2),
+			want:  1000.0 / 2100,
+		},
+		{
+			name:  "right island",
+			start: pathfind.Pt(42, 20),
+			want:  700.0 / 2100,
+		},
+		{
+			name:  "island inside hole",
+			start: pathfind.Pt(60, 20),
+			want:  400.0 / 2100,
+		},
+		{
+			name:  "inside hole",
+			start: pathfind.Pt(10, 10),
+			want:  0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pathfinder.ReachableFraction(tt.start)
+			if math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("ReachableFraction(%v) = %g, want %g", tt.start, got, tt.want)
+			}
+		})
+	}
+	if got := pathfind.NewPathfinder(polygonSquare).ReachableFraction(pathfind.Pt(1, 1)); got != 1 {
+		t.Errorf("ReachableFraction on connected map = %g, want 1", got)
+	}
+}
+
+func TestPathfinderReachableFractionLinked(t *testing.T) {
+	pathfinder := pathfind.NewPathfinder(polygonIslands)
+	// A jump link between corners of the holes of both islands.
+	pathfinder.Graph().Link(pathfind.Pt(25, 15), pathfind.Pt(45, 35))
+	for _, start := range []pathfind.Point{pathfind.Pt(2, 2), pathfind.Pt(42, 20)} {
+		if got, want := pathfinder.ReachableFraction(start), 1700.0/2100; math.Abs(got-want) > 1e-12 {
+			t.Errorf("ReachableFraction(%v) = %g, want %g", start, got, want)
+		}
+	}
+}
@@ -2,7 +2,4 @@ module github.com/fzipp/pathfind
 
 go 1.23.0
 
-require (
-	github.com/fzipp/astar v0.3.0
-	github.com/fzipp/geom v1.0.0
-)
+require github.com/fzipp/geom v1.0.0
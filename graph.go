@@ -5,6 +5,7 @@
 package pathfind
 
 import (
+	"cmp"
 	"iter"
 	"slices"
 )
@@ -19,7 +20,108 @@ func (g graph[Node]) link(a, b Node) graph[Node] {
 }
 
 // Neighbours returns the neighbour nodes of node n in the graph.
-// This method makes graph[Node] implement the astar.Graph[Node] interface.
 func (g graph[Node]) Neighbours(n Node) iter.Seq[Node] {
 	return slices.Values(g[n])
 }
+
+// sortAdjacency sorts the neighbours of each node of g with comparePoints,
+// so that the order of the edges does not depend on the iteration order of
+// maps and spatial indexes, e.g. for reproducible path searches.
+func sortAdjacency(g graph[Point]) {
+	for _, adj := range g {
+		slices.SortFunc(adj, comparePoints)
+	}
+}
+
+// comparePoints orders points by their X coordinate and then by their Y
+// coordinate.
+func comparePoints(a, b Point) int {
+	if c := cmp.Compare(a.X, b.X); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.Y, b.Y)
+}
+
+// A Graph gives access to the cached visibility graph of a Pathfinder, the
+// graph of the polygon corners at which paths can turn, with an edge
+// between each pair of corners that are in line of sight of each other.
+// Changes to the graph affect all subsequent path queries, e.g. to add
+// jump links between distant points or to remove unwanted connections.
+// The cost of an edge is that of the straight line between its nodes as
+// for Path, i.e. its length, or its cost with weighted regions or
+// WithMetric.
+// Changes are lost when the polygon set changes, e.g. by AddPolygon or
+// RemovePolygon.
+//
+// A Graph must not be modified concurrently with path queries or other
+// modifications of the Pathfinder.
+type Graph struct {
+	p *Pathfinder
+}
+
+// Graph returns the live visibility graph of the Pathfinder.
+func (p *Pathfinder) Graph() *Graph {
+	return &Graph{p: p}
+}
+
+// Link adds a directed edge from node a to node b, so paths can lead from
+// a to b. To link the nodes in both directions, call Link(b, a) as well.
+// Nodes that are not yet part of the graph are added to it. Such a node is
+// only connected by the edges added for it with Link, and to the start and
+// destination of a path query if they are in line of sight of it.
+//
+// Path only considers the nodes near the straight line from start to dest,
+// within the distance between start and dest, so a link is only used if
+// both of its nodes are in this region.
+func (g *Graph) Link(a, b Point) {
+	p := g.p
+	for _, n := range []Point{a, b} {
+		if _, ok := p.cachedGraph[n]; !ok && !slices.Contains(p.concaveVertices, n) {
+			p.cachedGraph[n] = nil
+			if !p.index.insert(n) {
+				// The node lies outside of the bounds of the quad tree.
+				p.index = buildIndex(p.polygons, p.graphNodes(), p.opts)
+			}
+		}
+	}
+	// The neighbours are kept sorted like those of the graph built by
+	// the Pathfinder.
+	if i, found := slices.BinarySearchFunc(p.cachedGraph[a], b, comparePoints); !found {
+		p.cachedGraph[a] = slices.Insert(p.cachedGraph[a], i, b)
+	}
+	p.components = nil
+	p.edited = true
+	p.generation++
+}
+
+// graphNodes returns the nodes of the spatial index: the concave vertices
+// and the nodes added with Link.
+func (p *Pathfinder) graphNodes() []Point {
+	nodes := slices.Clone(p.concaveVertices)
+	concave := make(map[Point]bool, len(nodes))
+	for _, v := range nodes {
+		concave[v] = true
+	}
+	for v := range p.cachedGraph {
+		if !concave[v] {
+			nodes = append(nodes, v)
+		}
+	}
+	return nodes
+}
+
+// Unlink removes the directed edge from node a to node b, if any.
+func (g *Graph) Unlink(a, b Point) {
+	g.p.cachedGraph[a] = slices.DeleteFunc(g.p.cachedGraph[a], func(n Point) bool {
+		return n == b
+	})
+	g.p.components = nil
+	g.p.edited = true
+	g.p.generation++
+}
+
+// Neighbors returns the nodes that node a has an edge to, sorted by their X
+// and then their Y coordinate.
+func (g *Graph) Neighbors(a Point) []Point {
+	return slices.Clone(g.p.cachedGraph[a])
+}
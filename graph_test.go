@@ -5,6 +5,7 @@
 package pathfind
 
 import (
+	"math/rand/v2"
 	"reflect"
 	"slices"
 	"testing"
@@ -33,3 +34,57 @@ func TestGraphNeighbours(t *testing.T) {
 		}
 	}
 }
+
+func TestIncrementalVisibilityGraph(t *testing.T) {
+	polygons := [][]Point{{Pt(0, 0), Pt(50, 0), Pt(50, 50), Pt(0, 50)}}
+	for i := range 4 {
+		for j := range 4 {
+			x, y := float64(12*i+3), float64(12*j+3)
+			polygons = append(polygons, []Point{Pt(x, y), Pt(x+5, y), Pt(x+5, y+5), Pt(x, y+5)})
+		}
+	}
+	crate := []Point{Pt(21, 21), Pt(25, 21), Pt(25, 25), Pt(21, 25)}
+
+	// A hole around four of the holes, which become islands in it.
+	room := []Point{Pt(1, 1), Pt(22, 1), Pt(22, 22), Pt(1, 22)}
+
+	p := NewPathfinder(polygons)
+	check := func(op string, polygons [][]Point) {
+		t.Helper()
+		want := NewPathfinder(polygons)
+		if !reflect.DeepEqual(p.cachedGraph, want.cachedGraph) {
+			t.Errorf("graph after %s differs from rebuilt graph", op)
+		}
+		if !reflect.DeepEqual(p.parents, want.parents) || !reflect.DeepEqual(p.depths, want.depths) {
+			t.Errorf("nesting after %s: parents %v, depths %v, want %v, %v",
+				op, p.parents, p.depths, want.parents, want.depths)
+		}
+	}
+	p.AddPolygon(crate)
+	check("AddPolygon", append(slices.Clone(polygons), crate))
+	p.RemovePolygon(len(polygons))
+	check("RemovePolygon", polygons)
+	p.AddPolygon(room)
+	check("AddPolygon around holes", append(slices.Clone(polygons), room))
+	p.RemovePolygon(len(polygons))
+	check("RemovePolygon around holes", polygons)
+	p.RemovePolygon(1)
+	check("RemovePolygon of a hole", slices.Delete(slices.Clone(polygons), 1, 2))
+}
+
+func TestShadowRect(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(3, 4))
+	bounds := rect{min: Pt(0, 0), max: Pt(100, 100)}
+	random := func() Point { return Pt(rnd.Float64()*100, rnd.Float64()*100) }
+	for range 200 {
+		a := random()
+		r := queryRect(random(), random(), 0)
+		s := shadowRect(a, r, bounds)
+		for range 200 {
+			b := random()
+			if segmentMeetsRect(a, b, r) && !s.contains(b) {
+				t.Fatalf("shadowRect(%v, %v) = %v does not contain %v", a, r, s, b)
+			}
+		}
+	}
+}
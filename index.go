@@ -47,14 +47,28 @@ func queryRect(a, b Point, radius float64) rect {
 	return rect{min: Point{minX, minY}, max: Point{maxX, maxY}}
 }
 
+// spatialIndex is implemented by the point indexes that can be used to look
+// up the concave vertices near a query.
+type spatialIndex interface {
+	insert(p Point) bool
+	remove(p Point) bool
+	query(r rect, found *[]Point)
+}
+
 type quadTree struct {
 	boundary       rect
 	capacity       int
+	depth          int
 	points         []Point
 	divided        bool
 	nw, ne, sw, se *quadTree
 }
 
+// maxQuadTreeDepth limits the subdivision of a quad tree. Without a limit,
+// more than capacity points at the same position would be subdivided
+// forever.
+const maxQuadTreeDepth = 32
+
 func newQuadTree(b rect, capacity int) *quadTree {
 	return &quadTree{boundary: b, capacity: capacity}
 }
@@ -63,7 +77,7 @@ func (qt *quadTree) insert(p Point) bool {
 	if !qt.boundary.contains(p) {
 		return false
 	}
-	if len(qt.points) < qt.capacity && !qt.divided {
+	if !qt.divided && (len(qt.points) < qt.capacity || qt.depth >= maxQuadTreeDepth) {
 		qt.points = append(qt.points, p)
 		return true
 	}
@@ -76,6 +90,22 @@ func (qt *quadTree) insert(p Point) bool {
 	return false
 }
 
+func (qt *quadTree) remove(p Point) bool {
+	if !qt.boundary.contains(p) {
+		return false
+	}
+	if qt.divided {
+		return qt.nw.remove(p) || qt.ne.remove(p) || qt.sw.remove(p) || qt.se.remove(p)
+	}
+	for i, q := range qt.points {
+		if q == p {
+			qt.points = append(qt.points[:i], qt.points[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
 func (qt *quadTree) subdivide() {
 	b := qt.boundary
 	midX := (b.min.X + b.max.X) / 2
@@ -84,12 +114,14 @@ func (qt *quadTree) subdivide() {
 	qt.ne = newQuadTree(rect{Point{midX, b.min.Y}, Point{b.max.X, midY}}, qt.capacity)
 	qt.sw = newQuadTree(rect{Point{b.min.X, midY}, Point{midX, b.max.Y}}, qt.capacity)
 	qt.se = newQuadTree(rect{Point{midX, midY}, b.max}, qt.capacity)
+	for _, child := range []*quadTree{qt.nw, qt.ne, qt.sw, qt.se} {
+		child.depth = qt.depth + 1
+	}
 	qt.divided = true
 	for _, p := range qt.points {
-		qt.nw.insert(p)
-		qt.ne.insert(p)
-		qt.sw.insert(p)
-		qt.se.insert(p)
+		// A point on the border between two quadrants is inserted only
+		// once, so that queries do not report it twice.
+		_ = qt.nw.insert(p) || qt.ne.insert(p) || qt.sw.insert(p) || qt.se.insert(p)
 	}
 	qt.points = nil
 }
@@ -112,8 +144,76 @@ func (qt *quadTree) query(r rect, found *[]Point) {
 	}
 }
 
-func (qt *quadTree) rangeSearch(r rect) []Point {
-	var pts []Point
-	qt.query(r, &pts)
-	return pts
+// spatialHash is a spatialIndex that sorts points into the cells of an
+// unbounded uniform grid. Inserting and removing points takes amortized
+// constant time, which suits indexes that change frequently.
+type spatialHash struct {
+	cellSize float64
+	cells    map[gridCell][]Point
+}
+
+type gridCell struct {
+	x, y int
+}
+
+func newSpatialHash(cellSize float64) *spatialHash {
+	return &spatialHash{cellSize: cellSize, cells: make(map[gridCell][]Point)}
+}
+
+func (h *spatialHash) cellOf(p Point) gridCell {
+	return gridCell{
+		x: int(math.Floor(p.X / h.cellSize)),
+		y: int(math.Floor(p.Y / h.cellSize)),
+	}
+}
+
+func (h *spatialHash) insert(p Point) bool {
+	c := h.cellOf(p)
+	h.cells[c] = append(h.cells[c], p)
+	return true
+}
+
+func (h *spatialHash) remove(p Point) bool {
+	c := h.cellOf(p)
+	pts := h.cells[c]
+	for i, q := range pts {
+		if q == p {
+			last := len(pts) - 1
+			pts[i] = pts[last]
+			if last == 0 {
+				delete(h.cells, c)
+			} else {
+				h.cells[c] = pts[:last]
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func (h *spatialHash) query(r rect, found *[]Point) {
+	lo, hi := h.cellOf(r.min), h.cellOf(r.max)
+	cols, rows := float64(hi.x-lo.x+1), float64(hi.y-lo.y+1)
+	if cols*rows > float64(len(h.cells)) {
+		// Visiting the occupied cells is cheaper than scanning the range.
+		for c, pts := range h.cells {
+			if c.x >= lo.x && c.x <= hi.x && c.y >= lo.y && c.y <= hi.y {
+				appendContained(found, r, pts)
+			}
+		}
+		return
+	}
+	for x := lo.x; x <= hi.x; x++ {
+		for y := lo.y; y <= hi.y; y++ {
+			appendContained(found, r, h.cells[gridCell{x, y}])
+		}
+	}
+}
+
+func appendContained(found *[]Point, r rect, pts []Point) {
+	for _, p := range pts {
+		if r.contains(p) {
+			*found = append(*found, p)
+		}
+	}
 }
@@ -0,0 +1,144 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestSpatialHashQueryMatchesQuadTree(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(1, 2))
+	box := rect{min: Pt(-100, -50), max: Pt(300, 250)}
+	qt := newQuadTree(box, 8)
+	sh := newSpatialHash(17)
+	var pts []Point
+	for range 500 {
+		p := Pt(box.min.X+rnd.Float64()*400, box.min.Y+rnd.Float64()*300)
+		pts = append(pts, p)
+		qt.insert(p)
+		sh.insert(p)
+	}
+	for i, p := range pts {
+		if i%3 != 0 {
+			continue
+		}
+		if !qt.remove(p) {
+			t.Fatalf("quadTree.remove(%v) = false, want true", p)
+		}
+		if !sh.remove(p) {
+			t.Fatalf("spatialHash.remove(%v) = false, want true", p)
+		}
+	}
+	if sh.remove(Pt(1000, 1000)) {
+		t.Errorf("spatialHash.remove of missing point = true, want false")
+	}
+
+	queries := []rect{
+		box,
+		{min: Pt(0, 0), max: Pt(50, 50)},
+		{min: Pt(-200, -200), max: Pt(-150, -150)},
+		{min: Pt(120.5, 10), max: Pt(121, 240)},
+		{min: Pt(-1000, -1000), max: Pt(1000, 1000)},
+	}
+	for range 50 {
+		a := Pt(box.min.X+rnd.Float64()*400, box.min.Y+rnd.Float64()*300)
+		b := Pt(box.min.X+rnd.Float64()*400, box.min.Y+rnd.Float64()*300)
+		queries = append(queries, queryRect(a, b, rnd.Float64()*20))
+	}
+	for _, r := range queries {
+		var want, got []Point
+		qt.query(r, &want)
+		sh.query(r, &got)
+		sortPoints(want)
+		sortPoints(got)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("query(%v): spatial hash found %d points, quad tree %d",
+				r, len(got), len(want))
+		}
+	}
+}
+
+func TestQuadTreeDegeneratePoints(t *testing.T) {
+	qt := newQuadTree(rect{min: Pt(0, 0), max: Pt(10, 10)}, 2)
+	// A point on the border between quadrants, followed by more points at
+	// the same position than fit into a node.
+	qt.insert(Pt(5, 5))
+	want := []Point{Pt(5, 5)}
+	for range 5 {
+		qt.insert(Pt(3, 3))
+		want = append(want, Pt(3, 3))
+	}
+	var got []Point
+	qt.query(rect{min: Pt(0, 0), max: Pt(10, 10)}, &got)
+	sortPoints(got)
+	sortPoints(want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("query found %v, want %v", got, want)
+	}
+}
+
+func TestIndexUpdatedWithPolygonSet(t *testing.T) {
+	square := []Point{Pt(0, 0), Pt(40, 0), Pt(40, 40), Pt(0, 40)}
+	hole := []Point{Pt(10, 10), Pt(20, 10), Pt(20, 20), Pt(10, 20)}
+	// An L-shaped area with a concave vertex outside of the bounds of the
+	// quad tree.
+	outside := []Point{Pt(50, 50), Pt(90, 50), Pt(90, 70), Pt(70, 70), Pt(70, 90), Pt(50, 90)}
+	all := rect{min: Pt(-1000, -1000), max: Pt(1000, 1000)}
+	for _, tt := range []struct {
+		name string
+		opts []Option
+	}{
+		{name: "quad tree"},
+		{name: "spatial hash", opts: []Option{WithSpatialHash(10)}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPathfinder([][]Point{square}, tt.opts...)
+			idx := p.index
+			check := func(op string) {
+				var got []Point
+				p.index.query(all, &got)
+				want := slices.Clone(p.concaveVertices)
+				sortPoints(got)
+				sortPoints(want)
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("after %s: index contains %v, want %v", op, got, want)
+				}
+			}
+			p.AddPolygon(hole)
+			check("AddPolygon")
+			p.RemovePolygon(1)
+			check("RemovePolygon")
+			if p.index != idx {
+				t.Errorf("index was rebuilt, want it updated")
+			}
+			p.AddPolygon(outside)
+			check("AddPolygon outside")
+		})
+	}
+}
+
+func TestIndexContainsLinkedNodes(t *testing.T) {
+	square := []Point{Pt(0, 0), Pt(40, 0), Pt(40, 40), Pt(0, 40)}
+	all := rect{min: Pt(-1000, -1000), max: Pt(1000, 1000)}
+	p := NewPathfinder([][]Point{square})
+	// The second node lies outside of the bounds of the quad tree.
+	p.Graph().Link(Pt(5, 5), Pt(60, 60))
+	var got []Point
+	p.index.query(all, &got)
+	sortPoints(got)
+	if want := []Point{Pt(5, 5), Pt(60, 60)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("index contains %v, want %v", got, want)
+	}
+}
+
+func sortPoints(pts []Point) {
+	slices.SortFunc(pts, func(a, b Point) int {
+		return cmp.Or(cmp.Compare(a.X, b.X), cmp.Compare(a.Y, b.Y))
+	})
+}
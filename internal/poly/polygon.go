@@ -102,8 +102,11 @@ type match struct {
 }
 
 // ClosestPt returns the closest point to point pt on the outline of
-// polygon p.
+// polygon p. If p has no vertices, pt is returned.
 func (p Polygon) ClosestPt(pt geom.Vec2) geom.Vec2 {
+	if len(p) == 0 {
+		return pt
+	}
 	var best match
 	best.pt = p[0]
 	best.dist = best.pt.SqDist(pt)
@@ -4,7 +4,11 @@
 
 package poly
 
-import "github.com/fzipp/geom"
+import (
+	"math"
+
+	"github.com/fzipp/geom"
+)
 
 // A PolygonSet represents multiple polygons.
 type PolygonSet []Polygon
@@ -21,24 +25,36 @@ func ParsePolygons(coords []string) PolygonSet {
 }
 
 // Contains checks if point pt lies inside the boundaries of a polygon set.
-// Overlapping polygons can form holes and islands.
+// Overlapping polygons can form holes and islands. Points on the outline of
+// any polygon are considered inside, regardless of the order of the
+// polygons in the set.
 func (ps PolygonSet) Contains(pt geom.Vec2) bool {
 	in := false
 	for _, p := range ps {
-		if p.Contains(pt, !in) {
+		if p.Contains(pt, false) {
 			in = !in
 		}
 	}
-	return in
+	if in {
+		return true
+	}
+	for _, p := range ps {
+		if !p.Contains(pt, false) && p.Contains(pt, true) {
+			return true
+		}
+	}
+	return false
 }
 
 // ClosestPt returns the closest point to point pt on any of the outlines of
-// polygon set ps.
+// polygon set ps. Polygons without vertices are skipped. If there are no
+// polygon outlines at all, pt is returned.
 func (ps PolygonSet) ClosestPt(pt geom.Vec2) geom.Vec2 {
-	var best match
-	best.pt = ps[0].ClosestPt(pt)
-	best.dist = best.pt.SqDist(pt)
+	best := match{pt: pt, dist: float32(math.Inf(1))}
 	for _, p := range ps {
+		if len(p) == 0 {
+			continue
+		}
 		var current match
 		current.pt = p.ClosestPt(pt)
 		current.dist = current.pt.SqDist(pt)
@@ -128,6 +128,8 @@ func TestPolygonSetContains(t *testing.T) {
 		{twoDisjointSquares, geom.V2(5, 5), true},
 		{twoDisjointSquares, geom.V2(25, 5), true},
 		{twoDisjointSquares, geom.V2(15, 5), false},
+		{twoSquaresNested, geom.V2(10, 0), true},
+		{poly.PolygonSet{twoSquaresNested[1], twoSquaresNested[0]}, geom.V2(10, 0), true},
 	}
 	for _, tt := range tests {
 		got := tt.polygonSet.Contains(tt.pt)
@@ -0,0 +1,97 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import "slices"
+
+// KPaths finds up to k distinct shortest paths from start to dest, e.g. to
+// choose a patrol route that avoids the route of a teammate. The paths are
+// ordered by their length, the first one being the path found by Path. The
+// search uses Yen's algorithm on the visibility graph: each further path
+// deviates from one of the paths found before at one of its nodes. Paths
+// never visit a node twice. With weighted regions or WithMetric, the paths
+// are ordered by their cost like for Path instead.
+//
+// KPaths returns fewer than k paths if there are no more distinct paths,
+// and nil if no path exists or if k is not positive. The destination is
+// clamped like for Path.
+func (p *Pathfinder) KPaths(start, dest Point, k int) [][]Point {
+	if k <= 0 || !allFinite(start, dest) {
+		return nil
+	}
+	dest = p.clampDest(start, dest)
+	if containmentLevel(p.polygonSet, start) != containmentLevel(p.polygonSet, dest) {
+		return nil
+	}
+	vis := copyGraph(p.cachedGraph)
+	p.linkIntoGraph(vis, start, nil)
+	p.linkIntoGraph(vis, dest, []Point{start})
+
+	cost, heuristic := p.travelCost()
+	var search aStar
+	first := search.findPath(vis, start, dest, cost, heuristic)
+	if first == nil {
+		return nil
+	}
+	paths := [][]Point{first}
+	var candidates [][]Point
+	for len(paths) < k {
+		last := paths[len(paths)-1]
+		for i := range len(last) - 1 {
+			root := last[:i+1]
+			removedEdges := make(map[[2]Point]bool)
+			for _, path := range paths {
+				if len(path) > i+1 && slices.Equal(path[:i+1], root) {
+					removedEdges[[2]Point{path[i], path[i+1]}] = true
+				}
+			}
+			removedNodes := make(map[Point]bool, i)
+			for _, n := range root[:i] {
+				removedNodes[n] = true
+			}
+			g := make(graph[Point], len(vis))
+			for a, adj := range vis {
+				if removedNodes[a] {
+					continue
+				}
+				for _, b := range adj {
+					if !removedNodes[b] && !removedEdges[[2]Point{a, b}] {
+						g.link(a, b)
+					}
+				}
+			}
+			spur := search.findPath(g, last[i], dest, cost, heuristic)
+			if spur == nil {
+				continue
+			}
+			candidate := append(slices.Clone(root[:i]), spur...)
+			if !containsPath(paths, candidate) && !containsPath(candidates, candidate) {
+				candidates = append(candidates, candidate)
+			}
+		}
+		if len(candidates) == 0 {
+			break
+		}
+		best := 0
+		for i, c := range candidates {
+			if pathCost(c, cost) < pathCost(candidates[best], cost) {
+				best = i
+			}
+		}
+		paths = append(paths, candidates[best])
+		candidates = slices.Delete(candidates, best, best+1)
+	}
+	for _, path := range paths {
+		offsetPath(p.polygonSet, path, p.opts.margin)
+	}
+	return paths
+}
+
+// containsPath reports whether paths contains a path equal to path.
+func containsPath(paths [][]Point, path []Point) bool {
+	return slices.ContainsFunc(paths, func(q []Point) bool {
+		return slices.Equal(q, path)
+	})
+}
@@ -0,0 +1,61 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+func TestPathfinderKPaths(t *testing.T) {
+	pathfinder := pathfind.NewPathfinder(polygonTwoPassages)
+	start, dest := pathfind.Pt(20, 10), pathfind.Pt(80, 10)
+
+	got := pathfinder.KPaths(start, dest, 2)
+	want := [][]pathfind.Point{
+		{start, pathfind.Pt(40, 5), pathfind.Pt(60, 5), dest},
+		{start, pathfind.Pt(40, 50), pathfind.Pt(60, 50), dest},
+	}
+	if len(got) != len(want) || !pathNearEq(got[0], want[0]) || !pathNearEq(got[1], want[1]) {
+		t.Errorf("KPaths(%v, %v, 2)\n got: %v\nwant: %v", start, dest, got, want)
+	}
+
+	const k = 20
+	got = pathfinder.KPaths(start, dest, k)
+	if len(got) < 2 || len(got) >= k {
+		t.Errorf("KPaths(%v, %v, %d) returned %d paths, want between 2 and %d", start, dest, k, len(got), k-1)
+	}
+	prev := 0.0
+	for i, path := range got {
+		if l := pathLength(path); l < prev-1e-9 {
+			t.Errorf("path %d has length %g, shorter than the previous path with %g", i, l, prev)
+		} else {
+			prev = l
+		}
+		for j := range i {
+			if slices.Equal(got[j], path) {
+				t.Errorf("path %d equals path %d: %v", i, j, path)
+			}
+		}
+	}
+
+	if got := pathfinder.KPaths(pathfind.Pt(50, 20), dest, 3); got != nil {
+		t.Errorf("KPaths from inside hole = %v, want nil", got)
+	}
+	if got := pathfinder.KPaths(start, dest, 0); got != nil {
+		t.Errorf("KPaths(%v, %v, 0) = %v, want nil", start, dest, got)
+	}
+}
+
+func pathLength(path []pathfind.Point) float64 {
+	var l float64
+	for i := 1; i < len(path); i++ {
+		l += math.Hypot(path[i].X-path[i-1].X, path[i].Y-path[i-1].Y)
+	}
+	return l
+}
@@ -0,0 +1,323 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import (
+	"slices"
+
+	"github.com/fzipp/pathfind/internal/poly"
+)
+
+// PathAvoidingRegion finds the shortest path from start to dest like Path,
+// but additionally treats region as a hole that the path must not enter.
+// The region only applies to this query; the Pathfinder is not modified.
+// If start or dest lie inside the region, they are moved to the nearest
+// point just outside of it.
+func (p *Pathfinder) PathAvoidingRegion(start, dest Point, region []Point) []Point {
+	return p.pathAroundHoles(start, dest, poly.PolygonSet{ps2vs(region)})
+}
+
+// PathAvoiding finds the shortest path from start to dest like Path, but
+// additionally treats each of the blockers as a hole that the path must not
+// enter, e.g. for moving units that temporarily block others. The blockers
+// only apply to this call: visibility edges crossing them are pruned from a
+// copy of the cached visibility graph, which itself is not modified, so
+// concurrent calls with different blockers do not interfere. Blockers with
+// fewer than three vertices are ignored. If start or dest lie inside a
+// blocker, they are moved to the nearest point just outside of it.
+func (p *Pathfinder) PathAvoiding(start, dest Point, blockers [][]Point) []Point {
+	holes := make(poly.PolygonSet, len(blockers))
+	for i, b := range blockers {
+		holes[i] = ps2vs(b)
+	}
+	return p.pathAroundHoles(start, dest, holes)
+}
+
+// pathAroundHoles finds the shortest path from start to dest in the polygon
+// set of the Pathfinder extended by additional holes. Points inside one of
+// the additional holes are moved out of it first. The cached visibility graph
+// is not modified: edges blocked by the holes are skipped while copying it,
+// and the turning points of the holes are linked in as additional nodes.
+// As for Path, dest is clamped with clampQueryDest, the path keeps the
+// clearance, also to the additional holes, its cost is measured with the
+// weights and the metric, and it is simplified with WithSimplification.
+func (p *Pathfinder) pathAroundHoles(start, dest Point, holes poly.PolygonSet) []Point {
+	if !allFinite(start, dest) {
+		return nil
+	}
+	holes = normalizedHoles(holes)
+	ps := append(slices.Clip(p.polygonSet), holes...)
+
+	dest, ok := p.clampQueryDest(start, dest)
+	if !ok {
+		return nil
+	}
+	start = moveOutOfHoles(ps, holes, start, p.opts.margin)
+	dest = moveOutOfHoles(ps, holes, dest, p.opts.margin)
+	if containmentLevel(ps, start) != containmentLevel(ps, dest) {
+		return nil
+	}
+	r := p.opts.clearance
+	var walls [][2]Point
+	if r > 0 {
+		walls = slices.Clip(p.walls)
+		for _, h := range holes {
+			for j := range h {
+				e := h.Edge(j)
+				walls = append(walls, [2]Point{v2p(e.A), v2p(e.B)})
+			}
+		}
+		var okStart, okDest bool
+		start, okStart = keepClear(ps, walls, start, r, p.opts.margin)
+		dest, okDest = keepClear(ps, walls, dest, r, p.opts.margin)
+		if !okStart || !okDest {
+			return nil
+		}
+	}
+	clear := func(a, b Point) bool {
+		return r <= 0 || wallDist(walls, a, b) >= r-clearanceEps
+	}
+	visible := func(a, b Point) bool {
+		return inLineOfSight(ps, p2v(a), p2v(b)) && !crossesWall(p.wallRings, p2v(a), p2v(b)) && clear(a, b)
+	}
+	if p.straightIsCheapest() && visible(start, dest) {
+		return p.finishPath(ps, []Point{start, dest}, visible)
+	}
+
+	var nodes []Point
+	keep := make(map[Point]bool)
+	for _, v := range p.concaveVertices {
+		if !insideAny(holes, v) && clear(v, v) {
+			nodes = append(nodes, v)
+			keep[v] = true
+		}
+	}
+	vis := make(graph[Point])
+	for a, adj := range p.cachedGraph {
+		if !keep[a] {
+			continue
+		}
+		for _, b := range adj {
+			if keep[b] && !blockedBy(holes, a, b) && clear(a, b) {
+				vis.link(a, b)
+			}
+		}
+	}
+	var extra []Point
+	for _, h := range holes {
+		for _, v := range holeTurningPoints(h, r) {
+			if p.polygonSet.Contains(p2v(v)) && !insideAny(holes, v) && clear(v, v) {
+				extra = append(extra, v)
+			}
+		}
+	}
+	for _, a := range extra {
+		for _, b := range nodes {
+			if visible(a, b) {
+				vis.link(a, b).link(b, a)
+			}
+		}
+		nodes = append(nodes, a)
+	}
+	for _, b := range append(nodes, dest) {
+		if visible(start, b) {
+			vis.link(start, b).link(b, start)
+		}
+	}
+	for _, b := range nodes {
+		if visible(dest, b) {
+			vis.link(dest, b).link(b, dest)
+		}
+	}
+
+	sortAdjacency(vis)
+
+	cost, heuristic := p.travelCost()
+	var search aStar
+	return p.finishPath(ps, search.findPath(vis, start, dest, cost, heuristic), visible)
+}
+
+// holeTurningPoints returns the points at which paths turn around the
+// additional hole h: its convex vertices or, with a clearance r, the
+// corresponding mitred vertices.
+func holeTurningPoints(h poly.Polygon, r float64) []Point {
+	if r <= 0 {
+		return verticesOfType(h, convex)
+	}
+	orient := h.Orientation()
+	var indices []int
+	for j := range h {
+		if !isReflexAt(h, j, orient) {
+			indices = append(indices, j)
+		}
+	}
+	return mitredVertices(convert(h, v2p), indices, r)
+}
+
+// PathWithAgents finds the shortest path from start to dest like Path, but
+// avoids the circles with the given radius around the positions of other
+// agents. Visibility edges that pass closer than radius to an agent are
+// pruned for this query only. Agents do not add new corners to the graph,
+// so a path can only detour around them via the existing polygon corners.
+// Agents whose circle contains start or dest are ignored, so an agent's
+// own position may be passed as well.
+func (p *Pathfinder) PathWithAgents(start, dest Point, agents []Point, radius float64) []Point {
+	start, dest, ok := p.prepareQuery(start, dest)
+	if !ok {
+		return nil
+	}
+	var blocking []Point
+	for _, a := range agents {
+		if nodeDist(a, start) >= radius && nodeDist(a, dest) >= radius {
+			blocking = append(blocking, a)
+		}
+	}
+	return p.pathWithEdgeFilter(start, dest, func(a, b Point) bool {
+		for _, agent := range blocking {
+			if segmentPointDist(a, b, agent) < radius {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// PathMonotone finds the shortest path from start to dest like Path, but
+// never moves backward relative to the direction from start to dest by
+// more than tolerance per path segment, e.g. for tower-defense creeps that
+// should not take loops around complex obstacles. Visibility edges whose
+// projection onto the axis from start to the clamped dest decreases by
+// more than tolerance are not used. PathMonotone returns nil if no such
+// path exists.
+func (p *Pathfinder) PathMonotone(start, dest Point, tolerance float64) []Point {
+	start, dest, ok := p.prepareQuery(start, dest)
+	if !ok {
+		return nil
+	}
+	axis := dest.Sub(start)
+	l := nodeDist(start, dest)
+	if l == 0 {
+		return p.pathWithEdgeFilter(start, dest, func(a, b Point) bool { return true })
+	}
+	return p.pathWithEdgeFilter(start, dest, func(a, b Point) bool {
+		d := b.Sub(a)
+		return (d.X*axis.X+d.Y*axis.Y)/l >= -tolerance
+	})
+}
+
+// pathWithEdgeFilter finds the shortest path from start to dest like Path,
+// but only uses the visibility edges for which allowed returns true. The
+// edges are directed: allowed(a, b) decides whether the path may lead from
+// a to b. Unlike Path, it does not prepare start and dest, which must have
+// been prepared by the caller with prepareQuery.
+// The graph is built from the complete cached graph for this query, so the
+// cached graph is not modified.
+func (p *Pathfinder) pathWithEdgeFilter(start, dest Point, allowed func(a, b Point) bool) []Point {
+	visible := func(a, b Point) bool {
+		return p.visible(a, b) && allowed(a, b)
+	}
+	if p.straightIsCheapest() && visible(start, dest) {
+		return p.finishPath(p.polygonSet, []Point{start, dest}, visible)
+	}
+	vis := make(graph[Point])
+	for a, adj := range p.cachedGraph {
+		for _, b := range adj {
+			if allowed(a, b) {
+				vis.link(a, b)
+			}
+		}
+	}
+	for _, pt := range []Point{start, dest} {
+		for _, b := range p.concaveVertices {
+			if !p.visible(pt, b) {
+				continue
+			}
+			if allowed(pt, b) {
+				vis.link(pt, b)
+			}
+			if allowed(b, pt) {
+				vis.link(b, pt)
+			}
+		}
+	}
+	if visible(start, dest) {
+		vis.link(start, dest)
+	}
+	cost, heuristic := p.travelCost()
+	var search aStar
+	return p.finishPath(p.polygonSet, search.findPath(vis, start, dest, cost, heuristic), visible)
+}
+
+// segmentPointDist returns the distance between point c and the line
+// segment from a to b.
+func segmentPointDist(a, b, c Point) float64 {
+	return nodeDist(closestOnSegment(a, b, c), c)
+}
+
+// closestOnSegment returns the point on the line segment from a to b that
+// is closest to point c.
+func closestOnSegment(a, b, c Point) Point {
+	ab := b.Sub(a)
+	ac := c.Sub(a)
+	l := ab.Dot(ab)
+	if l == 0 {
+		return a
+	}
+	t := max(0, min(1, ac.Dot(ab)/l))
+	return a.Add(ab.Mul(t))
+}
+
+// normalizedHoles returns the polygons with at least three vertices from
+// holes, wound in the same orientation as the polygons of the Pathfinder
+// so that their convex vertices are the turning points for paths.
+func normalizedHoles(holes poly.PolygonSet) poly.PolygonSet {
+	var res poly.PolygonSet
+	for _, h := range holes {
+		if len(h) < 3 {
+			continue
+		}
+		if h.Orientation() < 0 {
+			h = slices.Clone(h)
+			slices.Reverse(h)
+		}
+		res = append(res, h)
+	}
+	return res
+}
+
+// moveOutOfHoles moves pt to the nearest point just outside of the hole it
+// lies in, if any. ps is the polygon set including the holes.
+func moveOutOfHoles(ps, holes poly.PolygonSet, pt Point, margin float64) Point {
+	v := p2v(pt)
+	for _, h := range holes {
+		if h.Contains(v, true) {
+			return ensureInside(ps, v2p(h.ClosestPt(v)), margin)
+		}
+	}
+	return pt
+}
+
+// insideAny reports whether pt lies strictly inside any of the holes.
+func insideAny(holes poly.PolygonSet, pt Point) bool {
+	v := p2v(pt)
+	for _, h := range holes {
+		if h.Contains(v, false) {
+			return true
+		}
+	}
+	return false
+}
+
+// blockedBy reports whether the line segment between a and b crosses or
+// runs through any of the holes.
+func blockedBy(holes poly.PolygonSet, a, b Point) bool {
+	ls := poly.LineSeg{A: p2v(a), B: p2v(b)}
+	for _, h := range holes {
+		if h.IsCrossedBy(ls) || h.Contains(ls.Middle(), false) {
+			return true
+		}
+	}
+	return false
+}
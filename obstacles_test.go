@@ -0,0 +1,392 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+// A square without holes. Origin is at the top-left corner.
+//
+//	 0,0 >-----------+ 40,0
+//	     |           |
+//	     |           |
+//	0,40 +-----------+ 40,40
+var polygonSquare = [][]pathfind.Point{
+	{
+		pathfind.Pt(0, 0),
+		pathfind.Pt(40, 0),
+		pathfind.Pt(40, 40),
+		pathfind.Pt(0, 40),
+	},
+}
+
+func TestPathfinderPathAvoidingRegion(t *testing.T) {
+	// The region is the diamond of polygonO, given in opposite winding.
+	region := []pathfind.Point{
+		pathfind.Pt(10, 20),
+		pathfind.Pt(20, 30),
+		pathfind.Pt(30, 20),
+		pathfind.Pt(20, 10),
+	}
+	tests := []struct {
+		name  string
+		start pathfind.Point
+		dest  pathfind.Point
+		want  []pathfind.Point
+	}{
+		{
+			name:  "Path around region",
+			start: pathfind.Pt(15, 10),
+			dest:  pathfind.Pt(30, 30),
+			want: []pathfind.Point{
+				pathfind.Pt(15, 10),
+				pathfind.Pt(20, 10),
+				pathfind.Pt(30, 20),
+				pathfind.Pt(30, 30),
+			},
+		},
+		{
+			name:  "Direct connection next to region",
+			start: pathfind.Pt(5, 5),
+			dest:  pathfind.Pt(35, 5),
+			want: []pathfind.Point{
+				pathfind.Pt(5, 5),
+				pathfind.Pt(35, 5),
+			},
+		},
+		{
+			name:  "Dest inside region is moved out",
+			start: pathfind.Pt(5, 20),
+			dest:  pathfind.Pt(12, 20),
+			want: []pathfind.Point{
+				pathfind.Pt(5, 20),
+				pathfind.Pt(11, 21),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pathfinder := pathfind.NewPathfinder(polygonSquare)
+			got := pathfinder.PathAvoidingRegion(tt.start, tt.dest, region)
+			if !pathNearEq(got, tt.want) {
+				t.Errorf("PathAvoidingRegion(%v, %v, %v)\n got: %v\nwant: %v",
+					tt.start, tt.dest, region, got, tt.want)
+			}
+			if unaffected := pathfinder.Path(tt.start, tt.dest); len(unaffected) != 2 {
+				t.Errorf("Path(%v, %v) after PathAvoidingRegion = %v, want direct connection",
+					tt.start, tt.dest, unaffected)
+			}
+		})
+	}
+}
+
+func TestPathfinderPathAvoidingRegionMatchesHole(t *testing.T) {
+	pathfinder := pathfind.NewPathfinder(polygonSquare)
+	withHole := pathfind.NewPathfinder(polygonO)
+	start, dest := pathfind.Pt(5, 35), pathfind.Pt(35, 5)
+	got := pathfinder.PathAvoidingRegion(start, dest, polygonO[1])
+	want := withHole.Path(start, dest)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PathAvoidingRegion(%v, %v, %v)\n got: %v\nwant: %v",
+			start, dest, polygonO[1], got, want)
+	}
+}
+
+func TestPathfinderPathWithAgents(t *testing.T) {
+	//	>-----------+
+	//	|     a     |
+	//	|    / \    |
+	//	| s +   + d |
+	//	|    \ /    |
+	//	|     +     |
+	//	+-----------+
+	pathfinder := pathfind.NewPathfinder(polygonO)
+	start, dest := pathfind.Pt(5, 20), pathfind.Pt(35, 20)
+	upper := []pathfind.Point{start, pathfind.Pt(20, 10), dest}
+	lower := []pathfind.Point{start, pathfind.Pt(20, 30), dest}
+	tests := []struct {
+		name   string
+		agents []pathfind.Point
+		radius float64
+		want   []pathfind.Point
+	}{
+		{
+			name:   "agent blocks upper route",
+			agents: []pathfind.Point{pathfind.Pt(20, 7)},
+			radius: 4,
+			want:   lower,
+		},
+		{
+			name:   "agent blocks lower route",
+			agents: []pathfind.Point{pathfind.Pt(20, 33)},
+			radius: 4,
+			want:   upper,
+		},
+		{
+			name:   "agents block both routes",
+			agents: []pathfind.Point{pathfind.Pt(20, 7), pathfind.Pt(20, 33)},
+			radius: 4,
+			want:   nil,
+		},
+		{
+			name:   "agent at start is ignored",
+			agents: []pathfind.Point{start, pathfind.Pt(20, 33)},
+			radius: 4,
+			want:   upper,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pathfinder.PathWithAgents(start, dest, tt.agents, tt.radius)
+			if !pathNearEq(got, tt.want) {
+				t.Errorf("PathWithAgents(%v, %v, %v, %g)\n got: %v\nwant: %v",
+					start, dest, tt.agents, tt.radius, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathfinderPathMonotone(t *testing.T) {
+	// A cup-shaped hole in polygonSquare, open towards the top. A path
+	// from inside the cup to below it first has to move up and out.
+	//
+	//	>---------------+
+	//	|  >-+     +-+  |
+	//	|  | |  s  | |  |
+	//	|  | +-----+ |  |
+	//	|  +---------+  |
+	//	|       d       |
+	//	+---------------+
+	polygons := [][]pathfind.Point{
+		polygonSquare[0],
+		{
+			pathfind.Pt(10, 10),
+			pathfind.Pt(13, 10),
+			pathfind.Pt(13, 25),
+			pathfind.Pt(27, 25),
+			pathfind.Pt(27, 10),
+			pathfind.Pt(30, 10),
+			pathfind.Pt(30, 28),
+			pathfind.Pt(10, 28),
+		},
+	}
+	pathfinder := pathfind.NewPathfinder(polygons)
+	tests := []struct {
+		name      string
+		start     pathfind.Point
+		dest      pathfind.Point
+		tolerance float64
+		want      []pathfind.Point
+	}{
+		{
+			name:      "backtracking within tolerance",
+			start:     pathfind.Pt(18, 15),
+			dest:      pathfind.Pt(20, 35),
+			tolerance: 6,
+			want:      pathfinder.Path(pathfind.Pt(18, 15), pathfind.Pt(20, 35)),
+		},
+		{
+			name:      "backtracking beyond tolerance",
+			start:     pathfind.Pt(18, 15),
+			dest:      pathfind.Pt(20, 35),
+			tolerance: 4,
+			want:      nil,
+		},
+		{
+			name:      "longer path with less backtracking",
+			start:     pathfind.Pt(16, 22),
+			dest:      pathfind.Pt(20, 35),
+			tolerance: 10,
+			want: []pathfind.Point{
+				pathfind.Pt(16, 22),
+				pathfind.Pt(27, 10),
+				pathfind.Pt(30, 10),
+				pathfind.Pt(30, 28),
+				pathfind.Pt(20, 35),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pathfinder.PathMonotone(tt.start, tt.dest, tt.tolerance)
+			if !pathNearEq(got, tt.want) {
+				t.Errorf("PathMonotone(%v, %v, %g)\n got: %v\nwant: %v",
+					tt.start, tt.dest, tt.tolerance, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathfinderPathAvoiding(t *testing.T) {
+	rect := func(x0, y0, x1, y1 float64) []pathfind.Point {
+		return []pathfind.Point{
+			pathfind.Pt(x0, y0), pathfind.Pt(x1, y0), pathfind.Pt(x1, y1), pathfind.Pt(x0, y1),
+		}
+	}
+	tests := []struct {
+		name     string
+		start    pathfind.Point
+		dest     pathfind.Point
+		blockers [][]pathfind.Point
+		want     []pathfind.Point
+	}{
+		{
+			name:     "No blockers",
+			start:    pathfind.Pt(5, 12),
+			dest:     pathfind.Pt(35, 12),
+			blockers: nil,
+			want:     []pathfind.Point{pathfind.Pt(5, 12), pathfind.Pt(35, 12)},
+		},
+		{
+			name:     "Around one blocker",
+			start:    pathfind.Pt(5, 12),
+			dest:     pathfind.Pt(35, 12),
+			blockers: [][]pathfind.Point{rect(15, 10, 25, 30)},
+			want: []pathfind.Point{
+				pathfind.Pt(5, 12),
+				pathfind.Pt(15, 10),
+				pathfind.Pt(25, 10),
+				pathfind.Pt(35, 12),
+			},
+		},
+		{
+			name:     "Second blocker closes the shorter side",
+			start:    pathfind.Pt(5, 12),
+			dest:     pathfind.Pt(35, 12),
+			blockers: [][]pathfind.Point{rect(15, 10, 25, 30), rect(16, -5, 24, 12)},
+			want: []pathfind.Point{
+				pathfind.Pt(5, 12),
+				pathfind.Pt(15, 30),
+				pathfind.Pt(25, 30),
+				pathfind.Pt(35, 12),
+			},
+		},
+		{
+			name:     "Degenerate blocker is ignored",
+			start:    pathfind.Pt(5, 12),
+			dest:     pathfind.Pt(35, 12),
+			blockers: [][]pathfind.Point{{pathfind.Pt(20, 0), pathfind.Pt(20, 40)}},
+			want:     []pathfind.Point{pathfind.Pt(5, 12), pathfind.Pt(35, 12)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pathfinder := pathfind.NewPathfinder(polygonSquare)
+			got := pathfinder.PathAvoiding(tt.start, tt.dest, tt.blockers)
+			if !pathNearEq(got, tt.want) {
+				t.Errorf("PathAvoiding(%v, %v, %v)\n got: %v\nwant: %v",
+					tt.start, tt.dest, tt.blockers, got, tt.want)
+			}
+			if unaffected := pathfinder.Path(tt.start, tt.dest); len(unaffected) != 2 {
+				t.Errorf("Path(%v, %v) after PathAvoiding = %v, want direct connection",
+					tt.start, tt.dest, unaffected)
+			}
+		})
+	}
+}
+
+func TestPathfinderPathAvoidingConcurrent(t *testing.T) {
+	pathfinder := pathfind.NewPathfinder(polygonSquare)
+	start, dest := pathfind.Pt(20, 5), pathfind.Pt(20, 35)
+	blockers := [][][]pathfind.Point{
+		{{pathfind.Pt(10, 15), pathfind.Pt(25, 15), pathfind.Pt(25, 25), pathfind.Pt(10, 25)}},
+		{{pathfind.Pt(15, 15), pathfind.Pt(30, 15), pathfind.Pt(30, 25), pathfind.Pt(15, 25)}},
+		nil,
+	}
+	want := make([][]pathfind.Point, len(blockers))
+	for i, b := range blockers {
+		want[i] = pathfinder.PathAvoiding(start, dest, b)
+	}
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 30 {
+				k := (g + i) % len(blockers)
+				if got := pathfinder.PathAvoiding(start, dest, blockers[k]); !reflect.DeepEqual(got, want[k]) {
+					t.Errorf("concurrent PathAvoiding(%v, %v, %v) = %v, want %v", start, dest, blockers[k], got, want[k])
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestPathfinderPathWithAgentsClamp(t *testing.T) {
+	var calls int
+	var clamped pathfind.Point
+	pathfinder := pathfind.NewPathfinder(polygonO, pathfind.OnClamp(func(_, c pathfind.Point) {
+		calls++
+		clamped = c
+	}))
+	start, dest := pathfind.Pt(5, 20), pathfind.Pt(50, 20)
+	want := pathfinder.Path(start, dest)
+	calls = 0
+
+	got := pathfinder.PathWithAgents(start, dest, []pathfind.Point{pathfind.Pt(20, 33)}, 4)
+	if calls != 1 {
+		t.Errorf("OnClamp called %d times, want 1", calls)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PathWithAgents(%v, %v)\n got: %v\nwant: %v", start, dest, got, want)
+	}
+	if len(got) > 0 && got[len(got)-1] != clamped {
+		t.Errorf("PathWithAgents ends at %v, want the clamped destination %v", got[len(got)-1], clamped)
+	}
+
+	strict := pathfind.NewPathfinder(polygonO, pathfind.WithStrictBounds())
+	if got := strict.PathWithAgents(start, dest, nil, 4); got != nil {
+		t.Errorf("PathWithAgents with strict bounds = %v, want nil", got)
+	}
+}
+
+func TestPathfinderPathAvoidingMatchesPath(t *testing.T) {
+	mud := []pathfind.Point{pathfind.Pt(10, 10), pathfind.Pt(30, 10), pathfind.Pt(30, 30), pathfind.Pt(10, 30)}
+	tests := []struct {
+		name       string
+		pathfinder *pathfind.Pathfinder
+		start      pathfind.Point
+		dest       pathfind.Point
+	}{
+		{
+			name: "weighted region",
+			pathfinder: pathfind.NewWeightedPathfinder([]pathfind.WeightedPolygon{
+				{Points: polygonSquare[0]},
+				{Points: mud, Weight: 5},
+			}),
+			start: pathfind.Pt(5, 20),
+			dest:  pathfind.Pt(35, 20),
+		},
+		{
+			name:       "clearance",
+			pathfinder: pathfind.NewPathfinder(polygonO, pathfind.WithClearance(2)),
+			start:      pathfind.Pt(5, 9),
+			dest:       pathfind.Pt(35, 9),
+		},
+		{
+			name:       "metric",
+			pathfinder: pathfind.NewPathfinder(polygonO, pathfind.WithMetric(pathfind.ManhattanDist, pathfind.ManhattanDist)),
+			start:      pathfind.Pt(5, 35),
+			dest:       pathfind.Pt(35, 5),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tt.pathfinder.Path(tt.start, tt.dest)
+			if len(want) <= 2 {
+				t.Fatalf("Path(%v, %v) = %v, want a detour", tt.start, tt.dest, want)
+			}
+			if got := tt.pathfinder.PathAvoiding(tt.start, tt.dest, nil); !pathNearEq(got, want) {
+				t.Errorf("PathAvoiding(%v, %v, nil)\n got: %v\nwant: %v", tt.start, tt.dest, got, want)
+			}
+		})
+	}
+}
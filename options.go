@@ -0,0 +1,298 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+// An Option configures a Pathfinder. Options are passed to NewPathfinder
+// or NewPathfinderChecked.
+type Option func(*options)
+
+// options holds the configuration of a Pathfinder.
+type options struct {
+	autoClose        bool
+	hashCell         float64
+	tags             []string
+	onClamp          func(requested, clamped Point)
+	pruneUnreachable bool
+	doors            [][2]Point
+	strictBounds     bool
+	invertedNesting  bool
+	smoothingIters   int
+	tensionSteps     int
+	concaveThreshold float64
+	regionWeights    map[int]float64
+	clearance        float64
+	cost, heuristic  func(a, b Point) float64
+	simplify         bool
+	simplifyEps      float64
+	wallPolylines    []WallPolyline
+	clampToStart     bool
+	margin           float64
+}
+
+// WithAutoClose closes each open polygon ring by appending its first vertex,
+// so that the last vertex equals the first one. Without this option
+// NewPathfinderChecked reports open rings as an error.
+func WithAutoClose() Option {
+	return func(o *options) {
+		o.autoClose = true
+	}
+}
+
+// WithSpatialHash makes the Pathfinder index its concave vertices with a
+// spatial hash, a uniform grid of square cells with the given side length,
+// instead of the default quad tree. A spatial hash supports inserting and
+// removing vertices in constant time, which pays off for frequently changing
+// polygon sets: AddPolygon and RemovePolygon only insert and remove the
+// vertices that change, while a quad tree is rebuilt if a new vertex lies
+// outside of its bounds. The cell size should be in the order of the typical
+// distance between path start and destination. If cellSize is not positive,
+// a cell size is derived from the extent of the polygons.
+func WithSpatialHash(cellSize float64) Option {
+	return func(o *options) {
+		o.hashCell = cellSize
+		if cellSize <= 0 {
+			o.hashCell = -1
+		}
+	}
+}
+
+// WithTags attaches a tag to each polygon, e.g. "land", "water" or "road".
+// The tag of the polygon with index i is tags[i]; polygons without
+// a corresponding entry have the empty tag. Tags of area polygons can be
+// used to restrict path queries with PathFiltered.
+func WithTags(tags []string) Option {
+	return func(o *options) {
+		o.tags = tags
+	}
+}
+
+// OnClamp registers a function that is called when a path query clamps its
+// destination into the polygon set, with the requested and the clamped
+// destination. It is called at most once per Path call, and not at all if
+// the destination is already inside the polygon set. PathBatch calls it
+// from multiple goroutines concurrently.
+func OnClamp(f func(requested, clamped Point)) Option {
+	return func(o *options) {
+		o.onClamp = f
+	}
+}
+
+// WithPruneUnreachable removes polygon vertices from the visibility graph
+// that cannot be part of any path within the outermost areas, the areas
+// that do not lie inside of a hole. These are the corners of holes that
+// lie outside of their area polygon, and the vertices of all polygons
+// nested inside of a hole, e.g. of obstacles drawn within other obstacles.
+// This makes the graph smaller for messy imported maps without changing
+// any path within the outermost areas. The islands formed by polygons
+// inside of holes remain accessible, but paths on them can no longer turn
+// around corners.
+func WithPruneUnreachable() Option {
+	return func(o *options) {
+		o.pruneUnreachable = true
+	}
+}
+
+// WithDoors registers door segments, e.g. doorways that can be opened and
+// closed in a game. The door with index i is the line segment between the
+// two points of doors[i]. Doors do not affect Path; PathWithDoors treats
+// the doors passed to it as closed walls for a single query.
+func WithDoors(doors [][2]Point) Option {
+	return func(o *options) {
+		o.doors = doors
+	}
+}
+
+// ClampTowardStart changes how a path query clamps a destination outside of
+// the polygon set: instead of moving it to the nearest polygon edge, it is
+// moved along the line of travel from the start toward the destination,
+// to the point where this line leaves the accessible area for the last
+// time, slightly inside of it. This suits "walk as far as you can toward
+// the click" movement, where the nearest edge may lie off to the side or
+// even behind the start. If the line never runs through the accessible
+// area, e.g. because the start lies outside of it as well, the destination
+// is clamped to the nearest edge as usual. OnClamp is called with the
+// clamped destination in either case.
+func ClampTowardStart() Option {
+	return func(o *options) {
+		o.clampToStart = true
+	}
+}
+
+// WithStrictBounds makes Path return nil if the destination lies outside of
+// the polygon set, instead of clamping it to the nearest point inside. This
+// makes invalid requests detectable, e.g. for server-authoritative movement.
+// The option applies to Path, PathReversed, PathFiltered and PathBatch.
+// OnClamp is never called with this option.
+func WithStrictBounds() Option {
+	return func(o *options) {
+		o.strictBounds = true
+	}
+}
+
+// WithInvertedNesting inverts the convention for the nesting of polygons:
+// polygons at the first level are holes, polygons contained inside a hole
+// are area polygons, and so on. This suits data that only describes the
+// obstacles of a map. The accessible area around the top-level holes is
+// bounded by a rectangle that is added as the last polygon of the polygon
+// set. It encloses the bounding rectangle of the polygons with a border as
+// wide as the larger of its width and height, but at least 1. Destinations
+// beyond it are clamped to it. AddPolygon and RemovePolygon keep it the last
+// polygon and recompute it. The indices of the given polygons, e.g. for Tag
+// and InHole, are not affected.
+func WithInvertedNesting() Option {
+	return func(o *options) {
+		o.invertedNesting = true
+	}
+}
+
+// WithSmoothingIterations limits the work of the path smoothing functions,
+// such as StraightenPath, to n passes over the path. Each pass can only
+// shorten the path and keeps it within the accessible area, so stopping
+// early returns the best path found so far. This bounds the worst-case cost
+// for real-time use. If n is not positive, the smoothing continues until
+// the path does not change anymore, which is the default.
+func WithSmoothingIterations(n int) Option {
+	return func(o *options) {
+		o.smoothingIters = n
+	}
+}
+
+// WithSplineTensionSteps limits how often SmoothPath halves the tangents of
+// a spline segment that leaves the accessible area to n, before it uses the
+// straight segment instead. Fewer steps make SmoothPath faster, but fall
+// back to straight segments more often. If n is not positive, the default
+// of 8 steps is used.
+func WithSplineTensionSteps(n int) Option {
+	return func(o *options) {
+		o.tensionSteps = n
+	}
+}
+
+// WithConcaveAngleThreshold leaves out polygon corners at which the outline
+// turns by no more than the given angle in radians from the visibility
+// graph. On noisy data, e.g. outlines traced from images, many vertices are
+// only barely not collinear. Such corners hardly affect paths, but make the
+// graph larger and path queries slower. Note that a path can no longer turn
+// at a left-out corner of a hole, so the path around it may become longer
+// or, for a threshold that leaves out real corners, not be found at all.
+func WithConcaveAngleThreshold(radians float64) Option {
+	return func(o *options) {
+		o.concaveThreshold = radians
+	}
+}
+
+// WithPreferredAreas marks the polygons with the given indices as preferred
+// travel areas, e.g. roads, that paths favor even if they become slightly
+// longer. The cost of a path segment is its length outside of the preferred
+// areas plus its length inside of them multiplied by discount, which must be
+// greater than 0 and at most 1; otherwise the option is ignored.
+//
+// Preferred areas are overlays: they are not part of the polygon set that
+// bounds the accessible area and neither form areas nor holes themselves.
+// Their vertices inside the accessible area are additional turning points
+// for paths. Invalid indices are ignored.
+func WithPreferredAreas(indices []int, discount float64) Option {
+	return func(o *options) {
+		if discount <= 0 || discount > 1 {
+			return
+		}
+		for _, i := range indices {
+			o.setRegionWeight(i, discount)
+		}
+	}
+}
+
+// WithClearance makes paths keep a distance of at least radius from all
+// polygon edges, e.g. for an agent with a circular footprint of this radius.
+// The paths turn at the polygon corners mitred outward by the radius, and
+// a start or destination closer than radius to an edge is pushed away from
+// it. Passages narrower than twice the radius cannot be passed.
+//
+// If the clearance makes the destination unreachable, e.g. because it can
+// only be reached through a passage that is too narrow or because there is
+// no position with enough clearance near it, Path returns nil. The option
+// applies to Path, PathReversed, PathWithCost, PathBatch and PathFiltered.
+func WithClearance(radius float64) Option {
+	return func(o *options) {
+		o.clearance = max(0, radius)
+	}
+}
+
+// WithMetric makes Path measure the cost of a path segment with the cost
+// function instead of the Euclidean distance, e.g. ManhattanDist for
+// movement on a 4-connected grid. The heuristic function estimates the
+// cost between two points for the A* search and must never overestimate
+// it; a nil heuristic always estimates zero. The visibility graph is not
+// affected, so paths still turn only at polygon corners. The option applies
+// to Path and the methods based on it, and PathWithCost reports the cost
+// measured with the cost function.
+func WithMetric(cost, heuristic func(a, b Point) float64) Option {
+	return func(o *options) {
+		o.cost = cost
+		o.heuristic = heuristic
+		if cost != nil && heuristic == nil {
+			o.heuristic = func(a, b Point) float64 { return 0 }
+		}
+	}
+}
+
+// WithSimplification makes Path drop waypoints that deviate by at most
+// epsilon from the remaining path, like SimplifyPath, e.g. vertices that
+// happen to lie on the straight line between their neighbours. Unlike
+// SimplifyPath, a waypoint is only dropped if the shortcut between the
+// remaining waypoints stays within the accessible area, so the path never
+// cuts a corner around an obstacle. A negative epsilon is treated as 0.
+// The option applies to Path and the methods based on it.
+func WithSimplification(epsilon float64) Option {
+	return func(o *options) {
+		o.simplify = true
+		o.simplifyEps = max(0, epsilon)
+	}
+}
+
+// WithWalls adds open polylines as walls, e.g. fences or thin walls inside
+// a room, which paths cannot cross but go around. Unlike a closed polygon,
+// a wall has no interior that could be mistaken for an area or a hole.
+// See WallPolyline for details.
+func WithWalls(walls ...WallPolyline) Option {
+	return func(o *options) {
+		o.wallPolylines = append(o.wallPolylines, walls...)
+	}
+}
+
+// WithMargin sets the distance by which paths keep away from the polygon
+// corners that they turn at, 0.002 by default. Destinations outside of the
+// polygon set are moved this far into the accessible area, and paths turn
+// around the walls given by WithWalls at this distance. The default suits
+// maps in the order of 1 to 10000 units; maps at very different scales,
+// e.g. normalized to the unit square or in pixels of a large image, need
+// a margin that is accordingly smaller or larger. A margin that is not
+// positive is ignored.
+func WithMargin(m float64) Option {
+	return func(o *options) {
+		if m > 0 {
+			o.margin = m
+		}
+	}
+}
+
+// withRegionWeights turns the polygons with the given indices into weighted
+// regions with the respective cost multipliers, as for NewWeightedPathfinder.
+func withRegionWeights(weights map[int]float64) Option {
+	return func(o *options) {
+		for i, w := range weights {
+			o.setRegionWeight(i, w)
+		}
+	}
+}
+
+// setRegionWeight turns the polygon with index i into a weighted region
+// with cost multiplier w.
+func (o *options) setRegionWeight(i int, w float64) {
+	if o.regionWeights == nil {
+		o.regionWeights = make(map[int]float64)
+	}
+	o.regionWeights[i] = w
+}
@@ -0,0 +1,72 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+// NarrowPassages reports the spots where the accessible area is narrower
+// than minWidth, e.g. corridors that a wide agent cannot pass, so that they
+// can be widened. Each spot is returned as the pair of closest points on
+// two opposing polygon edges. The gap between them is less than minWidth
+// wide and leads through the accessible area. A passage that is bounded by
+// several pairs of edges is reported once for each distinct pair of points.
+//
+// The result only depends on the polygon geometry, not on any path.
+func (p *Pathfinder) NarrowPassages(minWidth float64) [][2]Point {
+	var edges [][2]Point
+	seen := make(map[[2]Point]bool)
+	for _, polygon := range p.polygonSet {
+		for i := range polygon {
+			e := polygon.Edge(i)
+			a, b := v2p(e.A), v2p(e.B)
+			if seen[[2]Point{a, b}] || seen[[2]Point{b, a}] {
+				// The edge back from the end of a two-vertex wall.
+				continue
+			}
+			seen[[2]Point{a, b}] = true
+			edges = append(edges, [2]Point{a, b})
+		}
+	}
+	var passages [][2]Point
+	reported := make(map[[2]Point]bool)
+	for i, e := range edges {
+		for _, f := range edges[i+1:] {
+			if e[0] == f[0] || e[0] == f[1] || e[1] == f[0] || e[1] == f[1] {
+				// Adjoining edges meet at a corner.
+				continue
+			}
+			u, v := closestPoints(e[0], e[1], f[0], f[1])
+			if d := nodeDist(u, v); d == 0 || d >= minWidth {
+				continue
+			}
+			mid := Point{X: (u.X + v.X) / 2, Y: (u.Y + v.Y) / 2}
+			if !strictlyInside(p.polygonSet, mid) || !p.inSight(u, v) {
+				continue
+			}
+			if reported[[2]Point{u, v}] || reported[[2]Point{v, u}] {
+				continue
+			}
+			reported[[2]Point{u, v}] = true
+			passages = append(passages, [2]Point{u, v})
+		}
+	}
+	return passages
+}
+
+// closestPoints returns the closest pair of points on the line segment from
+// a1 to a2 and the line segment from b1 to b2, which do not cross.
+func closestPoints(a1, a2, b1, b2 Point) (Point, Point) {
+	u, v := closestOnSegment(a1, a2, b1), b1
+	best := nodeDist(u, v)
+	candidates := [][2]Point{
+		{closestOnSegment(a1, a2, b2), b2},
+		{a1, closestOnSegment(b1, b2, a1)},
+		{a2, closestOnSegment(b1, b2, a2)},
+	}
+	for _, c := range candidates {
+		if d := nodeDist(c[0], c[1]); d < best {
+			u, v, best = c[0], c[1], d
+		}
+	}
+	return u, v
+}
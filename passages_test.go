@@ -0,0 +1,53 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+func TestPathfinderNarrowPassages(t *testing.T) {
+	tests := []struct {
+		name     string
+		minWidth float64
+		want     [][2]pathfind.Point
+	}{
+		{
+			name:     "narrow passage",
+			minWidth: 6,
+			want: [][2]pathfind.Point{
+				{pathfind.Pt(40, 0), pathfind.Pt(40, 5)},
+				{pathfind.Pt(60, 0), pathfind.Pt(60, 5)},
+			},
+		},
+		{
+			name:     "both passages",
+			minWidth: 11,
+			want: [][2]pathfind.Point{
+				{pathfind.Pt(40, 0), pathfind.Pt(40, 5)},
+				{pathfind.Pt(60, 0), pathfind.Pt(60, 5)},
+				{pathfind.Pt(60, 60), pathfind.Pt(60, 50)},
+				{pathfind.Pt(40, 60), pathfind.Pt(40, 50)},
+			},
+		},
+		{
+			name:     "no passage",
+			minWidth: 5,
+			want:     nil,
+		},
+	}
+	pathfinder := pathfind.NewPathfinder(polygonTwoPassages)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pathfinder.NarrowPassages(tt.minWidth)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NarrowPassages(%g)\n got: %v\nwant: %v", tt.minWidth, got, tt.want)
+			}
+		})
+	}
+}
@@ -7,24 +7,57 @@
 package pathfind
 
 import (
+	"context"
+	"fmt"
+	"iter"
 	"math"
+	"slices"
+	"sync"
 
-	"github.com/fzipp/astar"
 	"github.com/fzipp/geom"
 	"github.com/fzipp/pathfind/internal/poly"
 )
 
-const margin = 0.002
+// defaultMargin is the distance by which paths keep away from polygon
+// corners and outlines unless WithMargin is given.
+const defaultMargin = 0.002
 
 // A Pathfinder is created and initialized with a set of polygons via
 // NewPathfinder. Its Path method finds the shortest path between two points
 // in this polygon set.
+//
+// Path and the other query methods are safe for concurrent use by multiple
+// goroutines: each query works on its own copy of the relevant part of the
+// visibility graph. Methods that modify the Pathfinder, such as AddPolygon,
+// RemovePolygon and the modifications of its Graph, must not be called
+// concurrently with any other method.
+//
+// A point with a NaN or infinite coordinate, e.g. the result of a division
+// by zero elsewhere, lies outside of the accessible area for all methods:
+// no path leads from or to it, and it cannot be seen from any other point.
 type Pathfinder struct {
 	polygons        [][]Point
 	polygonSet      poly.PolygonSet
+	regions         poly.PolygonSet
+	weights         []float64
+	walls           [][2]Point
+	wallRings       poly.PolygonSet
+	concaveOf       [][]Point
 	concaveVertices []Point
+	parents         []int
+	depths          []int
+	boxes           []rect
 	cachedGraph     graph[Point]
-	index           *quadTree
+	edited          bool
+	generation      uint64
+	index           spatialIndex
+	opts            options
+
+	mu              sync.Mutex
+	visibilityGraph graph[Point]
+	components      map[Point]int
+	numComponents   int
+	scratchPool     sync.Pool
 }
 
 // NewPathfinder creates a Pathfinder instance and initializes it with a set of
@@ -40,83 +73,718 @@ type Pathfinder struct {
 //   - Polygons at the first level are area polygons.
 //   - Polygons contained inside an area polygon are holes.
 //   - Polygons contained inside a hole are area polygons again.
-func NewPathfinder(polygons [][]Point) *Pathfinder {
-	polygonSet := convert(polygons, func(ps []Point) poly.Polygon {
-		return ps2vs(ps)
-	})
-	concave := concaveVertices(polygonSet)
-	box := boundingRect(polygons)
-	idx := newQuadTree(box, 8)
+//
+// The edge from the last vertex of a polygon back to its first vertex is
+// implied, so polygons can be passed as open rings. Closed rings, where
+// the last vertex repeats the first one, are accepted as well. Consecutive
+// repeated vertices, which form edges of zero length, are collapsed into one.
+// The vertices of each polygon can run clockwise or counterclockwise,
+// independently of the other polygons; see NormalizeWinding.
+//
+// A polygon with only two vertices is a line segment that encloses no area.
+// Inside an area polygon it acts as a thin wall: paths cannot cross it, but
+// go around its ends. Polygons with fewer than two vertices are ignored.
+func NewPathfinder(polygons [][]Point, opts ...Option) *Pathfinder {
+	o := options{margin: defaultMargin}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	polygons = convert(polygons, dedupeVertices)
+	if o.autoClose {
+		polygons = convert(polygons, closeRing)
+	}
+	if o.invertedNesting {
+		polygons = append(slices.Clip(polygons), outerFrame(boundingRect(polygons)))
+	}
+	polygonSet := convert(polygons, toPolygon)
+	regions, weights := takeRegions(polygonSet, o.regionWeights)
+	concaveOf := make([][]Point, len(polygonSet))
+	for i := range polygonSet {
+		concaveOf[i] = turningPoints(polygons, polygonSet, i, o)
+	}
+	for i, area := range regions {
+		if area != nil {
+			concaveOf[i] = verticesInside(polygonSet, area)
+		}
+	}
+	boxes := make([]rect, len(polygons))
+	for i, ps := range polygons {
+		boxes[i] = boundingRect([][]Point{ps})
+	}
+	p := &Pathfinder{
+		polygons:   polygons,
+		polygonSet: polygonSet,
+		regions:    regions,
+		weights:    weights,
+		wallRings:  wallRings(o.wallPolylines),
+		concaveOf:  concaveOf,
+		boxes:      boxes,
+		opts:       o,
+	}
+	p.updateGraph(nil)
+	return p
+}
+
+// outerFrame returns the area polygon that encloses the top-level holes
+// for the WithInvertedNesting option: the rectangle b extended on each side
+// by the larger of its width and height.
+func outerFrame(b rect) []Point {
+	d := max(b.max.X-b.min.X, b.max.Y-b.min.Y, 1)
+	minX, minY := b.min.X-d, b.min.Y-d
+	maxX, maxY := b.max.X+d, b.max.Y+d
+	return []Point{{minX, minY}, {maxX, minY}, {maxX, maxY}, {minX, maxY}}
+}
+
+// toPolygon converts ring ps to a polygon of the polygon set. Polygons with
+// fewer than two vertices are ignored and represented as nil.
+func toPolygon(ps []Point) poly.Polygon {
+	ps = openRing(ps)
+	if len(ps) < 2 {
+		return nil
+	}
+	return ps2vs(ps)
+}
+
+// buildIndex creates the spatial index for the concave vertices of polygons
+// and inserts the vertices. The index covers the polygons and the vertices,
+// which may include nodes outside of the polygons added with Graph.Link.
+func buildIndex(polygons [][]Point, concave []Point, o options) spatialIndex {
+	idx := newIndex(boundingRect(append(slices.Clip(polygons), concave)), o)
 	for _, pt := range concave {
 		idx.insert(pt)
 	}
-	return &Pathfinder{
-		polygons:        polygons,
-		polygonSet:      polygonSet,
-		concaveVertices: concave,
-		cachedGraph:     visibilityGraph(polygonSet, concave),
-		index:           idx,
+	return idx
+}
+
+// newIndex creates the spatial index for the concave vertices of polygons
+// within the bounding rectangle box as configured by the options.
+func newIndex(box rect, o options) spatialIndex {
+	switch {
+	case o.hashCell > 0:
+		return newSpatialHash(o.hashCell)
+	case o.hashCell < 0:
+		size := math.Max(box.max.X-box.min.X, box.max.Y-box.min.Y) / 16
+		if size <= 0 {
+			size = 1
+		}
+		return newSpatialHash(size)
+	}
+	return newQuadTree(box, 8)
+}
+
+// NewPathfinderChecked is like NewPathfinder, but validates the polygons
+// first and reports an error for invalid input instead of creating a
+// Pathfinder that silently misbehaves. Polygon rings must be closed, i.e.
+// their last vertex must equal the first one, unless the WithAutoClose
+// option is given. Polygons with fewer than two distinct vertices are
+// reported as an error, while two-vertex polygons are accepted as walls.
+func NewPathfinderChecked(polygons [][]Point, opts ...Option) (*Pathfinder, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	for i, ps := range polygons {
+		if len(openRing(dedupeVertices(ps))) < 2 {
+			return nil, fmt.Errorf("polygon %d has fewer than 2 vertices", i)
+		}
+		if !o.autoClose && !isClosedRing(ps) {
+			return nil, fmt.Errorf("polygon %d is not a closed ring", i)
+		}
 	}
+	return NewPathfinder(polygons, opts...), nil
+}
+
+// isClosedRing reports whether the last vertex of ring ps equals its first.
+func isClosedRing(ps []Point) bool {
+	return len(ps) > 0 && ps[0] == ps[len(ps)-1]
+}
+
+// closeRing returns ring ps closed by appending its first vertex, unless it
+// is already closed.
+func closeRing(ps []Point) []Point {
+	if len(ps) == 0 || isClosedRing(ps) {
+		return ps
+	}
+	return append(slices.Clip(ps), ps[0])
+}
+
+// dedupeVertices returns ring ps without the vertices that repeat their
+// predecessor, e.g. in exported data, as these would add edges of zero
+// length without a direction. Vertices are compared at the precision of the
+// polygon set, and a last vertex that repeats the first one at this
+// precision is replaced by the first one, so that the ring is closed. If
+// there are no repeated vertices, ps is returned unchanged.
+func dedupeVertices(ps []Point) []Point {
+	same := func(a, b Point) bool { return p2v(a) == p2v(b) }
+	n := len(ps)
+	dup := n > 1 && same(ps[0], ps[n-1]) && ps[0] != ps[n-1]
+	for i := 1; i < n && !dup; i++ {
+		dup = same(ps[i-1], ps[i])
+	}
+	if !dup {
+		return ps
+	}
+	ps = slices.CompactFunc(slices.Clone(ps), same)
+	if n := len(ps); n > 1 && same(ps[0], ps[n-1]) {
+		ps[n-1] = ps[0]
+	}
+	return ps
+}
+
+// openRing returns ring ps without the last vertex if it repeats the first
+// one. Polygon edges are implicitly closed, so a repeated vertex would only
+// add an edge of zero length.
+func openRing(ps []Point) []Point {
+	if len(ps) > 1 && isClosedRing(ps) {
+		return ps[:len(ps)-1]
+	}
+	return ps
 }
 
 // Path finds the shortest path from start to dest within the bounds of the
 // polygons the Pathfinder was initialized with.
 // If dest is outside the polygon set it will be clamped to the nearest
-// polygon edge.
+// polygon edge, unless the Pathfinder was created with WithStrictBounds.
 // The function returns nil if no path exists because start is outside
-// the polygon set.
+// the polygon set. It also returns nil if a coordinate of start or dest is
+// NaN or infinite, e.g. as the result of a division by zero elsewhere.
+//
+// The path is taut: it only turns where it wraps around a polygon corner
+// or another turning point, so pulling it tighter, e.g. with the funnel
+// algorithm over the polygon edges that it crosses, would not remove any
+// of its waypoints.
+//
+// If several shortest paths exist, e.g. around a symmetric obstacle, the
+// choice is deterministic: among equally short ways to reach a waypoint,
+// the one from the predecessor with the smaller X coordinate, or with the
+// smaller Y coordinate for equal X, is taken. Pathfinders created from the
+// same polygons therefore return identical paths, e.g. on all clients of
+// a networked game.
 func (p *Pathfinder) Path(start, dest Point) []Point {
+	path, _ := p.PathContext(context.Background(), start, dest)
+	return path
+}
+
+// PathContext is like Path, but stops the search if ctx is cancelled, e.g.
+// when the query is superseded or a frame deadline has passed. In this case
+// it returns the error of ctx. The context is checked periodically while
+// the visibility graph for the query is prepared and while it is searched.
+// A cancelled query does not update the graph returned by VisibilityGraph.
+func (p *Pathfinder) PathContext(ctx context.Context, start, dest Point) ([]Point, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s := p.getScratch()
+	path, vis, err := p.findPath(ctx, start, dest, s)
+	p.scratchPool.Put(s)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	p.visibilityGraph = vis
+	p.mu.Unlock()
+	return path, nil
+}
+
+// PathReversed is like Path, but returns the path in reverse order, leading
+// from the (possibly clamped) dest back to start. The path is reversed in
+// place, without allocating a second slice.
+func (p *Pathfinder) PathReversed(start, dest Point) []Point {
+	path := p.Path(start, dest)
+	slices.Reverse(path)
+	return path
+}
+
+// PathIter returns an iterator over the waypoints of the path from start to
+// dest as found by Path, e.g. to feed a transformation pipeline without
+// collecting the waypoints into a slice of its own. The search runs each
+// time the iterator is used, not when PathIter is called. It yields nothing
+// if no path exists.
+func (p *Pathfinder) PathIter(start, dest Point) iter.Seq[Point] {
+	return func(yield func(Point) bool) {
+		for _, pt := range p.Path(start, dest) {
+			if !yield(pt) {
+				return
+			}
+		}
+	}
+}
+
+// VisibilityGraph returns the visibility graph that was used by the last
+// Path call, including the start and destination nodes of that call.
+// It returns nil if Path has not been called yet or if the last call did
+// not need a visibility graph.
+// With concurrent Path calls, it is the graph of the call that finished
+// last. The neighbours of each node are in the same order for the same
+// query, regardless of the spatial index: the polygon corners sorted by
+// their X and then their Y coordinate, followed by the start and
+// destination.
+func (p *Pathfinder) VisibilityGraph() map[Point][]Point {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.visibilityGraph
+}
+
+// StaticVisibilityGraph returns the visibility graph of the polygon corners
+// as built by NewPathfinder and updated by the modifications of the
+// Pathfinder, without the start and destination nodes of any path query,
+// e.g. for diagnostics at level load. Each corner at which paths can turn
+// is a key of the map, even if it has no edges. The neighbours of each
+// corner are sorted by their X and then their Y coordinate, so the result
+// is the same for equal polygon sets. It is a copy; its modification does
+// not affect the Pathfinder.
+func (p *Pathfinder) StaticVisibilityGraph() map[Point][]Point {
+	g := make(map[Point][]Point, len(p.cachedGraph))
+	for _, n := range p.concaveVertices {
+		g[n] = nil
+	}
+	for n, adj := range p.cachedGraph {
+		g[n] = slices.Clone(adj)
+	}
+	return g
+}
+
+// NavVertices returns the points at which paths can turn: the concave
+// vertices of the area polygons and the convex vertices of the holes, e.g.
+// to place guards or cover markers, followed by the turning points around
+// the walls given by WithWalls. They are the nodes of the static visibility
+// graph, in the order of the polygons and of the vertices within each
+// polygon. With WithClearance they are the corners mitred outward by the
+// clearance. Nodes added with Graph.Link are not included. The result
+// is a copy; its modification does not affect the Pathfinder.
+func (p *Pathfinder) NavVertices() []Point {
+	return slices.Clone(p.concaveVertices)
+}
+
+// InHole reports whether pt lies inside the polygon with index holeIndex,
+// where the index refers to the polygons the Pathfinder was initialized with.
+// Points on the outline of the hole are not considered inside.
+// InHole returns false if holeIndex is out of range or if the polygon with
+// this index is not a hole.
+func (p *Pathfinder) InHole(pt Point, holeIndex int) bool {
+	if !pt.isFinite() || holeIndex < 0 || holeIndex >= len(p.polygonSet) || !isHole(p.polygonSet, holeIndex) {
+		return false
+	}
+	return p.polygonSet[holeIndex].Contains(p2v(pt), false)
+}
+
+// Contains reports whether pt lies in the accessible area, e.g. whether the
+// mouse cursor is over walkable ground. These are the points that are
+// contained in an odd number of polygons by the nesting rule, i.e. inside
+// an area polygon and not inside one of its holes. Points on the outline of
+// a polygon belong to the accessible side of the outline. Contains is true
+// exactly for the points that Path accepts as start.
+func (p *Pathfinder) Contains(pt Point) bool {
+	return p.ContainmentLevel(pt)%2 == 1
+}
+
+// ContainmentLevel returns the number of polygons that contain pt, i.e. the
+// nesting depth of the region that pt falls into: 0 outside of all
+// polygons, 1 inside a top-level area polygon, 2 inside one of its holes,
+// 3 inside an area polygon within such a hole, and so on. Odd levels are
+// accessible. Points on the outline of a polygon belong to the accessible
+// side of the outline.
+//
+// Path only finds paths between points of the same level, so the level can
+// tell apart e.g. the floors of a layered map, where each floor is nested
+// inside a hole of the floor below.
+func (p *Pathfinder) ContainmentLevel(pt Point) int {
+	if !pt.isFinite() {
+		return 0
+	}
+	return containmentLevel(p.polygonSet, pt)
+}
+
+// PathVertexIndices returns for each waypoint of path the polygon index
+// and the vertex index within this polygon of the polygon vertex that the
+// waypoint corresponds to, e.g. to attach gameplay logic to the corners of
+// a map. Since Path moves waypoints slightly away from the polygon corners,
+// a waypoint matches a vertex within a distance of twice that offset. If
+// several vertices match, the nearest one is reported. Waypoints that do
+// not correspond to a vertex, such as start and dest in the interior of
+// the area, get the indices {-1, -1}.
+func (p *Pathfinder) PathVertexIndices(path []Point) [][2]int {
+	indices := make([][2]int, len(path))
+	for k, pt := range path {
+		indices[k] = [2]int{-1, -1}
+		best := 2 * p.opts.margin
+		for i, ps := range p.polygons {
+			for j, v := range openRing(ps) {
+				if d := nodeDist(v, pt); d < best {
+					best = d
+					indices[k] = [2]int{i, j}
+				}
+			}
+		}
+	}
+	return indices
+}
+
+// CanSee reports whether to is visible from from, i.e. whether the straight
+// line between them stays within the polygon set and is not longer than
+// maxRange.
+func (p *Pathfinder) CanSee(from, to Point, maxRange float64) bool {
+	if !allFinite(from, to) || nodeDist(from, to) > maxRange {
+		return false
+	}
+	return p.inSight(from, to)
+}
+
+// MaybeBlocked is a cheap, conservative test whether the straight line
+// between a and b might be blocked, based only on bounding boxes. It returns
+// true if the bounding box of the line intersects the bounding box of any
+// polygon edge or wall segment near it. A result of false means that the
+// line is definitely clear, provided that a and b lie within the polygon
+// set; true means that the exact test of CanSee is needed. The result is
+// true if a coordinate of a or b is NaN or infinite.
+func (p *Pathfinder) MaybeBlocked(a, b Point) bool {
+	if !allFinite(a, b) {
+		return true
+	}
+	r := queryRect(a, b, 0)
+	for i, ps := range p.polygonSet {
+		if !p.boxes[i].intersects(r) {
+			continue
+		}
+		for j := range ps {
+			e := ps.Edge(j)
+			if queryRect(v2p(e.A), v2p(e.B), 0).intersects(r) {
+				return true
+			}
+		}
+	}
+	for _, w := range p.wallSegments() {
+		if queryRect(w[0], w[1], 0).intersects(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// scratch holds buffers that are reused across the path searches performed
+// by a single goroutine.
+type scratch struct {
+	relevant []Point
+	points   []Point
+	search   aStar
+
+	// sight caches the line of sight between sightOrigin and other points
+	// for queries from the same start, as an outgoing and incoming flag.
+	sightOrigin Point
+	sight       map[Point][2]bool
+}
+
+// getScratch returns scratch buffers from the Pathfinder's pool, so that
+// sequential path searches reuse the buffers of previous searches. The
+// buffers should be returned to the pool after use.
+func (p *Pathfinder) getScratch() *scratch {
+	if s, ok := p.scratchPool.Get().(*scratch); ok {
+		return s
+	}
+	return new(scratch)
+}
+
+// findPath finds the shortest path from start to dest and returns it together
+// with the visibility graph that was used for the search, or the error of
+// ctx if ctx is cancelled. It only reads the
+// Pathfinder's state, so it can be called concurrently as long as each
+// goroutine passes its own scratch buffers.
+func (p *Pathfinder) findPath(ctx context.Context, start, dest Point, s *scratch) ([]Point, graph[Point], error) {
+	path, vis, err := p.searchPath(ctx, start, dest, math.Inf(1), s)
+	offsetPath(p.polygonSet, path, p.opts.margin)
+	return p.simplifyPath(path), vis, err
+}
+
+// searchPath is like findPath, but returns the path through the visibility
+// graph nodes before it is moved away from the polygon outlines. It returns
+// a nil path if the cost of the path would exceed maxCost.
+func (p *Pathfinder) searchPath(ctx context.Context, start, dest Point, maxCost float64, s *scratch) ([]Point, graph[Point], error) {
+	start, dest, ok := p.prepareQuery(start, dest)
+	if !ok {
+		return nil, nil, nil
+	}
+	if p.straightIsCheapest() && p.visible(start, dest) {
+		if nodeDist(start, dest) > maxCost {
+			return nil, nil, nil
+		}
+		vis := make(graph[Point])
+		vis.link(start, dest).link(dest, start)
+		return []Point{start, dest}, vis, nil
+	}
+	visibilityGraph, err := p.prepareVisibilityGraph(ctx, start, dest, s)
+	if err != nil {
+		return nil, nil, err
+	}
+	cost, heuristic := p.travelCost()
+	path, err := s.search.findPathBudget(ctx, visibilityGraph, start, dest, cost, heuristic, maxCost)
+	if err != nil {
+		return nil, nil, err
+	}
+	return path, visibilityGraph, nil
+}
+
+// prepareQuery prepares the start and the destination of a path query as
+// Path does: dest is clamped with clampQueryDest and, with a clearance,
+// both points are pushed away from the walls. The result is false if the
+// query has no path, e.g. because start and dest lie in different areas.
+func (p *Pathfinder) prepareQuery(start, dest Point) (Point, Point, bool) {
+	if !allFinite(start, dest) {
+		// The geometry routines are not prepared for non-finite
+		// coordinates, e.g. clamping a NaN destination never succeeds.
+		return start, dest, false
+	}
+	dest, ok := p.clampQueryDest(start, dest)
+	if !ok {
+		return start, dest, false
+	}
 	if containmentLevel(p.polygonSet, start) != containmentLevel(p.polygonSet, dest) {
-		return nil
+		return start, dest, false
 	}
-	d := p2v(dest)
-	if !p.polygonSet.Contains(d) {
-		dest = ensureInside(p.polygonSet, v2p(p.polygonSet.ClosestPt(d)))
+	if p.opts.clearance > 0 {
+		var okStart, okDest bool
+		start, okStart = p.keepClear(start)
+		dest, okDest = p.keepClear(dest)
+		if !okStart || !okDest {
+			return start, dest, false
+		}
 	}
-	if inLineOfSight(p.polygonSet, p2v(start), p2v(dest)) {
-		return []Point{start, dest}
+	return start, dest, true
+}
+
+// finishPath moves the path found by a query away from the outlines of the
+// polygon set ps and simplifies it as findPath does, where visible decides
+// which shortcuts the simplification may take.
+func (p *Pathfinder) finishPath(ps poly.PolygonSet, path []Point, visible func(a, b Point) bool) []Point {
+	offsetPath(ps, path, p.opts.margin)
+	if !p.opts.simplify {
+		return path
 	}
-	visibilityGraph := p.prepareVisibilityGraph(start, dest)
-	path := astar.FindPath[Point](visibilityGraph, start, dest, nodeDist, nodeDist)
-	for i := 1; i < len(path)-1; i++ {
-		path[i] = offsetFromBoundary(p.polygonSet, path[i])
+	return keepWaypoints(path, simplifyMask(path, p.opts.simplifyEps, visible))
+}
+
+// NearestWalkable returns the point of the accessible area nearest to pt,
+// e.g. to snap a cursor to the walkable area before requesting a path, and
+// whether pt already lies inside of it. A point outside is moved to the
+// nearest polygon edge and then slightly into the accessible area, just
+// like Path clamps its destination. If a coordinate of pt is NaN or
+// infinite, there is no nearest point and the result is the zero Point and
+// false.
+func (p *Pathfinder) NearestWalkable(pt Point) (Point, bool) {
+	if !pt.isFinite() {
+		return Point{}, false
 	}
-	return path
+	clamped := p.clamp(pt)
+	return clamped, clamped == pt
 }
 
-func ensureInside(ps poly.PolygonSet, pt Point) Point {
-	if ps.Contains(p2v(pt)) {
+// clamp moves a point outside of the polygon set to the nearest point inside.
+// Points inside the polygon set are returned unchanged.
+func (p *Pathfinder) clamp(pt Point) Point {
+	v := p2v(pt)
+	if p.polygonSet.Contains(v) {
 		return pt
 	}
-adjustment:
-	for dx := -1; dx <= 1; dx++ {
-		for dy := -1; dy <= 1; dy++ {
-			if dx == 0 && dy == 0 {
+	return ensureInside(p.polygonSet, v2p(p.polygonSet.ClosestPt(v)), p.opts.margin)
+}
+
+// clampQueryDest clamps the destination of a path query from start like
+// clampDest and reports it to the function registered with OnClamp. It
+// reports false if dest lies outside of the polygon set and the Pathfinder
+// was created with WithStrictBounds.
+func (p *Pathfinder) clampQueryDest(start, dest Point) (Point, bool) {
+	clamped := p.clampDest(start, dest)
+	if clamped == dest {
+		return dest, true
+	}
+	if p.opts.strictBounds {
+		return dest, false
+	}
+	if p.opts.onClamp != nil {
+		p.opts.onClamp(dest, clamped)
+	}
+	return clamped, true
+}
+
+// clampDest moves a destination outside of the polygon set to a point
+// inside for a path query from start, either to the nearest point or
+// along the line from start, as configured by ClampTowardStart.
+// Destinations inside the polygon set are returned unchanged.
+func (p *Pathfinder) clampDest(start, dest Point) Point {
+	if !p.opts.clampToStart || p.polygonSet.Contains(p2v(dest)) {
+		return p.clamp(dest)
+	}
+	if pt, ok := p.lastExit(start, dest); ok {
+		return pt
+	}
+	return p.clamp(dest)
+}
+
+// lastExit returns the point just before the line segment from start to
+// dest leaves the accessible area for the last time, i.e. the crossing
+// with a polygon edge nearest to dest that has the accessible area on the
+// side of start, moved back toward start by the margin. It reports false if
+// the segment does not leave the accessible area.
+func (p *Pathfinder) lastExit(start, dest Point) (Point, bool) {
+	back := start.Sub(dest).Norm().Mul(p.opts.margin)
+	var exit Point
+	best := math.Inf(1)
+	for _, ps := range p.polygonSet {
+		for j := range ps {
+			e := ps.Edge(j)
+			x, ok := SegmentsIntersect(start, dest, v2p(e.A), v2p(e.B))
+			if !ok || x.Dist(dest) >= best {
 				continue
 			}
-			npt := pt.Add(Point{X: float64(dx) * margin, Y: float64(dy) * margin})
-			if ps.Contains(p2v(npt)) {
-				pt = npt
-				break adjustment
+			if pt := x.Add(back); strictlyInside(p.polygonSet, pt) {
+				exit, best = pt, x.Dist(dest)
+			}
+		}
+	}
+	return exit, !math.IsInf(best, 1)
+}
+
+// ensureInside moves a point that lies on or just outside the boundary of
+// the polygon set to a nearby point strictly inside. It probes the eight
+// neighbouring positions at a distance of margin first and then repeats
+// with doubled distances, spiralling outwards until an inside point is found
+// or the distance exceeds margin<<maxNudgeSteps. In the latter case pt is
+// returned unchanged.
+func ensureInside(ps poly.PolygonSet, pt Point, margin float64) Point {
+	if strictlyInside(ps, pt) {
+		return pt
+	}
+	for step := range maxNudgeSteps + 1 {
+		dist := margin * float64(int(1)<<step)
+		for dx := -1; dx <= 1; dx++ {
+			for dy := -1; dy <= 1; dy++ {
+				if dx == 0 && dy == 0 {
+					continue
+				}
+				npt := pt.Add(Point{X: float64(dx) * dist, Y: float64(dy) * dist})
+				if strictlyInside(ps, npt) {
+					return npt
+				}
 			}
 		}
 	}
 	return pt
 }
 
-func concaveVertices(ps poly.PolygonSet) []Point {
-	var vs []Point
-	for i, p := range ps {
-		t := concave
-		if isHole(ps, i) {
-			t = convex
+// maxNudgeSteps is the number of times ensureInside doubles the distance
+// of its probes before giving up.
+const maxNudgeSteps = 10
+
+// strictlyInside reports whether pt lies inside the polygon set and not on
+// any of its polygon outlines.
+func strictlyInside(ps poly.PolygonSet, pt Point) bool {
+	v := p2v(pt)
+	in := false
+	for _, p := range ps {
+		if p.Contains(v, false) {
+			in = !in
 		}
-		vs = append(vs, verticesOfType(p, t)...)
+	}
+	return in && !onBoundary(ps, v)
+}
+
+// onBoundary reports whether v lies on the outline of any polygon in ps.
+func onBoundary(ps poly.PolygonSet, v geom.Vec2) bool {
+	for _, p := range ps {
+		for i := range p {
+			if p.Edge(i).ClosestPt(v).NearEq(v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// turningPoints returns the points at which paths can turn around the
+// polygon with index i in ps, which was converted from polygons[i]. These
+// are the vertices reported by polygonConcaveVertices or, with a clearance,
+// the corresponding mitred vertices.
+func turningPoints(polygons [][]Point, ps poly.PolygonSet, i int, o options) []Point {
+	if o.clearance > 0 {
+		return mitredVertices(openRing(polygons[i]), concaveIndices(ps, i, o.concaveThreshold), o.clearance)
+	}
+	return polygonConcaveVertices(ps, i, o.concaveThreshold)
+}
+
+// polygonConcaveVertices returns the vertices of the polygon with index i
+// in ps at which paths can turn: the concave vertices of an area polygon or
+// the convex vertices of a hole. Vertices at which the outline turns by no
+// more than threshold radians are left out.
+func polygonConcaveVertices(ps poly.PolygonSet, i int, threshold float64) []Point {
+	var vs []Point
+	for _, j := range concaveIndices(ps, i, threshold) {
+		vs = append(vs, v2p(ps[i][j]))
 	}
 	return vs
 }
 
+// concaveIndices is like polygonConcaveVertices, but returns the indices
+// of the vertices within the polygon.
+func concaveIndices(ps poly.PolygonSet, i int, threshold float64) []int {
+	t := concave
+	if isHole(ps, i) {
+		t = convex
+	}
+	p := ps[i]
+	orient := p.Orientation()
+	var indices []int
+	for j := range p {
+		if isReflexAt(p, j, orient) != (t == concave) {
+			continue
+		}
+		if threshold > 0 && math.Abs(turnAngle(p, j)) <= threshold {
+			continue
+		}
+		indices = append(indices, j)
+	}
+	return indices
+}
+
+// turnAngle returns the angle in radians by which the outline of polygon p
+// turns at its vertex with index i, between -Pi and Pi.
+func turnAngle(p poly.Polygon, i int) float64 {
+	v := v2p(p[i])
+	left := v.Sub(v2p(p[p.WrapIndex(i-1)]))
+	right := v2p(p[p.WrapIndex(i+1)]).Sub(v)
+	return math.Atan2(cross(left, right), left.X*right.X+left.Y*right.Y)
+}
+
+// reachableVertices returns the turning points of the polygons that can be
+// part of a path within the outermost areas, the areas that do not lie
+// inside of a hole, according to the nesting in p.depths. Left out are:
+//   - the turning points of polygons nested inside of a hole, e.g. an
+//     obstacle drawn within another obstacle on an imported map, which
+//     form a region disconnected from the outermost areas,
+//   - polygon vertices that border on no accessible space, e.g. the
+//     corners of a hole sticking out of its area polygon.
+func (p *Pathfinder) reachableVertices() []Point {
+	var reachable []Point
+	for i, vs := range p.concaveOf {
+		for _, v := range vs {
+			if len(p.polygonSet[i]) == 0 {
+				// The vertices of weighted regions lie strictly inside of
+				// the polygon set.
+				if containmentLevel(p.polygonSet, v) == 1 {
+					reachable = append(reachable, v)
+				}
+				continue
+			}
+			if p.depths[i] <= 1 && offsetFromBoundary(p.polygonSet, v, p.opts.margin) != v {
+				reachable = append(reachable, v)
+			}
+		}
+	}
+	return reachable
+}
+
 func isHole(ps poly.PolygonSet, i int) bool {
+	if len(ps[i]) == 0 {
+		return false
+	}
 	hole := false
 	for j, p := range ps {
 		if i != j && p.Contains(ps[i][0], false) {
@@ -126,11 +794,14 @@ func isHole(ps poly.PolygonSet, i int) bool {
 	return hole
 }
 
+// containmentLevel returns the number of polygons that contain pt. A point
+// on the outline of a polygon belongs to the accessible side of the outline,
+// i.e. it counts as inside an area polygon, but outside a hole.
 func containmentLevel(ps poly.PolygonSet, pt Point) int {
 	level := 0
 	v := p2v(pt)
-	for _, p := range ps {
-		if p.Contains(v, true) {
+	for i, p := range ps {
+		if p.Contains(v, false) || (p.Contains(v, true) && !isHole(ps, i)) {
 			level++
 		}
 	}
@@ -145,24 +816,42 @@ const (
 )
 
 func verticesOfType(p poly.Polygon, t vertexType) []Point {
+	orient := p.Orientation()
 	var vs []Point
 	for i, v := range p {
-		isConcave := p.IsConcaveAt(i)
-		if (t == concave && isConcave) || (t == convex && !isConcave) {
+		if isReflexAt(p, i, orient) == (t == concave) {
 			vs = append(vs, v2p(v))
 		}
 	}
 	return vs
 }
 
-func visibilityGraph(ps poly.PolygonSet, points []Point) graph[Point] {
+// isReflexAt reports whether the inner angle of polygon p at its vertex
+// with index i is greater than 180 degrees, given the orientation of p as
+// returned by its Orientation method. This way the vertices are classified
+// correctly regardless of the winding of the polygon. Vertices at which the
+// outline goes straight on are not reflex.
+func isReflexAt(p poly.Polygon, i, orient int) bool {
+	if orient > 0 {
+		return p.IsConcaveAt(i)
+	}
+	left := p[i].Sub(p[p.WrapIndex(i-1)])
+	right := p[p.WrapIndex(i+1)].Sub(p[i])
+	return left.CrossLen(right) > 0
+}
+
+// visibilityGraph links each pair of points that are in line of sight of
+// each other within the polygon set ps without crossing one of the walls,
+// given as rings by wallRings.
+func visibilityGraph(ps, walls poly.PolygonSet, points []Point) graph[Point] {
+	boxes := polygonBoxes(ps)
 	vis := make(graph[Point])
 	for i, a := range points {
 		for j, b := range points {
 			if i == j {
 				continue
 			}
-			if inLineOfSight(ps, p2v(a), p2v(b)) {
+			if inLineOfSightBoxed(ps, boxes, p2v(a), p2v(b)) && !crossesWall(walls, p2v(a), p2v(b)) {
 				vis.link(a, b)
 			}
 		}
@@ -170,6 +859,55 @@ func visibilityGraph(ps poly.PolygonSet, points []Point) graph[Point] {
 	return vis
 }
 
+// polygonBoxes returns the bounding rectangles of the polygons of ps.
+func polygonBoxes(ps poly.PolygonSet) []rect {
+	boxes := make([]rect, len(ps))
+	for i, p := range ps {
+		boxes[i] = boundingRect([][]Point{convert(p, v2p)})
+	}
+	return boxes
+}
+
+// inLineOfSightBoxed is like inLineOfSight, but only tests the polygons
+// whose bounding rectangles in boxes overlap with the bounding rectangle of
+// the line of sight for crossings. The result is the same.
+func inLineOfSightBoxed(ps poly.PolygonSet, boxes []rect, start, end geom.Vec2) bool {
+	// The rectangle is padded to be safe from rounding errors in the
+	// crossing tests near its border.
+	const eps = 1e-5
+	r := queryRect(v2p(start), v2p(end), eps)
+	lineOfSight := poly.LineSeg{A: start, B: end}
+	for i, p := range ps {
+		if boxes[i].intersects(r) && p.IsCrossedBy(lineOfSight) {
+			return false
+		}
+	}
+	return containsBoxed(ps, boxes, lineOfSight.Middle()) && !leavesAtVertices(ps, lineOfSight)
+}
+
+// containsBoxed is like the Contains method of ps, but skips the polygons
+// whose bounding rectangles in boxes do not contain pt. The result is the
+// same.
+func containsBoxed(ps poly.PolygonSet, boxes []rect, pt geom.Vec2) bool {
+	const eps = 1e-4
+	r := queryRect(v2p(pt), v2p(pt), eps)
+	in := false
+	for i, p := range ps {
+		if boxes[i].intersects(r) && p.Contains(pt, false) {
+			in = !in
+		}
+	}
+	if in {
+		return true
+	}
+	for i, p := range ps {
+		if boxes[i].intersects(r) && !p.Contains(pt, false) && p.Contains(pt, true) {
+			return true
+		}
+	}
+	return false
+}
+
 func inLineOfSight(ps poly.PolygonSet, start, end geom.Vec2) bool {
 	lineOfSight := poly.LineSeg{A: start, B: end}
 	for _, p := range ps {
@@ -177,17 +915,72 @@ func inLineOfSight(ps poly.PolygonSet, start, end geom.Vec2) bool {
 			return false
 		}
 	}
-	return ps.Contains(lineOfSight.Middle())
+	return ps.Contains(lineOfSight.Middle()) && !leavesAtVertices(ps, lineOfSight)
+}
+
+// leavesAtVertices reports whether line segment ls leaves the polygon set
+// between polygon vertices that lie on it, e.g. a diagonal through two
+// opposite corners of a rectangular hole. IsCrossedBy does not detect this,
+// because touching a vertex is not a proper crossing.
+func leavesAtVertices(ps poly.PolygonSet, ls poly.LineSeg) bool {
+	const eps = 1e-5
+	d := ls.B.Sub(ls.A)
+	dd := d.Dot(d)
+	if dd == 0 {
+		return false
+	}
+	minX, maxX := min(ls.A.X, ls.B.X)-eps, max(ls.A.X, ls.B.X)+eps
+	minY, maxY := min(ls.A.Y, ls.B.Y)-eps, max(ls.A.Y, ls.B.Y)+eps
+	var ts []float32
+	for _, p := range ps {
+		for _, v := range p {
+			if v.X < minX || v.X > maxX || v.Y < minY || v.Y > maxY {
+				continue
+			}
+			if v.NearEq(ls.A) || v.NearEq(ls.B) || !ls.ClosestPt(v).NearEq(v) {
+				continue
+			}
+			ts = append(ts, v.Sub(ls.A).Dot(d)/dd)
+		}
+	}
+	if len(ts) == 0 {
+		return false
+	}
+	slices.Sort(ts)
+	prev := float32(0)
+	for _, t := range append(ts, 1) {
+		if t > prev && !ps.Contains(ls.A.Add(d.Mul((prev+t)/2))) {
+			return true
+		}
+		prev = t
+	}
+	return false
 }
 
 // nodeDist is the cost function for the A* algorithm. The visibility graph has
 // 2d points as nodes, so we calculate the Euclidean distance.
 func nodeDist(a, b Point) float64 {
-	c := a.Sub(b)
-	return math.Sqrt(c.X*c.X + c.Y*c.Y)
+	return a.Dist(b)
 }
 
-func offsetFromBoundary(ps poly.PolygonSet, pt Point) Point {
+// offsetPath moves the inner points of path, which are polygon vertices,
+// away from the polygon outlines by margin with offsetFromBoundary. A point
+// keeps its exact vertex position if the moved point would not be in line
+// of sight of its neighbours, which can happen when a path segment grazes
+// another corner.
+func offsetPath(ps poly.PolygonSet, path []Point, margin float64) {
+	for i := 1; i < len(path)-1; i++ {
+		moved := offsetFromBoundary(ps, path[i], margin)
+		if inLineOfSight(ps, p2v(path[i-1]), p2v(moved)) && inLineOfSight(ps, p2v(moved), p2v(path[i+1])) {
+			path[i] = moved
+		}
+	}
+}
+
+// offsetFromBoundary moves pt, if it is a polygon vertex, by margin along
+// the bisector of its corner into the accessible area. Other points are
+// returned unchanged.
+func offsetFromBoundary(ps poly.PolygonSet, pt Point, margin float64) Point {
 	v := p2v(pt)
 	for pi, p := range ps {
 		orient := p.Orientation()
@@ -209,7 +1002,9 @@ func offsetFromBoundary(ps poly.PolygonSet, pt Point) Point {
 
 				bis := n1.Add(n2)
 				if bis.Len() == 0 {
-					bis = n1
+					// The boundary turns back on itself, e.g. at the
+					// end of a two-vertex wall: move beyond the tip.
+					bis = e1
 				}
 				bis = bis.Norm().Mul(float32(margin))
 				if hole {
@@ -229,10 +1024,22 @@ func offsetFromBoundary(ps poly.PolygonSet, pt Point) Point {
 	return pt
 }
 
-func (p *Pathfinder) prepareVisibilityGraph(start, dest Point) graph[Point] {
+func (p *Pathfinder) prepareVisibilityGraph(ctx context.Context, start, dest Point, s *scratch) (graph[Point], error) {
 	radius := nodeDist(start, dest)
+	if p.hasRegions() {
+		// A path that costs less than the weighted length of the direct
+		// line cannot be longer than this cost divided by the smallest
+		// weight.
+		radius = p.weightedLength(start, dest) / p.minWeight()
+	}
 	r := queryRect(start, dest, radius)
-	relevant := p.index.rangeSearch(r)
+	s.relevant = s.relevant[:0]
+	p.index.query(r, &s.relevant)
+	// The order of the query result depends on the index. Sorting it keeps
+	// the edges to start and dest, which are appended to the neighbours of
+	// the cached graph, in a deterministic order.
+	slices.SortFunc(s.relevant, comparePoints)
+	relevant := s.relevant
 	set := make(map[Point]bool, len(relevant))
 	for _, pt := range relevant {
 		set[pt] = true
@@ -242,33 +1049,51 @@ func (p *Pathfinder) prepareVisibilityGraph(start, dest Point) graph[Point] {
 	vis[start] = vis[start]
 	vis[dest] = vis[dest]
 
-	points := append([]Point(nil), relevant...)
-	points = append(points, dest)
-	for _, b := range points {
-		if b != start && inLineOfSight(p.polygonSet, p2v(start), p2v(b)) {
-			vis.link(start, b)
-		}
-		if b != start && inLineOfSight(p.polygonSet, p2v(b), p2v(start)) {
-			vis.link(b, start)
-		}
+	// Start and dest may coincide with vertices of the graph, e.g. if a
+	// path starts exactly at a corner. Such a node already has its edges to
+	// the other vertices and must not be linked a second time.
+	s.points = append(s.points[:0], relevant...)
+	if !set[dest] {
+		s.points = append(s.points, dest)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var sight map[Point][2]bool
+	if s.sight != nil && s.sightOrigin == start {
+		sight = s.sight
 	}
+	p.linkNode(vis, start, s.points, set, sight)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	p.linkNode(vis, dest, relevant, set, nil)
+
+	return vis, nil
+}
 
-	points = append(relevant, start)
+// linkNode links node pt with each of the points that is in line of sight,
+// unless both are vertices of the cached visibility graph. If sight is not
+// nil, the lines of sight are looked up in and added to this cache.
+func (p *Pathfinder) linkNode(vis graph[Point], pt Point, points []Point, vertices map[Point]bool, sight map[Point][2]bool) {
 	for _, b := range points {
-		if b != dest && inLineOfSight(p.polygonSet, p2v(dest), p2v(b)) {
-			vis.link(dest, b)
+		if b == pt || (vertices[pt] && vertices[b]) {
+			continue
 		}
-		if b != dest && inLineOfSight(p.polygonSet, p2v(b), p2v(dest)) {
-			vis.link(b, dest)
+		visible, ok := sight[b]
+		if !ok {
+			visible = [2]bool{p.visible(pt, b), p.visible(b, pt)}
+			if sight != nil {
+				sight[b] = visible
+			}
+		}
+		if visible[0] {
+			vis.link(pt, b)
+		}
+		if visible[1] {
+			vis.link(b, pt)
 		}
 	}
-
-	if inLineOfSight(p.polygonSet, p2v(start), p2v(dest)) {
-		vis.link(start, dest)
-		vis.link(dest, start)
-	}
-
-	return vis
 }
 
 func copyGraph(src graph[Point]) graph[Point] {
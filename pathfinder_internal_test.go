@@ -0,0 +1,114 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/fzipp/pathfind/internal/poly"
+)
+
+func TestEnsureInside(t *testing.T) {
+	square := poly.PolygonSet{ps2vs([]Point{Pt(0, 0), Pt(10, 0), Pt(10, 10), Pt(0, 10)})}
+	tests := []struct {
+		name    string
+		pt      Point
+		maxDist float64
+	}{
+		{"inside", Pt(5, 5), 0},
+		{"on edge", Pt(5, 0), 2 * defaultMargin},
+		{"on corner", Pt(10, 10), 2 * defaultMargin},
+		{"outside by more than margin", Pt(5, -0.01), 0.03},
+		{"diagonally outside", Pt(10.05, 10.05), 0.15},
+		{"far outside corner", Pt(10.5, -0.5), 1.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ensureInside(square, tt.pt, defaultMargin)
+			if !strictlyInside(square, got) {
+				t.Errorf("ensureInside(%v) = %v, want point strictly inside", tt.pt, got)
+			}
+			if d := nodeDist(got, tt.pt); d > tt.maxDist {
+				t.Errorf("ensureInside(%v) = %v moved point by %g, want at most %g",
+					tt.pt, got, d, tt.maxDist)
+			}
+		})
+	}
+}
+
+func TestEnsureInsideGivesUp(t *testing.T) {
+	square := poly.PolygonSet{ps2vs([]Point{Pt(0, 0), Pt(10, 0), Pt(10, 10), Pt(0, 10)})}
+	pt := Pt(-100, -100)
+	if got := ensureInside(square, pt, defaultMargin); got != pt {
+		t.Errorf("ensureInside(%v) = %v, want point unchanged", pt, got)
+	}
+}
+
+func TestReachableVertices(t *testing.T) {
+	// A square area with a hole that sticks out of its right edge and
+	// a hole that lies completely inside. The inner hole contains an island
+	// with a hole of its own, which no path from the outer area can reach.
+	polygons := [][]Point{
+		{Pt(0, 0), Pt(40, 0), Pt(40, 40), Pt(0, 40)},
+		{Pt(30, 10), Pt(50, 10), Pt(50, 30), Pt(30, 30)},
+		{Pt(5, 5), Pt(15, 5), Pt(15, 15), Pt(5, 15)},
+		{Pt(6, 6), Pt(14, 6), Pt(14, 14), Pt(6, 14)},
+		{Pt(8, 8), Pt(12, 8), Pt(12, 12), Pt(8, 12)},
+	}
+	all := NewPathfinder(polygons).concaveVertices
+	pruned := NewPathfinder(polygons, WithPruneUnreachable()).concaveVertices
+	for _, v := range []Point{Pt(50, 10), Pt(50, 30), Pt(8, 8), Pt(12, 12)} {
+		if !slices.Contains(all, v) {
+			t.Fatalf("vertex %v missing without pruning", v)
+		}
+		if slices.Contains(pruned, v) {
+			t.Errorf("unreachable vertex %v not pruned", v)
+		}
+	}
+	for _, v := range []Point{Pt(30, 10), Pt(30, 30), Pt(5, 5), Pt(15, 15)} {
+		if !slices.Contains(pruned, v) {
+			t.Errorf("reachable vertex %v pruned", v)
+		}
+	}
+}
+
+func TestReachableVerticesWeighted(t *testing.T) {
+	square := []Point{Pt(0, 0), Pt(40, 0), Pt(40, 40), Pt(0, 40)}
+	mud := []Point{Pt(10, 10), Pt(30, 10), Pt(30, 30), Pt(10, 30)}
+	pruned := NewWeightedPathfinder([]WeightedPolygon{
+		{Points: square},
+		{Points: mud, Weight: 3},
+	}, WithPruneUnreachable()).concaveVertices
+	for _, v := range mud {
+		if !slices.Contains(pruned, v) {
+			t.Errorf("vertex %v of weighted region pruned", v)
+		}
+	}
+}
+
+func TestInLineOfSightBoxed(t *testing.T) {
+	polygons := [][]Point{
+		{Pt(0, 0), Pt(40, 0), Pt(40, 40), Pt(0, 40)},
+		{Pt(5, 5), Pt(15, 5), Pt(15, 15), Pt(5, 15)},
+		{Pt(20, 5), Pt(35, 5), Pt(35, 10), Pt(25, 10), Pt(25, 35), Pt(20, 35)},
+		{Pt(5, 20), Pt(15, 20), Pt(10, 30)},
+	}
+	ps := convert(polygons, toPolygon)
+	boxes := polygonBoxes(ps)
+	var points []Point
+	for _, polygon := range polygons {
+		points = append(points, polygon...)
+	}
+	points = append(points, Pt(2, 2), Pt(17, 17), Pt(30, 30), Pt(10, 5), Pt(50, 50))
+	for _, a := range points {
+		for _, b := range points {
+			want := inLineOfSight(ps, p2v(a), p2v(b))
+			if got := inLineOfSightBoxed(ps, boxes, p2v(a), p2v(b)); got != want {
+				t.Errorf("inLineOfSightBoxed(%v, %v) = %v, want %v", a, b, got, want)
+			}
+		}
+	}
+}
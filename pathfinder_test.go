@@ -5,7 +5,13 @@
 package pathfind_test
 
 import (
+	"cmp"
+	"context"
+	"errors"
+	"math"
 	"reflect"
+	"slices"
+	"sync"
 	"testing"
 
 	"github.com/fzipp/pathfind"
@@ -200,7 +206,7 @@ func TestPathfinderPath(t *testing.T) {
 			dest:  pathfind.Pt(181, 54),
 			want: []pathfind.Point{
 				pathfind.Pt(180, 60),
-				pathfind.Pt(180, 55),
+				pathfind.Pt(181, 54.385),
 			},
 		},
 		{
@@ -216,7 +222,7 @@ func TestPathfinderPath(t *testing.T) {
 			dest:  pathfind.Pt(74, 98),
 			want: []pathfind.Point{
 				pathfind.Pt(90, 100),
-				pathfind.Pt(75, 97),
+				pathfind.Pt(74.141, 97.994),
 			},
 		},
 	}
@@ -224,7 +230,7 @@ func TestPathfinderPath(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			pathfinder := pathfind.NewPathfinder(tt.polygons)
 			got := pathfinder.Path(tt.start, tt.dest)
-			if !reflect.DeepEqual(got, tt.want) {
+			if !pathNearEq(got, tt.want) {
 				t.Errorf(`%s
 polygons: %v
 Path(%v, %v)
@@ -279,3 +285,1124 @@ want: %v`,
 		})
 	}
 }
+
+func TestPathfinderStaticVisibilityGraph(t *testing.T) {
+	pathfinder := pathfind.NewPathfinder(polygonU)
+	want := map[pathfind.Point][]pathfind.Point{
+		pathfind.Pt(10, 10): {pathfind.Pt(20, 10)},
+		pathfind.Pt(20, 10): {pathfind.Pt(10, 10)},
+	}
+	got := pathfinder.StaticVisibilityGraph()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("StaticVisibilityGraph()\n got: %v\nwant: %v", got, want)
+	}
+
+	// Neither path queries nor modifications of the result affect it.
+	pathfinder.Path(pathfind.Pt(5, 5), pathfind.Pt(25, 5))
+	got[pathfind.Pt(10, 10)][0] = pathfind.Pt(0, 0)
+	delete(got, pathfind.Pt(20, 10))
+	if got := pathfinder.StaticVisibilityGraph(); !reflect.DeepEqual(got, want) {
+		t.Errorf("StaticVisibilityGraph() after modification\n got: %v\nwant: %v", got, want)
+	}
+}
+
+func TestPathfinderVisibilityGraphOrder(t *testing.T) {
+	start, dest := pathfind.Pt(0.5, 0.5), pathfind.Pt(29.5, 29.5)
+	var wantStatic, wantQuery map[pathfind.Point][]pathfind.Point
+	for i := range 10 {
+		pathfinder := pathfind.NewPathfinder(gridOfHoles(3), pathfind.WithSpatialHash(2))
+		static := pathfinder.StaticVisibilityGraph()
+		for n, adj := range static {
+			if !slices.IsSortedFunc(adj, func(a, b pathfind.Point) int {
+				return cmp.Or(cmp.Compare(a.X, b.X), cmp.Compare(a.Y, b.Y))
+			}) {
+				t.Fatalf("StaticVisibilityGraph() neighbours of %v not sorted: %v", n, adj)
+			}
+		}
+		pathfinder.Path(start, dest)
+		query := pathfinder.VisibilityGraph()
+		if i == 0 {
+			wantStatic, wantQuery = static, query
+			continue
+		}
+		if !reflect.DeepEqual(static, wantStatic) {
+			t.Errorf("StaticVisibilityGraph() differs between equal Pathfinders\n got: %v\nwant: %v", static, wantStatic)
+		}
+		if !reflect.DeepEqual(query, wantQuery) {
+			t.Errorf("VisibilityGraph() differs between equal queries\n got: %v\nwant: %v", query, wantQuery)
+		}
+	}
+}
+
+func TestPathfinderPathTieBreak(t *testing.T) {
+	tests := []struct {
+		name        string
+		start, dest pathfind.Point
+		want        []pathfind.Point
+	}{
+		{
+			name:  "diagonal up",
+			start: pathfind.Pt(5, 35),
+			dest:  pathfind.Pt(35, 5),
+			want:  []pathfind.Point{pathfind.Pt(5, 35), pathfind.Pt(10, 20), pathfind.Pt(20, 10), pathfind.Pt(35, 5)},
+		},
+		{
+			name:  "diagonal down",
+			start: pathfind.Pt(5, 5),
+			dest:  pathfind.Pt(35, 35),
+			want:  []pathfind.Point{pathfind.Pt(5, 5), pathfind.Pt(10, 20), pathfind.Pt(20, 30), pathfind.Pt(35, 35)},
+		},
+		{
+			name:  "diagonal down reversed",
+			start: pathfind.Pt(35, 35),
+			dest:  pathfind.Pt(5, 5),
+			want:  []pathfind.Point{pathfind.Pt(35, 35), pathfind.Pt(20, 30), pathfind.Pt(10, 20), pathfind.Pt(5, 5)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := range 10 {
+				opts := []pathfind.Option{pathfind.WithSpatialHash(float64(1 + i))}
+				if i%2 == 0 {
+					opts = nil
+				}
+				pathfinder := pathfind.NewPathfinder(polygonO, opts...)
+				if got := pathfinder.Path(tt.start, tt.dest); !pathNearEq(got, tt.want) {
+					t.Fatalf("Path(%v, %v) #%d\n got: %v\nwant: %v", tt.start, tt.dest, i, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestPathfinderNavVertices(t *testing.T) {
+	tests := []struct {
+		name     string
+		polygons [][]pathfind.Point
+		want     []pathfind.Point
+	}{
+		{
+			name:     "concave corners of area",
+			polygons: polygonU,
+			want:     []pathfind.Point{pathfind.Pt(10, 10), pathfind.Pt(20, 10)},
+		},
+		{
+			name:     "convex corners of hole",
+			polygons: polygonO,
+			want:     []pathfind.Point{pathfind.Pt(20, 10), pathfind.Pt(30, 20), pathfind.Pt(20, 30), pathfind.Pt(10, 20)},
+		},
+		{
+			name:     "convex area",
+			polygons: [][]pathfind.Point{polygonO[0]},
+			want:     nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pathfinder := pathfind.NewPathfinder(tt.polygons)
+			got := pathfinder.NavVertices()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NavVertices() = %v, want %v", got, tt.want)
+			}
+			if len(got) > 0 {
+				got[0] = pathfind.Pt(-1, -1)
+				if again := pathfinder.NavVertices(); !reflect.DeepEqual(again, tt.want) {
+					t.Errorf("NavVertices() after modification = %v, want %v", again, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestPathfinderGraph(t *testing.T) {
+	pathfinder := pathfind.NewPathfinder(polygonU)
+	g := pathfinder.Graph()
+	corner := pathfind.Pt(10, 10)
+	if got, want := g.Neighbors(corner), []pathfind.Point{pathfind.Pt(20, 10)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Neighbors(%v) = %v, want %v", corner, got, want)
+	}
+
+	// A jump link over the wall between the arms of the U.
+	start, dest := pathfind.Pt(2, 2), pathfind.Pt(28, 2)
+	g.Link(pathfind.Pt(5, 5), pathfind.Pt(25, 5))
+	got := pathfinder.Path(start, dest)
+	want := []pathfind.Point{start, pathfind.Pt(5, 5), pathfind.Pt(25, 5), dest}
+	if !pathNearEq(got, want) {
+		t.Errorf("Path(%v, %v) with jump link\n got: %v\nwant: %v", start, dest, got, want)
+	}
+
+	g.Unlink(pathfind.Pt(5, 5), pathfind.Pt(25, 5))
+	g.Unlink(corner, pathfind.Pt(20, 10))
+	if got := pathfinder.Path(start, dest); got != nil {
+		t.Errorf("Path(%v, %v) without links = %v, want nil", start, dest, got)
+	}
+}
+
+func TestPathfinderPathFromVertex(t *testing.T) {
+	pathfinder := pathfind.NewPathfinder(polygonU)
+	start, dest := pathfind.Pt(10, 10), pathfind.Pt(25, 5)
+	got := pathfinder.Path(start, dest)
+	want := []pathfind.Point{start, pathfind.Pt(20, 10), dest}
+	if !pathNearEq(got, want) {
+		t.Fatalf("Path(%v, %v)\n got: %v\nwant: %v", start, dest, got, want)
+	}
+	want = []pathfind.Point{dest, pathfind.Pt(20, 10), start}
+	if got := pathfinder.Path(dest, start); !pathNearEq(got, want) {
+		t.Fatalf("Path(%v, %v)\n got: %v\nwant: %v", dest, start, got, want)
+	}
+	for n, adj := range pathfinder.VisibilityGraph() {
+		seen := make(map[pathfind.Point]bool)
+		for _, m := range adj {
+			if seen[m] {
+				t.Errorf("duplicate edge from %v to %v in visibility graph", n, m)
+			}
+			seen[m] = true
+		}
+	}
+}
+
+// pathNearEq reports whether paths a and b have the same number of points
+// and whether the corresponding points are approximately equal. Waypoints
+// at polygon corners are offset from the boundary by a small margin, so
+// they do not exactly match the corner coordinates.
+func pathNearEq(a, b []pathfind.Point) bool {
+	const tolerance = 0.01
+	if len(a) != len(b) || (a == nil) != (b == nil) {
+		return false
+	}
+	for i := range a {
+		if math.Abs(a[i].X-b[i].X) > tolerance || math.Abs(a[i].Y-b[i].Y) > tolerance {
+			return false
+		}
+	}
+	return true
+}
+
+func TestPathfinderInHole(t *testing.T) {
+	tests := []struct {
+		name      string
+		pt        pathfind.Point
+		holeIndex int
+		want      bool
+	}{
+		{"inside hole", pathfind.Pt(20, 20), 1, true},
+		{"on hole outline", pathfind.Pt(20, 10), 1, false},
+		{"outside hole", pathfind.Pt(5, 5), 1, false},
+		{"area polygon is not a hole", pathfind.Pt(20, 20), 0, false},
+		{"negative index", pathfind.Pt(20, 20), -1, false},
+		{"index out of range", pathfind.Pt(20, 20), 2, false},
+	}
+	pathfinder := pathfind.NewPathfinder(polygonO)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pathfinder.InHole(tt.pt, tt.holeIndex); got != tt.want {
+				t.Errorf("InHole(%v, %d) = %v, want %v", tt.pt, tt.holeIndex, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathfinderContains(t *testing.T) {
+	tests := []struct {
+		name string
+		pt   pathfind.Point
+		want bool
+	}{
+		{"inside area", pathfind.Pt(10, 20), true},
+		{"inside hole", pathfind.Pt(10, 10), false},
+		{"on hole outline", pathfind.Pt(5, 10), true},
+		{"on area outline", pathfind.Pt(0, 20), true},
+		{"outside all polygons", pathfind.Pt(35, 20), false},
+		{"inside hole of second area", pathfind.Pt(47, 20), false},
+		{"on island in hole", pathfind.Pt(60, 20), true},
+	}
+	pathfinder := pathfind.NewPathfinder(polygonIslands)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pathfinder.Contains(tt.pt); got != tt.want {
+				t.Errorf("Contains(%v) = %v, want %v", tt.pt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathfinderContainmentLevel(t *testing.T) {
+	tests := []struct {
+		name string
+		pt   pathfind.Point
+		want int
+	}{
+		{"outside all polygons", pathfind.Pt(35, 20), 0},
+		{"inside area", pathfind.Pt(10, 20), 1},
+		{"on area outline", pathfind.Pt(0, 20), 1},
+		{"inside hole", pathfind.Pt(10, 10), 2},
+		{"on hole outline", pathfind.Pt(5, 10), 1},
+		{"on island in hole", pathfind.Pt(60, 20), 3},
+		{"on island outline", pathfind.Pt(50, 20), 3},
+	}
+	pathfinder := pathfind.NewPathfinder(polygonIslands)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pathfinder.ContainmentLevel(tt.pt); got != tt.want {
+				t.Errorf("ContainmentLevel(%v) = %d, want %d", tt.pt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathfinderPathVertexIndices(t *testing.T) {
+	pathfinder := pathfind.NewPathfinder(polygonO)
+	path := pathfinder.Path(pathfind.Pt(15, 10), pathfind.Pt(30, 30))
+	got := pathfinder.PathVertexIndices(path)
+	want := [][2]int{{-1, -1}, {1, 0}, {1, 1}, {-1, -1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PathVertexIndices(%v) = %v, want %v", path, got, want)
+	}
+}
+
+func TestPathfinderCanSee(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to pathfind.Point
+		maxRange float64
+		want     bool
+	}{
+		{"visible within range", pathfind.Pt(5, 5), pathfind.Pt(5, 15), 10, true},
+		{"visible out of range", pathfind.Pt(5, 5), pathfind.Pt(5, 15), 9.9, false},
+		{"blocked within range", pathfind.Pt(5, 5), pathfind.Pt(25, 5), 100, false},
+		{"around the corner", pathfind.Pt(5, 15), pathfind.Pt(25, 15), 20, true},
+	}
+	pathfinder := pathfind.NewPathfinder(polygonU)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pathfinder.CanSee(tt.from, tt.to, tt.maxRange); got != tt.want {
+				t.Errorf("CanSee(%v, %v, %g) = %v, want %v", tt.from, tt.to, tt.maxRange, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathfinderMaybeBlocked(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b pathfind.Point
+		want bool
+	}{
+		{"clear above hole", pathfind.Pt(5, 5), pathfind.Pt(35, 5), false},
+		{"blocked by hole", pathfind.Pt(5, 15), pathfind.Pt(35, 15), true},
+		{"clear but near hole", pathfind.Pt(5, 5), pathfind.Pt(15, 15), true},
+		{"touching outer boundary", pathfind.Pt(5, 5), pathfind.Pt(5, 40), true},
+	}
+	pathfinder := pathfind.NewPathfinder(polygonO)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pathfinder.MaybeBlocked(tt.a, tt.b); got != tt.want {
+				t.Errorf("MaybeBlocked(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathfinderTwoVertexWall(t *testing.T) {
+	// A square with a vertical wall in the middle.
+	//
+	//	+--------+
+	//	|   |    |
+	//	| s | d  |
+	//	|   |    |
+	//	+--------+
+	polygons := [][]pathfind.Point{
+		polygonO[0],
+		{pathfind.Pt(20, 5), pathfind.Pt(20, 35)},
+		{pathfind.Pt(1, 1)},
+		{},
+	}
+	pathfinder := pathfind.NewPathfinder(polygons)
+	start, dest := pathfind.Pt(10, 15), pathfind.Pt(30, 15)
+	got := pathfinder.Path(start, dest)
+	want := []pathfind.Point{start, pathfind.Pt(20, 5), dest}
+	if !pathNearEq(got, want) {
+		t.Fatalf("Path(%v, %v)\n got: %v\nwant: %v", start, dest, got, want)
+	}
+	if got[1].Y >= 5 {
+		t.Errorf("path waypoint %v does not pass beyond the tip of the wall", got[1])
+	}
+	if pathfinder.CanSee(start, dest, 100) {
+		t.Errorf("CanSee(%v, %v) through wall = true, want false", start, dest)
+	}
+}
+
+func TestPathfinderWithPruneUnreachable(t *testing.T) {
+	// A hole sticking out of the right edge of the area polygon, and
+	// a hole containing an island with a hole of its own.
+	polygons := [][]pathfind.Point{
+		polygonO[0],
+		{pathfind.Pt(30, 10), pathfind.Pt(50, 10), pathfind.Pt(50, 30), pathfind.Pt(30, 30)},
+		{pathfind.Pt(10, 10), pathfind.Pt(20, 10), pathfind.Pt(20, 20), pathfind.Pt(10, 20)},
+		{pathfind.Pt(11, 11), pathfind.Pt(19, 11), pathfind.Pt(19, 19), pathfind.Pt(11, 19)},
+		{pathfind.Pt(14, 14), pathfind.Pt(16, 14), pathfind.Pt(16, 16), pathfind.Pt(14, 16)},
+	}
+	pruned := pathfind.NewPathfinder(polygons, pathfind.WithPruneUnreachable())
+	reference := pathfind.NewPathfinder(polygons)
+	points := []pathfind.Point{
+		pathfind.Pt(5, 5), pathfind.Pt(35, 5), pathfind.Pt(35, 35),
+		pathfind.Pt(20, 20), pathfind.Pt(5, 35), pathfind.Pt(45, 20),
+	}
+	for _, start := range points {
+		for _, dest := range points {
+			got := pruned.Path(start, dest)
+			want := reference.Path(start, dest)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Path(%v, %v)\n got: %v\nwant: %v", start, dest, got, want)
+			}
+		}
+	}
+}
+
+func TestNewPathfinderChecked(t *testing.T) {
+	closedU := append(append([]pathfind.Point(nil), polygonU[0]...), polygonU[0][0])
+	tests := []struct {
+		name     string
+		polygons [][]pathfind.Point
+		opts     []pathfind.Option
+		wantErr  bool
+	}{
+		{
+			name:     "closed ring",
+			polygons: [][]pathfind.Point{closedU},
+		},
+		{
+			name:     "open ring without auto-close",
+			polygons: polygonU,
+			wantErr:  true,
+		},
+		{
+			name:     "open ring with auto-close",
+			polygons: polygonU,
+			opts:     []pathfind.Option{pathfind.WithAutoClose()},
+		},
+		{
+			name:     "two-vertex wall",
+			polygons: [][]pathfind.Point{closedU, {pathfind.Pt(5, 15), pathfind.Pt(6, 15), pathfind.Pt(5, 15)}},
+		},
+		{
+			name:     "single vertex",
+			polygons: [][]pathfind.Point{closedU, {pathfind.Pt(5, 15), pathfind.Pt(5, 15)}},
+			wantErr:  true,
+		},
+		{
+			name:     "repeated single vertex",
+			polygons: [][]pathfind.Point{closedU, {pathfind.Pt(5, 15), pathfind.Pt(5, 15), pathfind.Pt(5, 15)}},
+			wantErr:  true,
+		},
+		{
+			name:     "empty polygon",
+			polygons: [][]pathfind.Point{closedU, {}},
+			opts:     []pathfind.Option{pathfind.WithAutoClose()},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pathfinder, err := pathfind.NewPathfinderChecked(tt.polygons, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewPathfinderChecked returned error %v, want error: %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			start, dest := pathfind.Pt(5, 5), pathfind.Pt(25, 5)
+			got := pathfinder.Path(start, dest)
+			want := pathfind.NewPathfinder(polygonU).Path(start, dest)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Path(%v, %v)\n got: %v\nwant: %v", start, dest, got, want)
+			}
+		})
+	}
+}
+
+func TestPathfinderDuplicateVertices(t *testing.T) {
+	u := polygonU[0]
+	tests := []struct {
+		name    string
+		polygon []pathfind.Point
+	}{
+		{
+			name:    "repeated concave vertex",
+			polygon: []pathfind.Point{u[0], u[1], u[2], u[2], u[3], u[4], u[5], u[6], u[7]},
+		},
+		{
+			name:    "repeated vertex of closed ring",
+			polygon: []pathfind.Point{u[0], u[1], u[2], u[3], u[3], u[4], u[5], u[6], u[7], u[0]},
+		},
+		{
+			name:    "repeated vertices at start and end",
+			polygon: []pathfind.Point{u[0], u[0], u[1], u[2], u[3], u[4], u[5], u[6], u[7], u[7]},
+		},
+		{
+			name:    "nearly repeated vertex",
+			polygon: []pathfind.Point{u[0], u[1], u[2], u[2].Add(pathfind.Pt(1e-12, 0)), u[3], u[4], u[5], u[6], u[7]},
+		},
+	}
+	start, dest := pathfind.Pt(5, 5), pathfind.Pt(25, 5)
+	want := pathfind.NewPathfinder(polygonU).Path(start, dest)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pathfinder := pathfind.NewPathfinder([][]pathfind.Point{tt.polygon})
+			got := pathfinder.Path(start, dest)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Path(%v, %v)\n got: %v\nwant: %v", start, dest, got, want)
+			}
+		})
+	}
+}
+
+func TestPathfinderWithSpatialHash(t *testing.T) {
+	for _, cellSize := range []float64{0, 3, 100} {
+		pathfinder := pathfind.NewPathfinder(polygonO, pathfind.WithSpatialHash(cellSize))
+		reference := pathfind.NewPathfinder(polygonO)
+		start, dest := pathfind.Pt(15, 10), pathfind.Pt(30, 30)
+		got := pathfinder.Path(start, dest)
+		want := reference.Path(start, dest)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("cell size %g: Path(%v, %v)\n got: %v\nwant: %v",
+				cellSize, start, dest, got, want)
+		}
+	}
+}
+
+func TestPathfinderOnClamp(t *testing.T) {
+	var calls int
+	var requested, clamped pathfind.Point
+	pathfinder := pathfind.NewPathfinder(polygonU, pathfind.OnClamp(func(r, c pathfind.Point) {
+		calls++
+		requested, clamped = r, c
+	}))
+
+	pathfinder.Path(pathfind.Pt(5, 5), pathfind.Pt(5, 15))
+	if calls != 0 {
+		t.Fatalf("OnClamp called %d times for destination inside, want 0", calls)
+	}
+
+	dest := pathfind.Pt(5, -10)
+	path := pathfinder.Path(pathfind.Pt(5, 15), dest)
+	if calls != 1 {
+		t.Fatalf("OnClamp called %d times for destination outside, want 1", calls)
+	}
+	if requested != dest || clamped != path[len(path)-1] {
+		t.Errorf("OnClamp(%v, %v), want OnClamp(%v, %v)", requested, clamped, dest, path[len(path)-1])
+	}
+}
+
+func TestPathfinderClampTowardStart(t *testing.T) {
+	tests := []struct {
+		name        string
+		start, dest pathfind.Point
+		want        pathfind.Point
+	}{
+		{
+			name:  "leaves through the side",
+			start: pathfind.Pt(5, 15),
+			dest:  pathfind.Pt(45, 10),
+			want:  pathfind.Pt(30, 11.875),
+		},
+		{
+			name:  "last of several exits",
+			start: pathfind.Pt(5, 14),
+			dest:  pathfind.Pt(45, -6),
+			want:  pathfind.Pt(30, 1.5),
+		},
+		{
+			name:  "leaves through the gap",
+			start: pathfind.Pt(5, 5),
+			dest:  pathfind.Pt(35, -10),
+			want:  pathfind.Pt(10, 2.5),
+		},
+		{
+			name:  "start outside",
+			start: pathfind.Pt(15, 5),
+			dest:  pathfind.Pt(12, -5),
+			want:  pathfind.Pt(10, 0),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got pathfind.Point
+			pathfinder := pathfind.NewPathfinder(polygonU, pathfind.ClampTowardStart(),
+				pathfind.OnClamp(func(_, clamped pathfind.Point) {
+					got = clamped
+				}))
+			pathfinder.Path(tt.start, tt.dest)
+			if !pathNearEq([]pathfind.Point{got}, []pathfind.Point{tt.want}) {
+				t.Errorf("Path(%v, %v) clamped destination to %v, want %v", tt.start, tt.dest, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathfinderWithMargin(t *testing.T) {
+	start, dest := pathfind.Pt(5, 5), pathfind.Pt(25, 5)
+	corner := pathfind.Pt(10, 10)
+	tests := []struct {
+		name string
+		opts []pathfind.Option
+		want float64
+	}{
+		{name: "default", opts: nil, want: 0.002},
+		{name: "larger margin", opts: []pathfind.Option{pathfind.WithMargin(0.5)}, want: 0.5},
+		{name: "non-positive margin ignored", opts: []pathfind.Option{pathfind.WithMargin(0)}, want: 0.002},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pathfinder := pathfind.NewPathfinder(polygonU, tt.opts...)
+			path := pathfinder.Path(start, dest)
+			if len(path) != 4 {
+				t.Fatalf("Path(%v, %v) = %v, want 4 points", start, dest, path)
+			}
+			if d := path[1].Dist(corner); math.Abs(d-tt.want) > 1e-6 {
+				t.Errorf("Path(%v, %v) turns at %v, distance %g from corner %v, want %g",
+					start, dest, path[1], d, corner, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathfinderPathNonFinite(t *testing.T) {
+	tests := []struct {
+		name        string
+		start, dest pathfind.Point
+	}{
+		{name: "NaN start", start: pathfind.Pt(math.NaN(), 0), dest: pathfind.Pt(5, 15)},
+		{name: "NaN dest", start: pathfind.Pt(5, 5), dest: pathfind.Pt(5, math.NaN())},
+		{name: "infinite start", start: pathfind.Pt(math.Inf(1), 5), dest: pathfind.Pt(5, 15)},
+		{name: "infinite dest", start: pathfind.Pt(5, 5), dest: pathfind.Pt(25, math.Inf(-1))},
+	}
+	calls := 0
+	pathfinder := pathfind.NewPathfinder(polygonU, pathfind.OnClamp(func(_, _ pathfind.Point) {
+		calls++
+	}))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, dest := tt.start, tt.dest
+			widest, _ := pathfinder.WidestPath(start, dest)
+			withCost, _ := pathfinder.PathWithCostValue(start, dest, pathfind.ManhattanDist, nil)
+			paths := []struct {
+				name string
+				got  []pathfind.Point
+			}{
+				{"Path", pathfinder.Path(start, dest)},
+				{"PathAvoiding", pathfinder.PathAvoiding(start, dest, nil)},
+				{"PathWithAgents", pathfinder.PathWithAgents(start, dest, nil, 1)},
+				{"PathMonotone", pathfinder.PathMonotone(start, dest, 0)},
+				{"PathWithDoors", pathfinder.PathWithDoors(start, dest, nil)},
+				{"PathWithin", pathfinder.PathWithin(start, dest, polygonU[0])},
+				{"PathFiltered", pathfinder.PathFiltered(start, dest, func(string) bool { return true })},
+				{"PathVia", pathfinder.PathVia(start, dest, pathfind.Pt(2, 12), pathfind.Pt(8, 12))},
+				{"PathBetweenEdges", pathfinder.PathBetweenEdges(start, start, dest, dest)},
+				{"OptimalTour", pathfinder.OptimalTour(start, []pathfind.Point{dest})},
+				{"WidestPath", widest},
+				{"PathWithCostValue", withCost},
+			}
+			for _, p := range paths {
+				if p.got != nil {
+					t.Errorf("%s(%v, %v) = %v, want nil", p.name, start, dest, p.got)
+				}
+			}
+			if got := pathfinder.KPaths(start, dest, 2); got != nil {
+				t.Errorf("KPaths(%v, %v, 2) = %v, want nil", start, dest, got)
+			}
+			if pathfinder.IsReachable(start, dest) {
+				t.Errorf("IsReachable(%v, %v) = true, want false", start, dest)
+			}
+			if pathfinder.Connected(start, dest) {
+				t.Errorf("Connected(%v, %v) = true, want false", start, dest)
+			}
+			if pathfinder.CanSee(start, dest, math.Inf(1)) {
+				t.Errorf("CanSee(%v, %v) = true, want false", start, dest)
+			}
+			if !pathfinder.MaybeBlocked(start, dest) {
+				t.Errorf("MaybeBlocked(%v, %v) = false, want true", start, dest)
+			}
+			if _, blocked := pathfinder.Raycast(start, dest); !blocked {
+				t.Errorf("Raycast(%v, %v) not blocked, want blocked", start, dest)
+			}
+		})
+	}
+	if calls != 0 {
+		t.Errorf("OnClamp called %d times for non-finite points, want 0", calls)
+	}
+}
+
+func TestPathfinderNonFinitePoint(t *testing.T) {
+	pathfinder := pathfind.NewPathfinder(polygonU)
+	exits := [][2]pathfind.Point{{pathfind.Pt(0, 0), pathfind.Pt(0, 20)}}
+	for _, pt := range []pathfind.Point{
+		pathfind.Pt(math.NaN(), 0),
+		pathfind.Pt(5, math.NaN()),
+		pathfind.Pt(math.Inf(1), 5),
+		pathfind.Pt(5, math.Inf(-1)),
+	} {
+		if pathfinder.Contains(pt) {
+			t.Errorf("Contains(%v) = true, want false", pt)
+		}
+		if got := pathfinder.ContainmentLevel(pt); got != 0 {
+			t.Errorf("ContainmentLevel(%v) = %d, want 0", pt, got)
+		}
+		if got, inside := pathfinder.NearestWalkable(pt); got != (pathfind.Point{}) || inside {
+			t.Errorf("NearestWalkable(%v) = %v, %v, want %v, false", pt, got, inside, pathfind.Point{})
+		}
+		if got := pathfinder.ComponentOf(pt); got != -1 {
+			t.Errorf("ComponentOf(%v) = %d, want -1", pt, got)
+		}
+		if got := pathfinder.ReachableRegion(pt); got != nil {
+			t.Errorf("ReachableRegion(%v) = %v, want nil", pt, got)
+		}
+		if got := pathfinder.ReachableFraction(pt); got != 0 {
+			t.Errorf("ReachableFraction(%v) = %g, want 0", pt, got)
+		}
+		if got := pathfinder.VisibilityPolygon(pt); got != nil {
+			t.Errorf("VisibilityPolygon(%v) = %v, want nil", pt, got)
+		}
+		if got := pathfinder.DistanceFromAny([]pathfind.Point{pathfind.Pt(5, 5)}, pt); !math.IsInf(got, 1) {
+			t.Errorf("DistanceFromAny to %v = %g, want +Inf", pt, got)
+		}
+		if got := pathfinder.DistanceFromAny([]pathfind.Point{pt}, pathfind.Pt(5, 5)); !math.IsInf(got, 1) {
+			t.Errorf("DistanceFromAny from %v = %g, want +Inf", pt, got)
+		}
+		if got, i := pathfinder.DistanceToExit(pt, exits); !math.IsInf(got, 1) || i != -1 {
+			t.Errorf("DistanceToExit(%v) = %g, %d, want +Inf, -1", pt, got, i)
+		}
+		if _, i, d := pathfind.NearestPointOnPath([]pathfind.Point{pathfind.Pt(5, 5), pathfind.Pt(5, 15)}, pt); i != -1 || !math.IsInf(d, 1) {
+			t.Errorf("NearestPointOnPath(%v) = index %d, distance %g, want -1, +Inf", pt, i, d)
+		}
+	}
+}
+
+func TestPathfinderWithStrictBounds(t *testing.T) {
+	strict := pathfind.NewPathfinder(polygonU, pathfind.WithStrictBounds())
+	start := pathfind.Pt(5, 15)
+	if got := strict.Path(start, pathfind.Pt(5, -10)); got != nil {
+		t.Errorf("Path to destination outside = %v, want nil", got)
+	}
+	got := strict.Path(start, pathfind.Pt(5, 5))
+	want := []pathfind.Point{start, pathfind.Pt(5, 5)}
+	if !pathNearEq(got, want) {
+		t.Errorf("Path to destination inside\n got: %v\nwant: %v", got, want)
+	}
+}
+
+func TestPathfinderQueriesLikePath(t *testing.T) {
+	// The variants of Path that are documented to work like Path, called
+	// with arguments that do not restrict the path.
+	queries := []struct {
+		name string
+		path func(p *pathfind.Pathfinder, start, dest pathfind.Point) []pathfind.Point
+	}{
+		{
+			name: "PathAvoiding",
+			path: func(p *pathfind.Pathfinder, start, dest pathfind.Point) []pathfind.Point {
+				return p.PathAvoiding(start, dest, nil)
+			},
+		},
+		{
+			name: "PathWithAgents",
+			path: func(p *pathfind.Pathfinder, start, dest pathfind.Point) []pathfind.Point {
+				return p.PathWithAgents(start, dest, nil, 1)
+			},
+		},
+		{
+			name: "PathMonotone",
+			path: func(p *pathfind.Pathfinder, start, dest pathfind.Point) []pathfind.Point {
+				return p.PathMonotone(start, dest, math.Inf(1))
+			},
+		},
+		{
+			name: "PathWithCostValue",
+			path: func(p *pathfind.Pathfinder, start, dest pathfind.Point) []pathfind.Point {
+				path, _ := p.PathWithCostValue(start, dest, pathfind.Point.Dist, pathfind.Point.Dist)
+				return path
+			},
+		},
+		{
+			name: "PathWithDoors",
+			path: func(p *pathfind.Pathfinder, start, dest pathfind.Point) []pathfind.Point {
+				return p.PathWithDoors(start, dest, nil)
+			},
+		},
+		{
+			name: "PathWithin",
+			path: func(p *pathfind.Pathfinder, start, dest pathfind.Point) []pathfind.Point {
+				min, max := p.Bounds()
+				return p.PathWithin(start, dest, []pathfind.Point{
+					min.Sub(pathfind.Pt(1, 1)), pathfind.Pt(max.X+1, min.Y-1),
+					max.Add(pathfind.Pt(1, 1)), pathfind.Pt(min.X-1, max.Y+1),
+				})
+			},
+		},
+	}
+	// The tops of the two notches lie on a straight line, so the path
+	// passes four collinear corners.
+	notches := [][]pathfind.Point{{
+		pathfind.Pt(0, 0), pathfind.Pt(10, 0), pathfind.Pt(10, 10), pathfind.Pt(15, 10),
+		pathfind.Pt(15, 0), pathfind.Pt(25, 0), pathfind.Pt(25, 10), pathfind.Pt(30, 10),
+		pathfind.Pt(30, 0), pathfind.Pt(40, 0), pathfind.Pt(40, 20), pathfind.Pt(0, 20),
+	}}
+	tests := []struct {
+		name       string
+		pathfinder *pathfind.Pathfinder
+		start      pathfind.Point
+		dest       pathfind.Point
+	}{
+		{
+			name:       "detour",
+			pathfinder: pathfind.NewPathfinder(polygonO),
+			start:      pathfind.Pt(5, 20),
+			dest:       pathfind.Pt(35, 20),
+		},
+		{
+			name:       "clamped destination",
+			pathfinder: pathfind.NewPathfinder(polygonO),
+			start:      pathfind.Pt(5, 20),
+			dest:       pathfind.Pt(50, 20),
+		},
+		{
+			name:       "strict bounds",
+			pathfinder: pathfind.NewPathfinder(polygonO, pathfind.WithStrictBounds()),
+			start:      pathfind.Pt(5, 20),
+			dest:       pathfind.Pt(50, 20),
+		},
+		{
+			name:       "clearance",
+			pathfinder: pathfind.NewPathfinder(polygonO, pathfind.WithClearance(2)),
+			start:      pathfind.Pt(5, 9),
+			dest:       pathfind.Pt(35, 9),
+		},
+		{
+			name:       "simplification",
+			pathfinder: pathfind.NewPathfinder(notches, pathfind.WithSimplification(0.01)),
+			start:      pathfind.Pt(5, 5),
+			dest:       pathfind.Pt(35, 5),
+		},
+	}
+	for _, tt := range tests {
+		want := tt.pathfinder.Path(tt.start, tt.dest)
+		for _, q := range queries {
+			t.Run(tt.name+"/"+q.name, func(t *testing.T) {
+				if got := q.path(tt.pathfinder, tt.start, tt.dest); !pathNearEq(got, want) {
+					t.Errorf("%s(%v, %v)\n got: %v\nwant: %v", q.name, tt.start, tt.dest, got, want)
+				}
+			})
+		}
+	}
+
+	var calls int
+	pathfinder := pathfind.NewPathfinder(polygonO, pathfind.OnClamp(func(_, _ pathfind.Point) {
+		calls++
+	}))
+	for _, q := range queries {
+		calls = 0
+		q.path(pathfinder, pathfind.Pt(5, 20), pathfind.Pt(50, 20))
+		if calls != 1 {
+			t.Errorf("%s: OnClamp called %d times, want 1", q.name, calls)
+		}
+	}
+}
+
+func TestPathfinderWithConcaveAngleThreshold(t *testing.T) {
+	// polygonU with a barely concave dent in the bottom edge.
+	dent := pathfind.Pt(15, 19.75)
+	polygons := [][]pathfind.Point{
+		{
+			pathfind.Pt(0, 0),
+			pathfind.Pt(10, 0),
+			pathfind.Pt(10, 10),
+			pathfind.Pt(20, 10),
+			pathfind.Pt(20, 0),
+			pathfind.Pt(30, 0),
+			pathfind.Pt(30, 20),
+			dent,
+			pathfind.Pt(0, 20),
+		},
+	}
+	start, dest := pathfind.Pt(5, 5), pathfind.Pt(25, 5)
+
+	noisy := pathfind.NewPathfinder(polygons)
+	if len(noisy.Graph().Neighbors(dent)) == 0 {
+		t.Fatalf("dent %v is not a node of the visibility graph without threshold", dent)
+	}
+	pathfinder := pathfind.NewPathfinder(polygons, pathfind.WithConcaveAngleThreshold(0.05))
+	if got := pathfinder.Graph().Neighbors(dent); len(got) != 0 {
+		t.Errorf("Neighbors(%v) = %v with threshold, want none", dent, got)
+	}
+	for _, corner := range []pathfind.Point{pathfind.Pt(10, 10), pathfind.Pt(20, 10)} {
+		if len(pathfinder.Graph().Neighbors(corner)) == 0 {
+			t.Errorf("corner %v is not a node of the visibility graph with threshold", corner)
+		}
+	}
+	if got, want := pathfinder.Path(start, dest), noisy.Path(start, dest); !reflect.DeepEqual(got, want) {
+		t.Errorf("Path(%v, %v) with threshold\n got: %v\nwant: %v", start, dest, got, want)
+	}
+}
+
+func TestPathfinderWithInvertedNesting(t *testing.T) {
+	// Only the diamond of polygonO, which is a hole with inverted nesting.
+	obstacles := [][]pathfind.Point{polygonO[1]}
+	pathfinder := pathfind.NewPathfinder(obstacles, pathfind.WithInvertedNesting())
+	start, dest := pathfind.Pt(15, 10), pathfind.Pt(30, 30)
+	got := pathfinder.Path(start, dest)
+	want := pathfind.NewPathfinder(polygonO).Path(start, dest)
+	if !pathNearEq(got, want) {
+		t.Errorf("Path(%v, %v)\n got: %v\nwant: %v", start, dest, got, want)
+	}
+	if !pathfinder.InHole(pathfind.Pt(20, 20), 0) {
+		t.Errorf("InHole(%v, 0) = false, want true", pathfind.Pt(20, 20))
+	}
+	if got := pathfinder.Path(pathfind.Pt(20, 20), dest); got != nil {
+		t.Errorf("Path from inside obstacle = %v, want nil", got)
+	}
+}
+
+func TestPathfinderMixedWinding(t *testing.T) {
+	reversed := func(ps []pathfind.Point) []pathfind.Point {
+		r := slices.Clone(ps)
+		slices.Reverse(r)
+		return r
+	}
+	tests := []struct {
+		name        string
+		polygons    [][]pathfind.Point
+		original    [][]pathfind.Point
+		start, dest pathfind.Point
+	}{
+		{
+			name:     "reversed area",
+			polygons: [][]pathfind.Point{reversed(polygonO[0]), polygonO[1]},
+			original: polygonO,
+			start:    pathfind.Pt(5, 35),
+			dest:     pathfind.Pt(35, 5),
+		},
+		{
+			name:     "reversed hole",
+			polygons: [][]pathfind.Point{polygonO[0], reversed(polygonO[1])},
+			original: polygonO,
+			start:    pathfind.Pt(5, 35),
+			dest:     pathfind.Pt(35, 5),
+		},
+		{
+			name:     "both reversed",
+			polygons: [][]pathfind.Point{reversed(polygonO[0]), reversed(polygonO[1])},
+			original: polygonO,
+			start:    pathfind.Pt(5, 35),
+			dest:     pathfind.Pt(35, 5),
+		},
+		{
+			name:     "reversed concave area",
+			polygons: [][]pathfind.Point{reversed(polygonU[0])},
+			original: polygonU,
+			start:    pathfind.Pt(5, 5),
+			dest:     pathfind.Pt(25, 5),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pathfind.NewPathfinder(tt.polygons).Path(tt.start, tt.dest)
+			want := pathfind.NewPathfinder(tt.original).Path(tt.start, tt.dest)
+			if len(want) < 3 || !pathNearEq(got, want) {
+				t.Errorf("Path(%v, %v)\n got: %v\nwant: %v", tt.start, tt.dest, got, want)
+			}
+		})
+	}
+}
+
+func TestPathfinderPathReversed(t *testing.T) {
+	pathfinder := pathfind.NewPathfinder(polygonU)
+	got := pathfinder.PathReversed(pathfind.Pt(5, 5), pathfind.Pt(25, 5))
+	want := []pathfind.Point{
+		pathfind.Pt(25, 5),
+		pathfind.Pt(20, 10),
+		pathfind.Pt(10, 10),
+		pathfind.Pt(5, 5),
+	}
+	if !pathNearEq(got, want) {
+		t.Errorf("PathReversed\n got: %v\nwant: %v", got, want)
+	}
+}
+
+func TestPathfinderPathIter(t *testing.T) {
+	pathfinder := pathfind.NewPathfinder(polygonU)
+	start, dest := pathfind.Pt(5, 5), pathfind.Pt(25, 5)
+	seq := pathfinder.PathIter(start, dest)
+	if got, want := slices.Collect(seq), pathfinder.Path(start, dest); !reflect.DeepEqual(got, want) {
+		t.Errorf("PathIter(%v, %v)\n got: %v\nwant: %v", start, dest, got, want)
+	}
+
+	var first []pathfind.Point
+	for pt := range seq {
+		first = append(first, pt)
+		if len(first) == 2 {
+			break
+		}
+	}
+	if want := []pathfind.Point{start, pathfind.Pt(10, 10)}; !pathNearEq(first, want) {
+		t.Errorf("PathIter(%v, %v) stopped after two waypoints = %v, want %v", start, dest, first, want)
+	}
+
+	noPath := pathfind.Pt(15, 5)
+	for pt := range pathfinder.PathIter(noPath, dest) {
+		t.Errorf("PathIter(%v, %v) yielded %v, want nothing", noPath, dest, pt)
+	}
+}
+
+func TestPathfinderPathSymmetric(t *testing.T) {
+	points := []pathfind.Point{
+		pathfind.Pt(5, 5),
+		pathfind.Pt(25, 5),
+		pathfind.Pt(25, 15),
+		pathfind.Pt(5, 15),
+		pathfind.Pt(15, 18),
+		pathfind.Pt(1, 1),
+	}
+	pathfinder := pathfind.NewPathfinder(polygonU)
+	for _, a := range points {
+		for _, b := range points {
+			if a == b {
+				continue
+			}
+			forward := pathfinder.PathReversed(a, b)
+			backward := pathfinder.Path(b, a)
+			if !reflect.DeepEqual(forward, backward) {
+				t.Errorf("PathReversed(%v, %v) = %v differs from Path(%v, %v) = %v",
+					a, b, forward, b, a, backward)
+			}
+		}
+	}
+}
+
+func TestPathfinderPathContext(t *testing.T) {
+	pathfinder := pathfind.NewPathfinder(polygonU)
+	start, dest := pathfind.Pt(5, 5), pathfind.Pt(25, 5)
+
+	got, err := pathfinder.PathContext(context.Background(), start, dest)
+	if want := pathfinder.Path(start, dest); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("PathContext(%v, %v) = %v, %v, want %v, <nil>", start, dest, got, err, want)
+	}
+	before := pathfinder.VisibilityGraph()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	got, err = pathfinder.PathContext(ctx, start, pathfind.Pt(25, 15))
+	if got != nil || !errors.Is(err, context.Canceled) {
+		t.Errorf("PathContext with cancelled context = %v, %v, want <nil>, %v", got, err, context.Canceled)
+	}
+	if after := pathfinder.VisibilityGraph(); !reflect.DeepEqual(after, before) {
+		t.Errorf("VisibilityGraph() changed by cancelled PathContext")
+	}
+}
+
+func TestPathfinderNearestWalkable(t *testing.T) {
+	tests := []struct {
+		name       string
+		pt         pathfind.Point
+		want       pathfind.Point
+		wantInside bool
+	}{
+		{name: "inside", pt: pathfind.Pt(5, 5), want: pathfind.Pt(5, 5), wantInside: true},
+		{name: "on outline", pt: pathfind.Pt(0, 5), want: pathfind.Pt(0, 5), wantInside: true},
+		{name: "in notch", pt: pathfind.Pt(14, 4), want: pathfind.Pt(10, 4), wantInside: false},
+		{name: "beyond corner", pt: pathfind.Pt(-3, -4), want: pathfind.Pt(0, 0), wantInside: false},
+	}
+	pathfinder := pathfind.NewPathfinder(polygonU)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, inside := pathfinder.NearestWalkable(tt.pt)
+			if !pathNearEq([]pathfind.Point{got}, []pathfind.Point{tt.want}) || inside != tt.wantInside {
+				t.Errorf("NearestWalkable(%v) = %v, %v, want %v, %v", tt.pt, got, inside, tt.want, tt.wantInside)
+			}
+		})
+	}
+}
+
+func TestPathfinderConcurrentPath(t *testing.T) {
+	pathfinder := pathfind.NewPathfinder(polygonTwoPassages)
+	queries := [][2]pathfind.Point{
+		{pathfind.Pt(20, 10), pathfind.Pt(80, 10)},
+		{pathfind.Pt(20, 55), pathfind.Pt(80, 55)},
+		{pathfind.Pt(50, 2), pathfind.Pt(50, 58)},
+		{pathfind.Pt(5, 30), pathfind.Pt(95, 30)},
+	}
+	want := make([][]pathfind.Point, len(queries))
+	for i, q := range queries {
+		want[i] = pathfinder.Path(q[0], q[1])
+	}
+	var wg sync.WaitGroup
+	for g := range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 50 {
+				q := queries[(g+i)%len(queries)]
+				if got := pathfinder.Path(q[0], q[1]); !reflect.DeepEqual(got, want[(g+i)%len(queries)]) {
+					t.Errorf("concurrent Path(%v, %v) = %v, want %v", q[0], q[1], got, want[(g+i)%len(queries)])
+				}
+				pathfinder.VisibilityGraph()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkPathfinderPath(b *testing.B) {
+	pathfinder := pathfind.NewPathfinder(polygonTwoPassages)
+	start, dest := pathfind.Pt(20, 10), pathfind.Pt(80, 10)
+	b.ReportAllocs()
+	for range b.N {
+		pathfinder.Path(start, dest)
+	}
+}
+
+func BenchmarkPathfinderPathLineOfSight(b *testing.B) {
+	// Path returns the direct connection of start and dest that are in line
+	// of sight of each other without preparing and searching the visibility
+	// graph. A query of about the same distance between the holes, which
+	// needs the search, is included for comparison.
+	pathfinder := pathfind.NewPathfinder(gridOfHoles(10))
+	for _, bm := range []struct {
+		name        string
+		start, dest pathfind.Point
+	}{
+		{name: "direct", start: pathfind.Pt(1, 1), dest: pathfind.Pt(99, 1)},
+		{name: "search", start: pathfind.Pt(1, 1), dest: pathfind.Pt(99, 5)},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				pathfinder.Path(bm.start, bm.dest)
+			}
+		})
+	}
+}
+
+func BenchmarkNewPathfinder(b *testing.B) {
+	polygons := gridOfHoles(10)
+	b.ReportAllocs()
+	for range b.N {
+		pathfind.NewPathfinder(polygons)
+	}
+}
+
+// gridOfHoles returns an area with a grid of n×n square holes.
+func gridOfHoles(n int) [][]pathfind.Point {
+	size := float64(10 * n)
+	polygons := [][]pathfind.Point{
+		{pathfind.Pt(0, 0), pathfind.Pt(size, 0), pathfind.Pt(size, size), pathfind.Pt(0, size)},
+	}
+	for i := range n {
+		for j := range n {
+			x, y := float64(10*i+3), float64(10*j+3)
+			polygons = append(polygons, []pathfind.Point{
+				pathfind.Pt(x, y), pathfind.Pt(x+4, y), pathfind.Pt(x+4, y+4), pathfind.Pt(x, y+4),
+			})
+		}
+	}
+	return polygons
+}
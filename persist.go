@@ -0,0 +1,192 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+)
+
+// persistVersion is the version of the format written by GobEncode.
+const persistVersion = 1
+
+// pathfinderData is the persisted state of a Pathfinder.
+type pathfinderData struct {
+	Version         int
+	Polygons        [][]Point
+	ConcaveOf       [][]Point
+	ConcaveVertices []Point
+	Parents         []int
+	Depths          []int
+	Graph           map[Point][]Point
+	Edited          bool
+	Components      map[Point]int
+	NumComponents   int
+	Options         optionsData
+}
+
+// optionsData holds the options of a Pathfinder that can be persisted.
+type optionsData struct {
+	AutoClose        bool
+	HashCell         float64
+	Tags             []string
+	PruneUnreachable bool
+	Doors            [][2]Point
+	StrictBounds     bool
+	InvertedNesting  bool
+	SmoothingIters   int
+	TensionSteps     int
+	ConcaveThreshold float64
+	RegionWeights    map[int]float64
+	Clearance        float64
+	Simplify         bool
+	SimplifyEps      float64
+	ClampToStart     bool
+	Margin           float64
+	Walls            []WallPolyline
+}
+
+// GobEncode encodes the Pathfinder including its visibility graph, e.g. to
+// precompute the Pathfinder of a static level and cache it on disk. Use
+// LoadPathfinder or GobDecode to restore it. The encoding contains the
+// options of the Pathfinder except for those given as functions, OnClamp
+// and WithMetric, as well as modifications of the Graph and the component
+// ids reported by ComponentOf.
+func (p *Pathfinder) GobEncode() ([]byte, error) {
+	components, numComponents := p.connectedComponents()
+	o := p.opts
+	data := pathfinderData{
+		Version:         persistVersion,
+		Polygons:        p.polygons,
+		ConcaveOf:       p.concaveOf,
+		ConcaveVertices: p.concaveVertices,
+		Parents:         p.parents,
+		Depths:          p.depths,
+		Graph:           p.cachedGraph,
+		Edited:          p.edited,
+		Components:      components,
+		NumComponents:   numComponents,
+		Options: optionsData{
+			AutoClose:        o.autoClose,
+			HashCell:         o.hashCell,
+			Tags:             o.tags,
+			PruneUnreachable: o.pruneUnreachable,
+			Doors:            o.doors,
+			StrictBounds:     o.strictBounds,
+			InvertedNesting:  o.invertedNesting,
+			SmoothingIters:   o.smoothingIters,
+			TensionSteps:     o.tensionSteps,
+			ConcaveThreshold: o.concaveThreshold,
+			RegionWeights:    o.regionWeights,
+			Clearance:        o.clearance,
+			Simplify:         o.simplify,
+			SimplifyEps:      o.simplifyEps,
+			ClampToStart:     o.clampToStart,
+			Margin:           o.margin,
+			Walls:            o.wallPolylines,
+		},
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(data); err != nil {
+		return nil, fmt.Errorf("could not encode Pathfinder: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// LoadPathfinder restores a Pathfinder encoded with GobEncode. Its
+// visibility graph is taken from data instead of being computed again, so
+// loading is much faster than creating the Pathfinder with NewPathfinder.
+// The loaded Pathfinder finds the same paths as the encoded one.
+//
+// The options given as functions cannot be encoded and can be passed again
+// as opts. Only OnClamp and WithMetric are taken from opts; all other
+// options are restored from data.
+func LoadPathfinder(data []byte, opts ...Option) (*Pathfinder, error) {
+	p := new(Pathfinder)
+	if err := p.GobDecode(data); err != nil {
+		return nil, err
+	}
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	p.opts.onClamp, p.opts.cost, p.opts.heuristic = o.onClamp, o.cost, o.heuristic
+	return p, nil
+}
+
+// GobDecode restores a Pathfinder encoded with GobEncode, like
+// LoadPathfinder, into p, which must not be in use. The options given as
+// functions, OnClamp and WithMetric, are not set.
+func (p *Pathfinder) GobDecode(data []byte) error {
+	var d pathfinderData
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&d); err != nil {
+		return fmt.Errorf("could not decode Pathfinder: %w", err)
+	}
+	if d.Version != persistVersion {
+		return fmt.Errorf("could not decode Pathfinder: unsupported version %d", d.Version)
+	}
+	n := len(d.Polygons)
+	if len(d.ConcaveOf) != n || len(d.Parents) != n || len(d.Depths) != n {
+		return errors.New("could not decode Pathfinder: inconsistent polygon data")
+	}
+	do := d.Options
+	o := options{
+		autoClose:        do.AutoClose,
+		hashCell:         do.HashCell,
+		tags:             do.Tags,
+		pruneUnreachable: do.PruneUnreachable,
+		doors:            do.Doors,
+		strictBounds:     do.StrictBounds,
+		invertedNesting:  do.InvertedNesting,
+		smoothingIters:   do.SmoothingIters,
+		tensionSteps:     do.TensionSteps,
+		concaveThreshold: do.ConcaveThreshold,
+		regionWeights:    do.RegionWeights,
+		clearance:        do.Clearance,
+		simplify:         do.Simplify,
+		simplifyEps:      do.SimplifyEps,
+		clampToStart:     do.ClampToStart,
+		margin:           do.Margin,
+		wallPolylines:    do.Walls,
+	}
+	if o.margin <= 0 {
+		// Encoded before the margin was configurable.
+		o.margin = defaultMargin
+	}
+	polygonSet := convert(d.Polygons, toPolygon)
+	regions, weights := takeRegions(polygonSet, o.regionWeights)
+	boxes := make([]rect, n)
+	for i, ps := range d.Polygons {
+		boxes[i] = boundingRect([][]Point{ps})
+	}
+	g := graph[Point](d.Graph)
+	if g == nil {
+		g = make(graph[Point])
+	}
+	*p = Pathfinder{
+		polygons:        d.Polygons,
+		polygonSet:      polygonSet,
+		regions:         regions,
+		weights:         weights,
+		wallRings:       wallRings(o.wallPolylines),
+		concaveOf:       d.ConcaveOf,
+		concaveVertices: d.ConcaveVertices,
+		parents:         d.Parents,
+		depths:          d.Depths,
+		boxes:           boxes,
+		cachedGraph:     g,
+		edited:          d.Edited,
+		opts:            o,
+		components:      d.Components,
+		numComponents:   d.NumComponents,
+	}
+	p.index = buildIndex(d.Polygons, p.graphNodes(), o)
+	if o.clearance > 0 {
+		p.walls = p.edges()
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"reflect"
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+func TestLoadPathfinder(t *testing.T) {
+	tests := []struct {
+		name     string
+		polygons [][]pathfind.Point
+		opts     []pathfind.Option
+	}{
+		{name: "area with hole", polygons: polygonO},
+		{name: "islands", polygons: polygonIslands},
+		{name: "clearance", polygons: polygonTwoPassages, opts: []pathfind.Option{pathfind.WithClearance(1)}},
+		{name: "spatial hash", polygons: polygonS, opts: []pathfind.Option{pathfind.WithSpatialHash(0)}},
+		{name: "walls", polygons: polygonSquare, opts: []pathfind.Option{pathfind.WithWalls(pathfind.WallPolyline{pathfind.Pt(20, 5), pathfind.Pt(20, 30), pathfind.Pt(35, 30)})}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fresh := pathfind.NewPathfinder(tt.polygons, tt.opts...)
+			data, err := fresh.GobEncode()
+			if err != nil {
+				t.Fatalf("GobEncode returned error: %v", err)
+			}
+			loaded, err := pathfind.LoadPathfinder(data)
+			if err != nil {
+				t.Fatalf("LoadPathfinder returned error: %v", err)
+			}
+			if got, want := loaded.StaticVisibilityGraph(), fresh.StaticVisibilityGraph(); !reflect.DeepEqual(got, want) {
+				t.Errorf("StaticVisibilityGraph of loaded Pathfinder\n got: %v\nwant: %v", got, want)
+			}
+			var points []pathfind.Point
+			for x := 1.0; x < 60; x += 7 {
+				for y := 1.0; y < 60; y += 7 {
+					points = append(points, pathfind.Pt(x, y))
+				}
+			}
+			for _, start := range points {
+				if got, want := loaded.ComponentOf(start), fresh.ComponentOf(start); got != want {
+					t.Errorf("ComponentOf(%v) = %d, want %d", start, got, want)
+				}
+				for _, dest := range points[:10] {
+					if got, want := loaded.Path(start, dest), fresh.Path(start, dest); !reflect.DeepEqual(got, want) {
+						t.Errorf("Path(%v, %v) of loaded Pathfinder\n got: %v\nwant: %v", start, dest, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestPathfinderGobRoundTrip(t *testing.T) {
+	fresh := pathfind.NewPathfinder(polygonU)
+	fresh.Graph().Link(pathfind.Pt(5, 5), pathfind.Pt(25, 5))
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(fresh); err != nil {
+		t.Fatalf("Encode returned error: %v", err)
+	}
+	var loaded pathfind.Pathfinder
+	if err := gob.NewDecoder(&buf).Decode(&loaded); err != nil {
+		t.Fatalf("Decode returned error: %v", err)
+	}
+	start, dest := pathfind.Pt(2, 2), pathfind.Pt(28, 2)
+	want := []pathfind.Point{start, pathfind.Pt(5, 5), pathfind.Pt(25, 5), dest}
+	if got := loaded.Path(start, dest); !pathNearEq(got, want) {
+		t.Errorf("Path(%v, %v) with persisted jump link\n got: %v\nwant: %v", start, dest, got, want)
+	}
+}
+
+func TestLoadPathfinderAfterRemovePolygon(t *testing.T) {
+	fresh := pathfind.NewWeightedPathfinder([]pathfind.WeightedPolygon{
+		{Points: polygonSquare[0]},
+		{Points: []pathfind.Point{pathfind.Pt(2, 2), pathfind.Pt(4, 2), pathfind.Pt(4, 4), pathfind.Pt(2, 4)}},
+		{Points: []pathfind.Point{pathfind.Pt(15, 5), pathfind.Pt(25, 5), pathfind.Pt(25, 35), pathfind.Pt(15, 35)}, Weight: 2},
+	})
+	fresh.RemovePolygon(1)
+	data, err := fresh.GobEncode()
+	if err != nil {
+		t.Fatalf("GobEncode returned error: %v", err)
+	}
+	loaded, err := pathfind.LoadPathfinder(data)
+	if err != nil {
+		t.Fatalf("LoadPathfinder returned error: %v", err)
+	}
+	// The path crosses the weighted region instead of going around it.
+	start, dest := pathfind.Pt(5, 20), pathfind.Pt(35, 20)
+	want := []pathfind.Point{start, dest}
+	if got := fresh.Path(start, dest); !reflect.DeepEqual(got, want) {
+		t.Errorf("Path(%v, %v) after RemovePolygon = %v, want %v", start, dest, got, want)
+	}
+	if got := loaded.Path(start, dest); !reflect.DeepEqual(got, want) {
+		t.Errorf("Path(%v, %v) of loaded Pathfinder = %v, want %v", start, dest, got, want)
+	}
+}
+
+func TestLoadPathfinderErrors(t *testing.T) {
+	data, err := pathfind.NewPathfinder(polygonU).GobEncode()
+	if err != nil {
+		t.Fatalf("GobEncode returned error: %v", err)
+	}
+	for name, data := range map[string][]byte{
+		"empty":     nil,
+		"truncated": data[:len(data)/2],
+		"garbage":   []byte("not a pathfinder"),
+	} {
+		if _, err := pathfind.LoadPathfinder(data); err == nil {
+			t.Errorf("LoadPathfinder of %s data returned no error", name)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import (
+	"math"
+	"sync"
+)
+
+// A POISet is a set of named points of interest on the map of a Pathfinder,
+// e.g. the locations of quest givers in a game. It precomputes the shortest
+// paths between all pairs of points, so that distance and route queries are
+// simple lookups.
+//
+// The paths are computed lazily on the first query after the set or the map
+// has changed, e.g. with AddPolygon, RemovePolygon or by editing the Graph
+// of the Pathfinder. Like DistanceFromAny, points are not clamped to the
+// polygon set. A POISet is safe for concurrent use.
+type POISet struct {
+	pf *Pathfinder
+
+	mu         sync.Mutex
+	names      []string
+	points     []Point
+	index      map[string]int
+	valid      bool
+	generation uint64
+	dist       [][]float64
+	prev       []map[Point]Point
+}
+
+// NewPOISet creates an empty set of points of interest on the map of
+// Pathfinder pf.
+func NewPOISet(pf *Pathfinder) *POISet {
+	return &POISet{pf: pf, index: make(map[string]int)}
+}
+
+// Add adds the point of interest pt with the given name to the set,
+// replacing the point previously registered under this name.
+func (s *POISet) Add(name string, pt Point) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i, ok := s.index[name]; ok {
+		s.points[i] = pt
+	} else {
+		s.index[name] = len(s.points)
+		s.names = append(s.names, name)
+		s.points = append(s.points, pt)
+	}
+	s.valid = false
+}
+
+// Remove removes the point of interest with the given name from the set.
+func (s *POISet) Remove(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, ok := s.index[name]
+	if !ok {
+		return
+	}
+	last := len(s.points) - 1
+	s.names[i], s.points[i] = s.names[last], s.points[last]
+	s.index[s.names[i]] = i
+	s.names, s.points = s.names[:last], s.points[:last]
+	delete(s.index, name)
+	s.valid = false
+}
+
+// Distance returns the length of the shortest path between the points of
+// interest named a and b. It returns +Inf if there is no such path or if
+// one of the names is not registered.
+func (s *POISet) Distance(a, b string) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, j, ok := s.lookup(a, b)
+	if !ok {
+		return math.Inf(1)
+	}
+	return s.dist[i][j]
+}
+
+// Route returns the shortest path from the point of interest named a to the
+// one named b. It returns nil if there is no such path or if one of the
+// names is not registered.
+func (s *POISet) Route(a, b string) []Point {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, j, ok := s.lookup(a, b)
+	if !ok || math.IsInf(s.dist[i][j], 1) {
+		return nil
+	}
+	if i == j {
+		return []Point{s.points[i]}
+	}
+	path := tracePath(s.prev[i], s.points[j])
+	offsetPath(s.pf.polygonSet, path, s.pf.opts.margin)
+	return path
+}
+
+// lookup returns the indices of the points of interest named a and b,
+// recomputing the paths between all points if necessary.
+func (s *POISet) lookup(a, b string) (i, j int, ok bool) {
+	i, okA := s.index[a]
+	j, okB := s.index[b]
+	if !okA || !okB {
+		return 0, 0, false
+	}
+	if !s.valid || s.generation != s.pf.generation {
+		s.compute()
+	}
+	return i, j, true
+}
+
+// compute runs Dijkstra's algorithm from each point of interest over the
+// visibility graph extended by all points of interest.
+func (s *POISet) compute() {
+	pf := s.pf
+	vis := copyGraph(pf.cachedGraph)
+	for i, pt := range s.points {
+		pf.linkIntoGraph(vis, pt, s.points[:i])
+	}
+	levels := make([]int, len(s.points))
+	for i, pt := range s.points {
+		levels[i] = containmentLevel(pf.polygonSet, pt)
+	}
+	cost, _ := pf.travelCost()
+	s.dist = make([][]float64, len(s.points))
+	s.prev = make([]map[Point]Point, len(s.points))
+	for i, src := range s.points {
+		dist, prev := dijkstra(vis, []Point{src}, cost, nil)
+		s.dist[i] = make([]float64, len(s.points))
+		for j, dst := range s.points {
+			d, ok := dist[dst]
+			if !ok || levels[i] != levels[j] {
+				d = math.Inf(1)
+			}
+			s.dist[i][j] = d
+		}
+		s.prev[i] = prev
+	}
+	s.valid = true
+	s.generation = pf.generation
+}
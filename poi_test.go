@@ -0,0 +1,79 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+func TestPOISet(t *testing.T) {
+	pathfinder := pathfind.NewPathfinder(polygonU)
+	pois := pathfind.NewPOISet(pathfinder)
+	pois.Add("left", pathfind.Pt(5, 5))
+	pois.Add("right", pathfind.Pt(25, 5))
+	pois.Add("top", pathfind.Pt(5, 15))
+	pois.Add("outside", pathfind.Pt(15, 5))
+
+	tests := []struct {
+		a, b      string
+		wantDist  float64
+		wantRoute []pathfind.Point
+	}{
+		{"left", "left", 0, []pathfind.Point{pathfind.Pt(5, 5)}},
+		{"left", "top", 10, []pathfind.Point{pathfind.Pt(5, 5), pathfind.Pt(5, 15)}},
+		{
+			"left", "right", 2*math.Sqrt(50) + 10,
+			[]pathfind.Point{pathfind.Pt(5, 5), pathfind.Pt(10, 10), pathfind.Pt(20, 10), pathfind.Pt(25, 5)},
+		},
+		{
+			"right", "left", 2*math.Sqrt(50) + 10,
+			[]pathfind.Point{pathfind.Pt(25, 5), pathfind.Pt(20, 10), pathfind.Pt(10, 10), pathfind.Pt(5, 5)},
+		},
+		{"left", "outside", math.Inf(1), nil},
+		{"left", "unknown", math.Inf(1), nil},
+	}
+	for _, tt := range tests {
+		if got := pois.Distance(tt.a, tt.b); math.Abs(got-tt.wantDist) > 1e-9 && got != tt.wantDist {
+			t.Errorf("Distance(%q, %q) = %g, want %g", tt.a, tt.b, got, tt.wantDist)
+		}
+		if got := pois.Route(tt.a, tt.b); !pathNearEq(got, tt.wantRoute) {
+			t.Errorf("Route(%q, %q)\n got: %v\nwant: %v", tt.a, tt.b, got, tt.wantRoute)
+		}
+	}
+
+	pois.Add("right", pathfind.Pt(25, 15))
+	if got, want := pois.Distance("top", "right"), 20.0; got != want {
+		t.Errorf("after moving: Distance(%q, %q) = %g, want %g", "top", "right", got, want)
+	}
+	pois.Remove("top")
+	if got := pois.Distance("top", "right"); !math.IsInf(got, 1) {
+		t.Errorf("after removing: Distance(%q, %q) = %g, want +Inf", "top", "right", got)
+	}
+	if got, want := pois.Distance("left", "right"), math.Sqrt(50)+math.Sqrt(250); math.Abs(got-want) > 1e-9 {
+		t.Errorf("after removing: Distance(%q, %q) = %g, want %g", "left", "right", got, want)
+	}
+}
+
+func TestPOISetAfterAddPolygon(t *testing.T) {
+	pathfinder := pathfind.NewPathfinder(polygonSquare)
+	pois := pathfind.NewPOISet(pathfinder)
+	pois.Add("left", pathfind.Pt(5, 20))
+	pois.Add("right", pathfind.Pt(35, 20))
+	if got, want := pois.Distance("left", "right"), 30.0; got != want {
+		t.Fatalf("Distance(%q, %q) = %g, want %g", "left", "right", got, want)
+	}
+
+	pathfinder.AddPolygon([]pathfind.Point{pathfind.Pt(15, 10), pathfind.Pt(25, 10), pathfind.Pt(25, 30), pathfind.Pt(15, 30)})
+	if got, want := pois.Distance("left", "right"), 2*math.Sqrt(200)+10; math.Abs(got-want) > 1e-3 {
+		t.Errorf("after AddPolygon: Distance(%q, %q) = %g, want %g", "left", "right", got, want)
+	}
+	want := []pathfind.Point{pathfind.Pt(5, 20), pathfind.Pt(15, 10), pathfind.Pt(25, 10), pathfind.Pt(35, 20)}
+	if got := pois.Route("left", "right"); !pathNearEq(got, want) {
+		t.Errorf("after AddPolygon: Route(%q, %q)\n got: %v\nwant: %v", "left", "right", got, want)
+	}
+}
@@ -1,6 +1,12 @@
 package pathfind
 
-import "fmt"
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
 
 type Point struct {
 	X, Y float64
@@ -18,6 +24,106 @@ func (p Point) Sub(q Point) Point {
 	return Point{X: p.X - q.X, Y: p.Y - q.Y}
 }
 
+// Mul returns the vector p scaled by s.
+func (p Point) Mul(s float64) Point {
+	return Point{X: p.X * s, Y: p.Y * s}
+}
+
+// Dot returns the dot product of p and q.
+func (p Point) Dot(q Point) float64 {
+	return p.X*q.X + p.Y*q.Y
+}
+
+// Norm returns the vector p scaled to length 1, the unit vector in the
+// direction of p. The zero vector is returned unchanged.
+func (p Point) Norm() Point {
+	l := p.Len()
+	if l == 0 {
+		return p
+	}
+	return Point{X: p.X / l, Y: p.Y / l}
+}
+
+// Dist returns the Euclidean distance between p and q.
+func (p Point) Dist(q Point) float64 {
+	return p.Sub(q).Len()
+}
+
+// Len returns the Euclidean length of p, its distance from the origin.
+func (p Point) Len() float64 {
+	return math.Sqrt(p.X*p.X + p.Y*p.Y)
+}
+
+// Rotate rotates p around origin by the angle radians, counterclockwise
+// for a y axis pointing up and clockwise for a y axis pointing down.
+func (p Point) Rotate(origin Point, radians float64) Point {
+	sin, cos := math.Sincos(radians)
+	d := p.Sub(origin)
+	return Point{
+		X: origin.X + d.X*cos - d.Y*sin,
+		Y: origin.Y + d.X*sin + d.Y*cos,
+	}
+}
+
+// isFinite reports whether both coordinates of p are neither NaN nor
+// infinite.
+func (p Point) isFinite() bool {
+	return !math.IsNaN(p.X) && !math.IsInf(p.X, 0) && !math.IsNaN(p.Y) && !math.IsInf(p.Y, 0)
+}
+
+// allFinite reports whether all of the points are finite.
+func allFinite(pts ...Point) bool {
+	for _, p := range pts {
+		if !p.isFinite() {
+			return false
+		}
+	}
+	return true
+}
+
 func (p Point) String() string {
 	return fmt.Sprintf("(%g,%g)", p.X, p.Y)
 }
+
+// MarshalJSON encodes p as a JSON array of its two coordinates, e.g. [5,5],
+// like a position in GeoJSON. The coordinates are written with the minimal
+// number of digits that represent them exactly, so decoding the result
+// yields p again. NaN and infinite coordinates cannot be represented in
+// JSON and result in an error.
+func (p Point) MarshalJSON() ([]byte, error) {
+	if !p.isFinite() {
+		return nil, fmt.Errorf("point %v has no JSON representation", p)
+	}
+	b := append([]byte{'['}, strconv.FormatFloat(p.X, 'g', -1, 64)...)
+	b = append(b, ',')
+	b = strconv.AppendFloat(b, p.Y, 'g', -1, 64)
+	return append(b, ']'), nil
+}
+
+// UnmarshalJSON decodes a point from a JSON array of exactly two numbers,
+// as written by MarshalJSON. For compatibility it also accepts an object
+// with the fields X and Y, the default encoding of a struct. A JSON null
+// leaves p unchanged.
+func (p *Point) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		return nil
+	case len(data) > 0 && data[0] == '{':
+		var obj struct{ X, Y float64 }
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return fmt.Errorf("invalid point: %w", err)
+		}
+		*p = Point{X: obj.X, Y: obj.Y}
+		return nil
+	}
+	var coords []float64
+	if err := json.Unmarshal(data, &coords); err != nil {
+		return fmt.Errorf("invalid point: %w", err)
+	}
+	if len(coords) != 2 {
+		return fmt.Errorf("invalid point: expected 2 coordinates, found %d", len(coords))
+	}
+	*p = Point{X: coords[0], Y: coords[1]}
+	return nil
+}
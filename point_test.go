@@ -0,0 +1,170 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"encoding/json"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+func TestPointRotate(t *testing.T) {
+	tests := []struct {
+		name    string
+		p       pathfind.Point
+		origin  pathfind.Point
+		radians float64
+		want    pathfind.Point
+	}{
+		{
+			name:    "quarter turn around zero",
+			p:       pathfind.Pt(1, 0),
+			radians: math.Pi / 2,
+			want:    pathfind.Pt(0, 1),
+		},
+		{
+			name:    "half turn around origin",
+			p:       pathfind.Pt(3, 2),
+			origin:  pathfind.Pt(1, 1),
+			radians: math.Pi,
+			want:    pathfind.Pt(-1, 0),
+		},
+		{
+			name:    "no rotation",
+			p:       pathfind.Pt(3, 2),
+			origin:  pathfind.Pt(1, 1),
+			radians: 0,
+			want:    pathfind.Pt(3, 2),
+		},
+		{
+			name:    "origin stays fixed",
+			p:       pathfind.Pt(1, 1),
+			origin:  pathfind.Pt(1, 1),
+			radians: 1,
+			want:    pathfind.Pt(1, 1),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.p.Rotate(tt.origin, tt.radians)
+			if !pathNearEq([]pathfind.Point{got}, []pathfind.Point{tt.want}) {
+				t.Errorf("%v.Rotate(%v, %g) = %v, want %v", tt.p, tt.origin, tt.radians, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPointDist(t *testing.T) {
+	tests := []struct {
+		p, q pathfind.Point
+		want float64
+	}{
+		{p: pathfind.Pt(1, 1), q: pathfind.Pt(4, 5), want: 5},
+		{p: pathfind.Pt(4, 5), q: pathfind.Pt(1, 1), want: 5},
+		{p: pathfind.Pt(-2, 3), q: pathfind.Pt(-2, 3), want: 0},
+	}
+	for _, tt := range tests {
+		if got := tt.p.Dist(tt.q); got != tt.want {
+			t.Errorf("%v.Dist(%v) = %g, want %g", tt.p, tt.q, got, tt.want)
+		}
+	}
+}
+
+func TestPointLen(t *testing.T) {
+	tests := []struct {
+		p    pathfind.Point
+		want float64
+	}{
+		{p: pathfind.Pt(3, 4), want: 5},
+		{p: pathfind.Pt(-3, -4), want: 5},
+		{p: pathfind.Pt(0, 0), want: 0},
+	}
+	for _, tt := range tests {
+		if got := tt.p.Len(); got != tt.want {
+			t.Errorf("%v.Len() = %g, want %g", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestPointMulDotNorm(t *testing.T) {
+	p := pathfind.Pt(3, -4)
+	if got, want := p.Mul(2), pathfind.Pt(6, -8); got != want {
+		t.Errorf("%v.Mul(2) = %v, want %v", p, got, want)
+	}
+	if got, want := p.Mul(0), pathfind.Pt(0, 0); got != want {
+		t.Errorf("%v.Mul(0) = %v, want %v", p, got, want)
+	}
+	if got, want := p.Dot(pathfind.Pt(2, 1)), 2.0; got != want {
+		t.Errorf("%v.Dot((2,1)) = %g, want %g", p, got, want)
+	}
+	if got, want := p.Dot(pathfind.Pt(4, 3)), 0.0; got != want {
+		t.Errorf("%v.Dot((4,3)) = %g, want %g", p, got, want)
+	}
+	if got, want := p.Norm(), pathfind.Pt(0.6, -0.8); !pathNearEq([]pathfind.Point{got}, []pathfind.Point{want}) || math.Abs(got.Len()-1) > 1e-12 {
+		t.Errorf("%v.Norm() = %v, want %v", p, got, want)
+	}
+	if got, want := pathfind.Pt(0, 0).Norm(), pathfind.Pt(0, 0); got != want {
+		t.Errorf("(0,0).Norm() = %v, want %v", got, want)
+	}
+}
+
+func TestPointJSON(t *testing.T) {
+	tests := []struct {
+		p    pathfind.Point
+		want string
+	}{
+		{p: pathfind.Pt(5, 5), want: `[5,5]`},
+		{p: pathfind.Pt(-0.5, 1e21), want: `[-0.5,1e+21]`},
+		{p: pathfind.Pt(0.1, 1.0/3), want: `[0.1,0.3333333333333333]`},
+	}
+	for _, tt := range tests {
+		got, err := json.Marshal(tt.p)
+		if err != nil {
+			t.Fatalf("json.Marshal(%v) returned error: %v", tt.p, err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("json.Marshal(%v) = %s, want %s", tt.p, got, tt.want)
+		}
+		var decoded pathfind.Point
+		if err := json.Unmarshal(got, &decoded); err != nil {
+			t.Fatalf("json.Unmarshal(%s) returned error: %v", got, err)
+		}
+		if decoded != tt.p {
+			t.Errorf("json.Unmarshal(%s) = %v, want %v", got, decoded, tt.p)
+		}
+	}
+}
+
+func TestPointUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		data    string
+		want    []pathfind.Point
+		wantErr bool
+	}{
+		{data: `[[1,2],[3.5,-4]]`, want: []pathfind.Point{pathfind.Pt(1, 2), pathfind.Pt(3.5, -4)}},
+		{data: `[{"X":1,"Y":2}]`, want: []pathfind.Point{pathfind.Pt(1, 2)}},
+		{data: `[null]`, want: []pathfind.Point{{}}},
+		{data: `[[1]]`, wantErr: true},
+		{data: `[[1,2,3]]`, wantErr: true},
+		{data: `[["1","2"]]`, wantErr: true},
+	}
+	for _, tt := range tests {
+		var got []pathfind.Point
+		err := json.Unmarshal([]byte(tt.data), &got)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("json.Unmarshal(%s) error = %v, want error: %t", tt.data, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !slices.Equal(got, tt.want) {
+			t.Errorf("json.Unmarshal(%s) = %v, want %v", tt.data, got, tt.want)
+		}
+	}
+	if _, err := json.Marshal(pathfind.Pt(math.NaN(), 0)); err == nil {
+		t.Errorf("json.Marshal of NaN point returned no error")
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// EncodePolygons writes a polygon set to w in the line-based text format
+// read by DecodePolygons. The coordinates are written with the minimal
+// number of digits that represent them exactly, so decoding the output
+// yields the original polygons. Polygons without vertices cannot be
+// represented in this format and are skipped.
+func EncodePolygons(w io.Writer, polygons [][]Point) error {
+	bw := bufio.NewWriter(w)
+	first := true
+	for _, polygon := range polygons {
+		if len(polygon) == 0 {
+			continue
+		}
+		if !first {
+			bw.WriteByte('\n')
+		}
+		first = false
+		for _, pt := range polygon {
+			bw.WriteString(strconv.FormatFloat(pt.X, 'g', -1, 64))
+			bw.WriteByte(' ')
+			bw.WriteString(strconv.FormatFloat(pt.Y, 'g', -1, 64))
+			bw.WriteByte('\n')
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("could not write polygons: %w", err)
+	}
+	return nil
+}
+
+// DecodePolygons reads a polygon set in a simple line-based text format
+// from r. Each line holds the X and Y coordinates of one vertex, separated
+// by whitespace. Polygons are separated by one or more blank lines.
+// For example:
+//
+//	0 0
+//	40 0
+//	40 40
+//	0 40
+//
+//	20 10
+//	30 20
+//	20 30
+//	10 20
+//
+// The input is read line by line. A line that does not consist of exactly
+// two numbers results in an error that reports the line number.
+func DecodePolygons(r io.Reader) ([][]Point, error) {
+	var (
+		polygons [][]Point
+		current  []Point
+	)
+	sc := bufio.NewScanner(r)
+	for lineNo := 1; sc.Scan(); lineNo++ {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			if current != nil {
+				polygons = append(polygons, current)
+				current = nil
+			}
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected 2 coordinates, found %d", lineNo, len(fields))
+		}
+		x, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid x coordinate: %w", lineNo, err)
+		}
+		y, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid y coordinate: %w", lineNo, err)
+		}
+		current = append(current, Pt(x, y))
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("could not read polygons: %w", err)
+	}
+	if current != nil {
+		polygons = append(polygons, current)
+	}
+	return polygons, nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+func TestDecodePolygons(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  [][]pathfind.Point
+	}{
+		{"empty input", "", nil},
+		{"only blank lines", "\n  \n\n", nil},
+		{
+			"two polygons",
+			"0 0\n40 0\n40 40\n0 40\n\n20 10\n30 20\n20 30\n10 20\n",
+			polygonO,
+		},
+		{
+			"extra whitespace and blank lines",
+			"\n\n  1.5\t-2  \n3e2 4\n\n\n\n5 6\n",
+			[][]pathfind.Point{
+				{pathfind.Pt(1.5, -2), pathfind.Pt(300, 4)},
+				{pathfind.Pt(5, 6)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pathfind.DecodePolygons(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("DecodePolygons(%q) returned unexpected error: %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodePolygons(%q)\n got: %v\nwant: %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodePolygonsErrors(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr string
+	}{
+		{"0 0\n1\n", "line 2: expected 2 coordinates, found 1"},
+		{"0 0 0\n", "line 1: expected 2 coordinates, found 3"},
+		{"0 0\n\nx 1\n", "line 3: invalid x coordinate"},
+		{"0 y\n", "line 1: invalid y coordinate"},
+	}
+	for _, tt := range tests {
+		_, err := pathfind.DecodePolygons(strings.NewReader(tt.input))
+		if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+			t.Errorf("DecodePolygons(%q) error = %v, want error starting with %q",
+				tt.input, err, tt.wantErr)
+		}
+	}
+}
+
+func TestEncodePolygons(t *testing.T) {
+	var buf bytes.Buffer
+	if err := pathfind.EncodePolygons(&buf, polygonO); err != nil {
+		t.Fatalf("EncodePolygons returned unexpected error: %v", err)
+	}
+	want := "0 0\n40 0\n40 40\n0 40\n\n20 10\n30 20\n20 30\n10 20\n"
+	if got := buf.String(); got != want {
+		t.Errorf("EncodePolygons(%v)\n got: %q\nwant: %q", polygonO, got, want)
+	}
+}
+
+func TestEncodeDecodePolygonsRoundTrip(t *testing.T) {
+	polygons := [][]pathfind.Point{
+		{
+			pathfind.Pt(0.1, 1.0/3),
+			pathfind.Pt(-123456.789, 2e-300),
+			pathfind.Pt(math.Pi, math.MaxFloat64),
+		},
+		{
+			pathfind.Pt(math.SmallestNonzeroFloat64, -0.5),
+		},
+	}
+	var buf bytes.Buffer
+	if err := pathfind.EncodePolygons(&buf, polygons); err != nil {
+		t.Fatalf("EncodePolygons returned unexpected error: %v", err)
+	}
+	got, err := pathfind.DecodePolygons(&buf)
+	if err != nil {
+		t.Fatalf("DecodePolygons returned unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, polygons) {
+		t.Errorf("round trip\n got: %v\nwant: %v", got, polygons)
+	}
+}
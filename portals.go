@@ -0,0 +1,96 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import "slices"
+
+// PathFunneled returns the shortest path from start to dest pulled taut
+// as with the simple stupid funnel algorithm over the portals that the path
+// passes through. The paths returned by Path are already taut, since they
+// only turn where they wrap around a polygon corner, so PathFunneled
+// returns the same path as Path, including all of its options.
+func (p *Pathfinder) PathFunneled(start, dest Point) []Point {
+	return p.Path(start, dest)
+}
+
+// A Portal is a line segment across the accessible area that a path passes
+// through, as returned by PathPortals. It leads from the polygon or wall
+// corner at which the path turns to the opposite polygon edge or wall.
+// Left and Right are its end points on the left and the right side in the
+// direction of travel, for a y axis pointing up; for a y axis pointing
+// down the sides are swapped. PolygonIndex is the index of the polygon
+// that the corner belongs to, or -1 if the portal does not start at a
+// polygon corner.
+type Portal struct {
+	Left, Right  Point
+	PolygonIndex int
+}
+
+// PathPortals returns the portals that the shortest path from start to dest
+// passes through, one for each turning point of the path returned by Path.
+// The portals bound the corridor of the path, e.g. for a custom smoothing
+// or for spreading the agents of a crowd across the width of the corridor.
+// A portal at a turning point that is neither a polygon corner nor a wall
+// corner, e.g. a node added with Graph.Link, has the length zero. The
+// result is nil if no path exists.
+func (p *Pathfinder) PathPortals(start, dest Point) []Portal {
+	path := p.Path(start, dest)
+	if len(path) == 0 {
+		return nil
+	}
+	return p.pathPortals(path)
+}
+
+// pathPortals returns the portals at the turning points of path, i.e. at
+// all of its waypoints except the first and the last.
+func (p *Pathfinder) pathPortals(path []Point) []Portal {
+	edges := p.edges()
+	indices := p.PathVertexIndices(path)
+	portals := make([]Portal, 0, max(len(path)-2, 0))
+	for i := 1; i < len(path)-1; i++ {
+		wp := path[i]
+		k := indices[i]
+		var corner Point
+		if k[0] >= 0 {
+			corner = openRing(p.polygons[k[0]])[k[1]]
+		} else if c, ok := p.wallCorner(wp); ok {
+			corner = c
+		} else {
+			portals = append(portals, Portal{Left: wp, Right: wp, PolygonIndex: -1})
+			continue
+		}
+		far, ok := castRay(wp, wp.Sub(corner).Norm(), edges)
+		if !ok {
+			far = wp
+		}
+		if cross(wp.Sub(path[i-1]), path[i+1].Sub(wp)) > 0 {
+			// Left turn: the corner is on the left.
+			portals = append(portals, Portal{Left: corner, Right: far, PolygonIndex: k[0]})
+		} else {
+			portals = append(portals, Portal{Left: far, Right: corner, PolygonIndex: k[0]})
+		}
+	}
+	return portals
+}
+
+// wallCorner returns the wall vertex that pt turns around if pt is one of
+// the turning points of a wall, i.e. the vertex of that wall nearest to pt.
+func (p *Pathfinder) wallCorner(pt Point) (Point, bool) {
+	d := p.wallDistance()
+	for _, w := range p.opts.wallPolylines {
+		if !slices.ContainsFunc(w.turningPoints(d), func(tp Point) bool { return p2v(tp) == p2v(pt) }) {
+			continue
+		}
+		vs := w.vertices()
+		corner := vs[0]
+		for _, v := range vs[1:] {
+			if nodeDist(v, pt) < nodeDist(corner, pt) {
+				corner = v
+			}
+		}
+		return corner, true
+	}
+	return Point{}, false
+}
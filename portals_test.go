@@ -0,0 +1,131 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+// polygonS is an area with two walls that force an S-shaped path from the
+// lower left to the upper right.
+var polygonS = [][]pathfind.Point{
+	{pathfind.Pt(0, 0), pathfind.Pt(60, 0), pathfind.Pt(60, 40), pathfind.Pt(0, 40)},
+	{pathfind.Pt(20, 5), pathfind.Pt(22, 5), pathfind.Pt(22, 39), pathfind.Pt(20, 39)},
+	{pathfind.Pt(40, 1), pathfind.Pt(42, 1), pathfind.Pt(42, 35), pathfind.Pt(40, 35)},
+}
+
+func TestPathfinderPathFunneled(t *testing.T) {
+	tests := []struct {
+		name        string
+		polygons    [][]pathfind.Point
+		start, dest pathfind.Point
+		want        []pathfind.Point
+	}{
+		{
+			name:     "line of sight",
+			polygons: polygonU,
+			start:    pathfind.Pt(5, 5),
+			dest:     pathfind.Pt(5, 15),
+			want:     []pathfind.Point{pathfind.Pt(5, 5), pathfind.Pt(5, 15)},
+		},
+		{
+			name:     "around two corners",
+			polygons: polygonU,
+			start:    pathfind.Pt(5, 5),
+			dest:     pathfind.Pt(25, 5),
+			want:     []pathfind.Point{pathfind.Pt(5, 5), pathfind.Pt(10, 10), pathfind.Pt(20, 10), pathfind.Pt(25, 5)},
+		},
+		{
+			name:     "corners on both sides",
+			polygons: polygonS,
+			start:    pathfind.Pt(10, 3),
+			dest:     pathfind.Pt(50, 38),
+			want:     []pathfind.Point{pathfind.Pt(10, 3), pathfind.Pt(22, 5), pathfind.Pt(40, 35), pathfind.Pt(50, 38)},
+		},
+		{
+			name:     "no path",
+			polygons: polygonU,
+			start:    pathfind.Pt(15, 5),
+			dest:     pathfind.Pt(25, 5),
+			want:     nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pathfinder := pathfind.NewPathfinder(tt.polygons)
+			got := pathfinder.PathFunneled(tt.start, tt.dest)
+			if !pathNearEq(got, tt.want) {
+				t.Errorf("PathFunneled(%v, %v) = %v, want %v", tt.start, tt.dest, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathfinderPathPortals(t *testing.T) {
+	tests := []struct {
+		name        string
+		polygons    [][]pathfind.Point
+		opts        []pathfind.Option
+		start, dest pathfind.Point
+		want        []pathfind.Portal
+	}{
+		{
+			name:     "line of sight",
+			polygons: polygonU,
+			start:    pathfind.Pt(5, 5),
+			dest:     pathfind.Pt(5, 15),
+			want:     []pathfind.Portal{},
+		},
+		{
+			name:     "corners on both sides",
+			polygons: polygonS,
+			start:    pathfind.Pt(10, 3),
+			dest:     pathfind.Pt(50, 38),
+			want: []pathfind.Portal{
+				{Left: pathfind.Pt(22, 5), Right: pathfind.Pt(27, 0), PolygonIndex: 1},
+				{Left: pathfind.Pt(35, 40), Right: pathfind.Pt(40, 35), PolygonIndex: 2},
+			},
+		},
+		{
+			name:     "walls",
+			polygons: polygonS[:2],
+			opts: []pathfind.Option{pathfind.WithWalls(
+				pathfind.WallPolyline{pathfind.Pt(25, 1), pathfind.Pt(25, 3)},
+				pathfind.WallPolyline{pathfind.Pt(41, 1), pathfind.Pt(41, 35)},
+			)},
+			start: pathfind.Pt(10, 3),
+			dest:  pathfind.Pt(50, 38),
+			want: []pathfind.Portal{
+				{Left: pathfind.Pt(22, 5), Right: pathfind.Pt(25, 2), PolygonIndex: 1},
+				{Left: pathfind.Pt(36, 40), Right: pathfind.Pt(41, 35), PolygonIndex: -1},
+			},
+		},
+		{
+			name:     "no path",
+			polygons: polygonU,
+			start:    pathfind.Pt(15, 5),
+			dest:     pathfind.Pt(25, 5),
+			want:     nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pathfinder := pathfind.NewPathfinder(tt.polygons, tt.opts...)
+			got := pathfinder.PathPortals(tt.start, tt.dest)
+			if len(got) != len(tt.want) || (got == nil) != (tt.want == nil) {
+				t.Fatalf("PathPortals(%v, %v) = %v, want %v", tt.start, tt.dest, got, tt.want)
+			}
+			for i, portal := range got {
+				want := tt.want[i]
+				if portal.PolygonIndex != want.PolygonIndex ||
+					!pathNearEq([]pathfind.Point{portal.Left, portal.Right}, []pathfind.Point{want.Left, want.Right}) {
+					t.Errorf("PathPortals(%v, %v)[%d] = %v, want %v", tt.start, tt.dest, i, portal, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+func TestPathfinderWithPreferredAreas(t *testing.T) {
+	polygons := [][]pathfind.Point{
+		{pathfind.Pt(0, 0), pathfind.Pt(100, 0), pathfind.Pt(100, 100), pathfind.Pt(0, 100)},
+		{pathfind.Pt(20, 70), pathfind.Pt(80, 70), pathfind.Pt(80, 80), pathfind.Pt(20, 80)},
+	}
+	tests := []struct {
+		name     string
+		discount float64
+		want     []pathfind.Point
+		wantArea float64
+	}{
+		{
+			name:     "road is worth the detour",
+			discount: 0.1,
+			want:     []pathfind.Point{pathfind.Pt(10, 50), pathfind.Pt(20, 70), pathfind.Pt(80, 70), pathfind.Pt(90, 50)},
+			wantArea: 10000,
+		},
+		{
+			name:     "road is not worth the detour",
+			discount: 0.9,
+			want:     []pathfind.Point{pathfind.Pt(10, 50), pathfind.Pt(90, 50)},
+			wantArea: 10000,
+		},
+		{
+			name:     "invalid discount makes a hole",
+			discount: 0,
+			want:     []pathfind.Point{pathfind.Pt(10, 50), pathfind.Pt(90, 50)},
+			wantArea: 9400,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pathfinder := pathfind.NewPathfinder(polygons, pathfind.WithPreferredAreas([]int{1}, tt.discount))
+			got := pathfinder.Path(pathfind.Pt(10, 50), pathfind.Pt(90, 50))
+			if !pathNearEq(got, tt.want) {
+				t.Errorf("Path = %v, want %v", got, tt.want)
+			}
+			if got := pathfinder.NavigableArea(); got != tt.wantArea {
+				t.Errorf("NavigableArea() = %g, want %g", got, tt.wantArea)
+			}
+		})
+	}
+}
@@ -0,0 +1,312 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/fzipp/pathfind/internal/poly"
+)
+
+// ReachableRegion returns the outline of the region that is reachable from
+// start: the area polygons of the connected component of start, as
+// reported by ComponentOf, each followed by the holes directly inside of
+// it, in the order of their indices. On a fully connected map this is the
+// area polygon with all its holes. With disconnected islands it is only the
+// island of start, together with the islands that are connected to it by
+// edges added with Graph.Link. The result is nil if start is not inside the
+// accessible area.
+//
+// The outlines are those of the polygons of the Pathfinder. An area that
+// the clearance of WithClearance splits into several components belongs to
+// the region of each of them.
+func (p *Pathfinder) ReachableRegion(start Point) [][]Point {
+	var region [][]Point
+	for _, a := range p.reachableAreas(start) {
+		region = append(region, slices.Clone(p.polygons[a]))
+		for i, parent := range p.parents {
+			if parent == a {
+				region = append(region, slices.Clone(p.polygons[i]))
+			}
+		}
+	}
+	return region
+}
+
+// reachableAreas returns the indices of the area polygons of the connected
+// component of start, in ascending order: the area of start and the areas
+// of all graph nodes in the same component. The result is nil if start is
+// not inside the accessible area.
+func (p *Pathfinder) reachableAreas(start Point) []int {
+	c := p.ComponentOf(start)
+	if c < 0 {
+		return nil
+	}
+	area, _ := p.regionOf(start)
+	areas := []int{area}
+	components, _ := p.connectedComponents()
+	for node, nc := range components {
+		if nc != c {
+			continue
+		}
+		if a, ok := p.regionOf(node); ok && !slices.Contains(areas, a) {
+			areas = append(areas, a)
+		}
+	}
+	slices.Sort(areas)
+	return areas
+}
+
+// PathRegions returns the indices of the polygons that the shortest path
+// from start to dest passes through, in the order of traversal, e.g. for
+// revealing rooms on a minimap. Rooms are the weighted regions of
+// NewWeightedPathfinder, e.g. with the weight 1 to keep the cost of the
+// paths, and the preferred areas of WithPreferredAreas. The path is split
+// where it crosses their outlines, and each piece is classified by the last
+// of these regions that contains it, the one whose weight applies, or else
+// by the innermost area polygon containing it. Consecutive duplicates are
+// removed. Since a path never leaves its area, the result without such
+// regions is the index of this area alone. The result is nil if no path
+// exists.
+func (p *Pathfinder) PathRegions(start, dest Point) []int {
+	path := p.Path(start, dest)
+	if len(path) == 0 {
+		return nil
+	}
+	var regions []int
+	for i := 1; i < len(path); i++ {
+		a, b := path[i-1], path[i]
+		ts := p.regionSplits(a, b)
+		for j := 1; j < len(ts); j++ {
+			if ts[j-1] == ts[j] {
+				// Where the outlines of two regions meet.
+				continue
+			}
+			r, ok := p.roomOf(lerp(a, b, (ts[j-1]+ts[j])/2))
+			if ok && (len(regions) == 0 || regions[len(regions)-1] != r) {
+				regions = append(regions, r)
+			}
+		}
+	}
+	return regions
+}
+
+// roomOf returns the index of the polygon that PathRegions reports for pt:
+// the last weighted region that contains pt, or else the innermost area
+// polygon containing it.
+func (p *Pathfinder) roomOf(pt Point) (int, bool) {
+	v := p2v(pt)
+	for i := len(p.regions) - 1; i >= 0; i-- {
+		if len(p.regions[i]) > 0 && p.regions[i].Contains(v, false) {
+			return i, true
+		}
+	}
+	return p.regionOf(pt)
+}
+
+// regionOf returns the index of the innermost area polygon that contains pt.
+// Points on the outline of an area or a hole belong to the area.
+// The result is false if pt lies outside of all areas or inside a hole.
+func (p *Pathfinder) regionOf(pt Point) (area int, ok bool) {
+	v := p2v(pt)
+	innermost := -1
+	for i, polygon := range p.polygonSet {
+		hole := p.depths[i]%2 == 1
+		if polygon.Contains(v, !hole) && (innermost < 0 || p.depths[i] > p.depths[innermost]) {
+			innermost = i
+		}
+	}
+	if innermost < 0 || p.depths[innermost]%2 == 1 {
+		return -1, false
+	}
+	return innermost, true
+}
+
+// polygonNesting determines how the polygons of ps are nested in each other.
+// For each polygon it returns the index of the innermost polygon containing
+// it, or -1 for a top-level polygon, and its depth, which is the number of
+// polygons containing it. Polygons with an odd depth are holes.
+func polygonNesting(ps poly.PolygonSet) (parents, depths []int) {
+	parents = make([]int, len(ps))
+	depths = make([]int, len(ps))
+	for i := range ps {
+		parents[i] = -1
+		if len(ps[i]) == 0 {
+			continue
+		}
+		for j, p := range ps {
+			if i != j && p.Contains(ps[i][0], false) {
+				depths[i]++
+			}
+		}
+	}
+	for i := range ps {
+		if len(ps[i]) == 0 {
+			continue
+		}
+		for j, p := range ps {
+			if i != j && depths[j] == depths[i]-1 && p.Contains(ps[i][0], false) {
+				parents[i] = j
+				break
+			}
+		}
+	}
+	return parents, depths
+}
+
+// IsReachable reports whether Path would find a path from start to dest,
+// with the same clamping of dest. It is cheaper than Path, because it only
+// checks whether dest can be reached in the visibility graph of the query
+// with a breadth-first search, without computing the shortest path.
+// Unlike Path, it does not call the function registered with OnClamp.
+func (p *Pathfinder) IsReachable(start, dest Point) bool {
+	if !allFinite(start, dest) {
+		return false
+	}
+	if clamped := p.clampDest(start, dest); clamped != dest {
+		if p.opts.strictBounds {
+			return false
+		}
+		dest = clamped
+	}
+	if containmentLevel(p.polygonSet, start) != containmentLevel(p.polygonSet, dest) {
+		return false
+	}
+	if p.opts.clearance > 0 {
+		var okStart, okDest bool
+		start, okStart = p.keepClear(start)
+		dest, okDest = p.keepClear(dest)
+		if !okStart || !okDest {
+			return false
+		}
+	}
+	if p.visible(start, dest) {
+		return true
+	}
+	s := p.getScratch()
+	defer p.scratchPool.Put(s)
+	vis, _ := p.prepareVisibilityGraph(context.Background(), start, dest, s)
+	return reachable(vis, start, dest)
+}
+
+// reachable reports whether node b can be reached from node a in graph g.
+func reachable(g graph[Point], a, b Point) bool {
+	visited := map[Point]bool{a: true}
+	queue := []Point{a}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		if n == b {
+			return true
+		}
+		for _, nb := range g[n] {
+			if !visited[nb] {
+				visited[nb] = true
+				queue = append(queue, nb)
+			}
+		}
+	}
+	return false
+}
+
+// Connected reports whether a path between a and b exists. Unlike Path, b
+// is not clamped to the polygon set, so Connected returns false if a or b
+// lie outside of the accessible area. It is equivalent to comparing the
+// results of ComponentOf for both points.
+func (p *Pathfinder) Connected(a, b Point) bool {
+	ca := p.ComponentOf(a)
+	return ca >= 0 && ca == p.ComponentOf(b)
+}
+
+// ComponentOf returns the id of the connected component of the accessible
+// area that pt belongs to, or -1 if pt lies outside of the accessible area.
+// Two points are connected by a path if and only if they have the same
+// component id. The ids are only meaningful for comparison and may change
+// when the polygon set or the visibility graph are modified. They are
+// preserved by GobEncode and LoadPathfinder.
+//
+// The connected components of the visibility graph are labeled on the first
+// call, so subsequent calls only need to find a visible graph node among
+// the nodes of the area that contains pt, which is much cheaper than
+// finding a path. Edges added with Graph.Link connect their nodes in both
+// directions for this purpose.
+func (p *Pathfinder) ComponentOf(pt Point) int {
+	if !pt.isFinite() {
+		return -1
+	}
+	area, ok := p.regionOf(pt)
+	if !ok {
+		return -1
+	}
+	components, n := p.connectedComponents()
+	if c, ok := components[pt]; ok {
+		return c
+	}
+	// A visible node lies within the bounding box of the area. The nearest
+	// nodes are the most likely to be visible.
+	box := p.boxes[area]
+	const eps = 1e-9
+	var nodes []Point
+	p.index.query(rect{
+		min: Point{X: box.min.X - eps, Y: box.min.Y - eps},
+		max: Point{X: box.max.X + eps, Y: box.max.Y + eps},
+	}, &nodes)
+	slices.SortFunc(nodes, func(a, b Point) int {
+		return cmp.Compare(nodeDist(pt, a), nodeDist(pt, b))
+	})
+	for _, node := range nodes {
+		if c, ok := components[node]; ok && p.inSight(pt, node) {
+			return c
+		}
+	}
+	// A point that does not see any graph node sees its entire area, which
+	// has no turning points and is therefore a component of its own.
+	return n + area
+}
+
+// connectedComponents returns the labels of the connected components of
+// the cached visibility graph, computing them if necessary, and the number
+// of components. The edges are treated as undirected.
+func (p *Pathfinder) connectedComponents() (map[Point]int, int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.components != nil {
+		return p.components, p.numComponents
+	}
+	undirected := make(graph[Point])
+	for a, adj := range p.cachedGraph {
+		undirected[a] = undirected[a]
+		for _, b := range adj {
+			undirected.link(a, b).link(b, a)
+		}
+	}
+	for _, v := range p.concaveVertices {
+		undirected[v] = undirected[v]
+	}
+	components := make(map[Point]int, len(undirected))
+	label := 0
+	for n := range undirected {
+		if _, ok := components[n]; ok {
+			continue
+		}
+		stack := []Point{n}
+		components[n] = label
+		for len(stack) > 0 {
+			m := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, nb := range undirected[m] {
+				if _, ok := components[nb]; !ok {
+					components[nb] = label
+					stack = append(stack, nb)
+				}
+			}
+		}
+		label++
+	}
+	p.components, p.numComponents = components, label
+	return components, label
+}
@@ -0,0 +1,233 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+// Two disjoint islands, the left one with a hole, the right one with a
+// hole containing another island.
+//
+//	 0,0 >-------+  >---------------+ 80,0
+//	     | >---+ |  | >-----------+ |
+//	     | |   | |  | |  >-----+  | |
+//	     | +---+ |  | |  |     |  | |
+//	     |       |  | |  +-----+  | |
+//	     |       |  | +-----------+ |
+//	0,40 +-------+  +---------------+ 80,40
+var polygonIslands = [][]pathfind.Point{
+	{pathfind.Pt(0, 0), pathfind.Pt(30, 0), pathfind.Pt(30, 40), pathfind.Pt(0, 40)},
+	{pathfind.Pt(5, 5), pathfind.Pt(25, 5), pathfind.Pt(25, 15), pathfind.Pt(5, 15)},
+	{pathfind.Pt(40, 0), pathfind.Pt(80, 0), pathfind.Pt(80, 40), pathfind.Pt(40, 40)},
+	{pathfind.Pt(45, 5), pathfind.Pt(75, 5), pathfind.Pt(75, 35), pathfind.Pt(45, 35)},
+	{pathfind.Pt(50, 10), pathfind.Pt(70, 10), pathfind.Pt(70, 30), pathfind.Pt(50, 30)},
+}
+
+func TestPathfinderReachableRegion(t *testing.T) {
+	tests := []struct {
+		name  string
+		start pathfind.Point
+		want  [][]pathfind.Point
+	}{
+		{
+			name:  "left island with hole",
+			start: pathfind.Pt(2, 2),
+			want:  [][]pathfind.Point{polygonIslands[0], polygonIslands[1]},
+		},
+		{
+			name:  "on hole outline",
+			start: pathfind.Pt(5, 10),
+			want:  [][]pathfind.Point{polygonIslands[0], polygonIslands[1]},
+		},
+		{
+			name:  "right island",
+			start: pathfind.Pt(42, 20),
+			want:  [][]pathfind.Point{polygonIslands[2], polygonIslands[3]},
+		},
+		{
+			name:  "island inside hole",
+			start: pathfind.Pt(60, 20),
+			want:  [][]pathfind.Point{polygonIslands[4]},
+		},
+		{
+			name:  "inside hole",
+			start: pathfind.Pt(10, 10),
+			want:  nil,
+		},
+		{
+			name:  "outside",
+			start: pathfind.Pt(35, 20),
+			want:  nil,
+		},
+	}
+	pathfinder := pathfind.NewPathfinder(polygonIslands)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pathfinder.ReachableRegion(tt.start)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReachableRegion(%v)\n got: %v\nwant: %v", tt.start, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathfinderReachableRegionLinked(t *testing.T) {
+	pathfinder := pathfind.NewPathfinder(polygonIslands)
+	left, right := pathfind.Pt(2, 2), pathfind.Pt(42, 20)
+	// A jump link between corners of the holes of both islands.
+	pathfinder.Graph().Link(pathfind.Pt(25, 15), pathfind.Pt(45, 35))
+	if !pathfinder.Connected(left, right) {
+		t.Fatalf("Connected(%v, %v) = false after Link, want true", left, right)
+	}
+	want := [][]pathfind.Point{polygonIslands[0], polygonIslands[1], polygonIslands[2], polygonIslands[3]}
+	for _, start := range []pathfind.Point{left, right} {
+		if got := pathfinder.ReachableRegion(start); !reflect.DeepEqual(got, want) {
+			t.Errorf("ReachableRegion(%v)\n got: %v\nwant: %v", start, got, want)
+		}
+	}
+	if got, want := pathfinder.ReachableRegion(pathfind.Pt(60, 20)), [][]pathfind.Point{polygonIslands[4]}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ReachableRegion(%v)\n got: %v\nwant: %v", pathfind.Pt(60, 20), got, want)
+	}
+}
+
+func TestPathfinderConnected(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b pathfind.Point
+		want bool
+	}{
+		{name: "line of sight", a: pathfind.Pt(2, 2), b: pathfind.Pt(28, 2), want: true},
+		{name: "around hole", a: pathfind.Pt(15, 2), b: pathfind.Pt(15, 38), want: true},
+		{name: "different islands", a: pathfind.Pt(2, 2), b: pathfind.Pt(42, 20), want: false},
+		{name: "island inside hole", a: pathfind.Pt(42, 20), b: pathfind.Pt(60, 20), want: false},
+		{name: "within island without corners", a: pathfind.Pt(52, 12), b: pathfind.Pt(68, 28), want: true},
+		{name: "inside hole", a: pathfind.Pt(2, 2), b: pathfind.Pt(10, 10), want: false},
+		{name: "outside", a: pathfind.Pt(2, 2), b: pathfind.Pt(35, 20), want: false},
+	}
+	pathfinder := pathfind.NewPathfinder(polygonIslands)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pathfinder.Connected(tt.a, tt.b); got != tt.want {
+				t.Errorf("Connected(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathfinderComponentOf(t *testing.T) {
+	pathfinder := pathfind.NewPathfinder(polygonIslands)
+	for _, pt := range []pathfind.Point{pathfind.Pt(10, 10), pathfind.Pt(35, 20), pathfind.Pt(100, 100)} {
+		if got := pathfinder.ComponentOf(pt); got != -1 {
+			t.Errorf("ComponentOf(%v) = %d, want -1", pt, got)
+		}
+	}
+	ids := map[int]bool{}
+	for _, pt := range []pathfind.Point{pathfind.Pt(2, 2), pathfind.Pt(42, 20), pathfind.Pt(60, 20)} {
+		id := pathfinder.ComponentOf(pt)
+		if id < 0 || ids[id] {
+			t.Errorf("ComponentOf(%v) = %d, want a new non-negative id", pt, id)
+		}
+		ids[id] = true
+	}
+	// The island at the right has no graph nodes, since it is convex, so
+	// its points are labeled by the island itself.
+	a, b := pathfind.Pt(52, 12), pathfind.Pt(68, 28)
+	if ca, cb := pathfinder.ComponentOf(a), pathfinder.ComponentOf(b); ca != cb || ca < 0 {
+		t.Errorf("ComponentOf(%v) = %d, ComponentOf(%v) = %d, want the same non-negative id", a, ca, b, cb)
+	}
+	if c := pathfind.Pt(42, 20); pathfinder.Connected(a, c) {
+		t.Errorf("Connected(%v, %v) = true, want false", a, c)
+	}
+	// Two convex islands without any graph nodes are separate components.
+	islands := pathfind.NewPathfinder([][]pathfind.Point{
+		{pathfind.Pt(0, 0), pathfind.Pt(10, 0), pathfind.Pt(10, 10), pathfind.Pt(0, 10)},
+		{pathfind.Pt(20, 0), pathfind.Pt(30, 0), pathfind.Pt(30, 10), pathfind.Pt(20, 10)},
+	})
+	a, b = pathfind.Pt(5, 5), pathfind.Pt(25, 5)
+	if islands.Connected(a, b) {
+		t.Errorf("Connected(%v, %v) between convex islands = true, want false", a, b)
+	}
+}
+
+func TestPathfinderPathRegions(t *testing.T) {
+	tests := []struct {
+		name        string
+		start, dest pathfind.Point
+		want        []int
+	}{
+		{name: "around hole", start: pathfind.Pt(2, 2), dest: pathfind.Pt(15, 38), want: []int{0}},
+		{name: "island inside hole", start: pathfind.Pt(52, 12), dest: pathfind.Pt(68, 28), want: []int{4}},
+		{name: "start outside", start: pathfind.Pt(35, 20), dest: pathfind.Pt(2, 2), want: nil},
+	}
+	pathfinder := pathfind.NewPathfinder(polygonIslands)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pathfinder.PathRegions(tt.start, tt.dest)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PathRegions(%v, %v) = %v, want %v", tt.start, tt.dest, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathfinderPathRegionsRooms(t *testing.T) {
+	rect := func(x0, y0, x1, y1 float64) []pathfind.Point {
+		return []pathfind.Point{pathfind.Pt(x0, y0), pathfind.Pt(x1, y0), pathfind.Pt(x1, y1), pathfind.Pt(x0, y1)}
+	}
+	// Two rooms side by side and a corridor overlapping both, which comes
+	// last and so takes precedence. Rooms of weight 1 do not change the
+	// cost of the paths.
+	pathfinder := pathfind.NewWeightedPathfinder([]pathfind.WeightedPolygon{
+		{Points: rect(0, 0, 60, 40)},
+		{Points: rect(0, 0, 30, 40), Weight: 1},
+		{Points: rect(30, 0, 60, 40), Weight: 1},
+		{Points: rect(20, 15, 40, 25), Weight: 1},
+	})
+	tests := []struct {
+		name        string
+		start, dest pathfind.Point
+		want        []int
+	}{
+		{name: "through the corridor", start: pathfind.Pt(5, 20), dest: pathfind.Pt(55, 20), want: []int{1, 3, 2}},
+		{name: "past the corridor", start: pathfind.Pt(5, 5), dest: pathfind.Pt(55, 5), want: []int{1, 2}},
+		{name: "within a room", start: pathfind.Pt(5, 5), dest: pathfind.Pt(25, 35), want: []int{1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pathfinder.PathRegions(tt.start, tt.dest)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PathRegions(%v, %v) = %v, want %v", tt.start, tt.dest, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathfinderIsReachable(t *testing.T) {
+	tests := []struct {
+		name        string
+		start, dest pathfind.Point
+	}{
+		{name: "line of sight", start: pathfind.Pt(2, 2), dest: pathfind.Pt(28, 2)},
+		{name: "around hole", start: pathfind.Pt(15, 2), dest: pathfind.Pt(15, 38)},
+		{name: "different islands", start: pathfind.Pt(2, 2), dest: pathfind.Pt(42, 20)},
+		{name: "destination clamped", start: pathfind.Pt(2, 2), dest: pathfind.Pt(-5, 20)},
+		{name: "destination in hole", start: pathfind.Pt(2, 2), dest: pathfind.Pt(10, 10)},
+		{name: "start outside", start: pathfind.Pt(35, 20), dest: pathfind.Pt(2, 2)},
+		{name: "island inside hole", start: pathfind.Pt(52, 12), dest: pathfind.Pt(68, 28)},
+	}
+	pathfinder := pathfind.NewPathfinder(polygonIslands)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := pathfinder.Path(tt.start, tt.dest) != nil
+			if got := pathfinder.IsReachable(tt.start, tt.dest); got != want {
+				t.Errorf("IsReachable(%v, %v) = %v, want %v", tt.start, tt.dest, got, want)
+			}
+		})
+	}
+}
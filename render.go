@@ -0,0 +1,156 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"slices"
+)
+
+// Colors of the images drawn by Render.
+var (
+	renderBackground = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	renderArea       = color.RGBA{R: 0xe0, G: 0xe0, B: 0xe0, A: 0xff}
+	renderHole       = color.RGBA{R: 0x60, G: 0x60, B: 0x60, A: 0xff}
+	renderOutline    = color.RGBA{A: 0xff}
+	renderRegion     = color.RGBA{R: 0x40, G: 0xa0, B: 0x40, A: 0xff}
+	renderVertex     = color.RGBA{R: 0x20, G: 0x60, B: 0xe0, A: 0xff}
+	renderPath       = color.RGBA{R: 0xe0, G: 0x20, B: 0x20, A: 0xff}
+)
+
+// renderRegionOpacity is the opacity of the weighted regions drawn by
+// Render over the polygons.
+const renderRegionOpacity = 0.4
+
+// Render draws the Pathfinder and the given path into a new image of the
+// given size, e.g. for visual regression tests that compare PNG files. It
+// shows the same as WriteSVG in the same colors on a white background: the
+// area polygons in light gray, the holes in dark gray, both with black
+// outlines, the walls as black lines, the weighted regions and preferred
+// areas as translucent green overlays, the concave vertices of the
+// visibility graph as blue dots and the path as a red line. The view covers
+// the bounding rectangle of the polygons with a margin of 5% of its larger
+// side, scaled uniformly to fit into the image and aligned to its top left
+// corner. As for WriteSVG, the Y axis points down. The path can be nil.
+//
+// The shapes are not anti-aliased, so the result for a given Pathfinder,
+// path and size is the same on every platform.
+func (p *Pathfinder) Render(size image.Point, path []Point) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, max(0, size.X), max(0, size.Y)))
+	draw.Draw(img, img.Bounds(), image.NewUniform(renderBackground), image.Point{}, draw.Src)
+	if img.Bounds().Empty() {
+		return img
+	}
+	v := p.view()
+	scale := min(float64(size.X)/(v.max.X-v.min.X), float64(size.Y)/(v.max.Y-v.min.Y))
+	toPixel := func(pt Point) Point {
+		return Point{X: (pt.X - v.min.X) * scale, Y: (pt.Y - v.min.Y) * scale}
+	}
+	set := func(c color.RGBA) func(x, y int) {
+		return func(x, y int) { img.SetRGBA(x, y, c) }
+	}
+
+	order := p.drawOrder()
+	for _, i := range order {
+		if len(p.polygonSet[i]) == 0 {
+			continue
+		}
+		ring := convert(openRing(p.polygons[i]), toPixel)
+		fill := renderArea
+		if p.depths[i]%2 == 1 {
+			fill = renderHole
+		}
+		fillRing(img.Bounds(), ring, set(fill))
+		for j := range ring {
+			drawLine(img.Bounds(), ring[j], ring[(j+1)%len(ring)], 1, set(renderOutline))
+		}
+	}
+	for _, w := range p.wallSegments() {
+		drawLine(img.Bounds(), toPixel(w[0]), toPixel(w[1]), 1, set(renderOutline))
+	}
+	for _, i := range order {
+		if len(p.polygonSet[i]) == 0 && len(p.polygons[i]) > 0 {
+			ring := convert(openRing(p.polygons[i]), toPixel)
+			fillRing(img.Bounds(), ring, func(x, y int) {
+				img.SetRGBA(x, y, blend(img.RGBAAt(x, y), renderRegion, renderRegionOpacity))
+			})
+		}
+	}
+	dot := max(1, float64(min(size.X, size.Y))/200)
+	for _, c := range p.concaveVertices {
+		fillDisc(img.Bounds(), toPixel(c), dot, set(renderVertex))
+	}
+	for i := 1; i < len(path); i++ {
+		drawLine(img.Bounds(), toPixel(path[i-1]), toPixel(path[i]), 2, set(renderPath))
+	}
+	return img
+}
+
+// fillRing calls plot for each pixel within bounds whose center lies inside
+// the ring of pixel coordinates, according to the even-odd rule.
+func fillRing(bounds image.Rectangle, ring []Point, plot func(x, y int)) {
+	var xs []float64
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		yc := float64(y) + 0.5
+		xs = xs[:0]
+		for j, a := range ring {
+			b := ring[(j+1)%len(ring)]
+			if (a.Y <= yc) != (b.Y <= yc) {
+				xs = append(xs, a.X+(yc-a.Y)*(b.X-a.X)/(b.Y-a.Y))
+			}
+		}
+		slices.Sort(xs)
+		for k := 0; k+1 < len(xs); k += 2 {
+			from := max(bounds.Min.X, int(math.Ceil(xs[k]-0.5)))
+			to := min(bounds.Max.X, int(math.Ceil(xs[k+1]-0.5)))
+			for x := from; x < to; x++ {
+				plot(x, y)
+			}
+		}
+	}
+}
+
+// drawLine calls plot for the pixels within bounds along the line from a to
+// b in pixel coordinates, with the given width in pixels.
+func drawLine(bounds image.Rectangle, a, b Point, width int, plot func(x, y int)) {
+	steps := max(1, int(math.Ceil(max(math.Abs(b.X-a.X), math.Abs(b.Y-a.Y)))))
+	for i := 0; i <= steps; i++ {
+		pt := lerp(a, b, float64(i)/float64(steps))
+		x0, y0 := int(math.Floor(pt.X-float64(width-1)/2)), int(math.Floor(pt.Y-float64(width-1)/2))
+		for x := x0; x < x0+width; x++ {
+			for y := y0; y < y0+width; y++ {
+				if (image.Point{X: x, Y: y}).In(bounds) {
+					plot(x, y)
+				}
+			}
+		}
+	}
+}
+
+// fillDisc calls plot for each pixel within bounds whose center lies within
+// the radius r around the center c in pixel coordinates.
+func fillDisc(bounds image.Rectangle, c Point, r float64, plot func(x, y int)) {
+	area := image.Rect(int(math.Floor(c.X-r)), int(math.Floor(c.Y-r)), int(math.Ceil(c.X+r))+1, int(math.Ceil(c.Y+r))+1)
+	area = area.Intersect(bounds)
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			if c.Dist(Point{X: float64(x) + 0.5, Y: float64(y) + 0.5}) <= r {
+				plot(x, y)
+			}
+		}
+	}
+}
+
+// blend returns the color c painted over the color dst with the given
+// opacity between 0 and 1.
+func blend(dst, c color.RGBA, opacity float64) color.RGBA {
+	mix := func(d, s uint8) uint8 {
+		return uint8(math.Round(float64(d)*(1-opacity) + float64(s)*opacity))
+	}
+	return color.RGBA{R: mix(dst.R, c.R), G: mix(dst.G, c.G), B: mix(dst.B, c.B), A: mix(dst.A, c.A)}
+}
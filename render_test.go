@@ -0,0 +1,56 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+func TestPathfinderRender(t *testing.T) {
+	pathfinder := pathfind.NewWeightedPathfinder([]pathfind.WeightedPolygon{
+		{Points: polygonO[0]},
+		{Points: polygonO[1]},
+		{Points: []pathfind.Point{pathfind.Pt(30, 30), pathfind.Pt(38, 30), pathfind.Pt(38, 38), pathfind.Pt(30, 38)}, Weight: 2},
+	})
+	path := []pathfind.Point{pathfind.Pt(5, 5), pathfind.Pt(5, 35)}
+	// The view from (-2,-2) to (42,42) is scaled by 10.
+	img := pathfinder.Render(image.Pt(440, 500), path)
+	if got, want := img.Bounds(), image.Rect(0, 0, 440, 500); got != want {
+		t.Fatalf("Render bounds = %v, want %v", got, want)
+	}
+	tests := []struct {
+		name string
+		pt   image.Point
+		want color.RGBA
+	}{
+		{name: "background", pt: image.Pt(5, 5), want: color.RGBA{0xff, 0xff, 0xff, 0xff}},
+		{name: "below the view", pt: image.Pt(200, 470), want: color.RGBA{0xff, 0xff, 0xff, 0xff}},
+		{name: "area", pt: image.Pt(150, 300), want: color.RGBA{0xe0, 0xe0, 0xe0, 0xff}},
+		{name: "outline", pt: image.Pt(100, 20), want: color.RGBA{0x00, 0x00, 0x00, 0xff}},
+		{name: "hole", pt: image.Pt(220, 220), want: color.RGBA{0x60, 0x60, 0x60, 0xff}},
+		{name: "region", pt: image.Pt(340, 340), want: color.RGBA{0xa0, 0xc6, 0xa0, 0xff}},
+		{name: "vertex", pt: image.Pt(220, 120), want: color.RGBA{0x20, 0x60, 0xe0, 0xff}},
+		{name: "path", pt: image.Pt(70, 200), want: color.RGBA{0xe0, 0x20, 0x20, 0xff}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := img.RGBAAt(tt.pt.X, tt.pt.Y); got != tt.want {
+				t.Errorf("pixel at %v = %v, want %v", tt.pt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathfinderRenderEmptySize(t *testing.T) {
+	pathfinder := pathfind.NewPathfinder(polygonU)
+	img := pathfinder.Render(image.Pt(0, -5), nil)
+	if !img.Bounds().Empty() {
+		t.Errorf("Render bounds = %v, want empty", img.Bounds())
+	}
+}
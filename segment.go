@@ -0,0 +1,68 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/fzipp/pathfind/internal/poly"
+)
+
+// SegmentsIntersect returns the point where the line segment from a1 to a2
+// crosses the line segment from b1 to b2. The result is false if the
+// segments do not cross.
+//
+// The test is the one used by the Pathfinder for its line of sight tests,
+// with the same precision: only proper crossings count. Segments that
+// merely touch, e.g. at a common end point or where an end point of one
+// segment lies on the other, do not cross. Parallel segments never cross,
+// and neither do collinear segments, even if they overlap.
+func SegmentsIntersect(a1, a2, b1, b2 Point) (Point, bool) {
+	a := poly.LineSeg{A: p2v(a1), B: p2v(a2)}
+	b := poly.LineSeg{A: p2v(b1), B: p2v(b2)}
+	if !a.Crosses(b) {
+		return Point{}, false
+	}
+	// The crossing point is computed with the full precision of the
+	// coordinates.
+	d1 := a2.Sub(a1)
+	d2 := b2.Sub(b1)
+	denom := cross(d1, d2)
+	if denom == 0 {
+		return Point{}, false
+	}
+	t := max(0, min(1, cross(b1.Sub(a1), d2)/denom))
+	return Point{X: a1.X + t*d1.X, Y: a1.Y + t*d1.Y}, true
+}
+
+// PathRegionCrossings returns the points where path crosses the boundary
+// of the polygon region, in the order in which they are passed along the
+// path, e.g. to trigger "entered zone" and "left zone" events at the
+// precise locations. As with SegmentsIntersect, only proper crossings
+// count: a path that merely touches the boundary does not cross it.
+func (p *Pathfinder) PathRegionCrossings(path []Point, region []Point) []Point {
+	var crossings []Point
+	for i := 1; i < len(path); i++ {
+		a, b := path[i-1], path[i]
+		n := len(crossings)
+		for j := range region {
+			e1, e2 := region[j], region[(j+1)%len(region)]
+			if pt, ok := SegmentsIntersect(a, b, e1, e2); ok {
+				crossings = append(crossings, pt)
+			}
+		}
+		slices.SortFunc(crossings[n:], func(u, v Point) int {
+			return cmp.Compare(nodeDist(a, u), nodeDist(a, v))
+		})
+	}
+	return crossings
+}
+
+// cross returns the z component of the cross product of p and q
+// extended to three dimensions.
+func cross(p, q Point) float64 {
+	return p.X*q.Y - p.Y*q.X
+}
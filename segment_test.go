@@ -0,0 +1,130 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+func TestSegmentsIntersect(t *testing.T) {
+	tests := []struct {
+		name       string
+		a1, a2     pathfind.Point
+		b1, b2     pathfind.Point
+		wantPt     pathfind.Point
+		wantExists bool
+	}{
+		{
+			name: "X-shaped segments cross",
+			a1:   pathfind.Pt(-2, -1), a2: pathfind.Pt(2, 1),
+			b1: pathfind.Pt(-2, 1), b2: pathfind.Pt(2, -1),
+			wantPt: pathfind.Pt(0, 0), wantExists: true,
+		},
+		{
+			name: "perpendicular segments cross",
+			a1:   pathfind.Pt(0, 0), a2: pathfind.Pt(4, 0),
+			b1: pathfind.Pt(3, 2), b2: pathfind.Pt(3, -2),
+			wantPt: pathfind.Pt(3, 0), wantExists: true,
+		},
+		{
+			name: "perpendicular segments with gap",
+			a1:   pathfind.Pt(0, 0), a2: pathfind.Pt(4, 0),
+			b1: pathfind.Pt(5, 2), b2: pathfind.Pt(5, -2),
+		},
+		{
+			name: "end point touching other segment",
+			a1:   pathfind.Pt(0, 0), a2: pathfind.Pt(4, 0),
+			b1: pathfind.Pt(4, 2), b2: pathfind.Pt(4, -2),
+		},
+		{
+			name: "common end point",
+			a1:   pathfind.Pt(3, 2), a2: pathfind.Pt(5, 3),
+			b1: pathfind.Pt(3, 2), b2: pathfind.Pt(5, 7),
+		},
+		{
+			name: "parallel segments",
+			a1:   pathfind.Pt(1, 3), a2: pathfind.Pt(5, 7),
+			b1: pathfind.Pt(1, 4), b2: pathfind.Pt(5, 8),
+		},
+		{
+			name: "collinear overlapping segments",
+			a1:   pathfind.Pt(0, 0), a2: pathfind.Pt(4, 0),
+			b1: pathfind.Pt(2, 0), b2: pathfind.Pt(6, 0),
+		},
+		{
+			name: "identical segments",
+			a1:   pathfind.Pt(-3, 2), a2: pathfind.Pt(3, 2),
+			b1: pathfind.Pt(-3, 2), b2: pathfind.Pt(3, 2),
+		},
+		{
+			name: "small protrusion crosses",
+			a1:   pathfind.Pt(0, 0), a2: pathfind.Pt(4, 0),
+			b1: pathfind.Pt(3.999999, 2), b2: pathfind.Pt(3.999999, -2),
+			wantPt: pathfind.Pt(3.999999, 0), wantExists: true,
+		},
+		{
+			// Like for the line of sight tests, the protrusion is below
+			// the precision of the test, so the end point touches.
+			name: "protrusion below precision touches",
+			a1:   pathfind.Pt(0, 0), a2: pathfind.Pt(4, 0),
+			b1: pathfind.Pt(3.99999999, 2), b2: pathfind.Pt(3.99999999, -2),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pt, exists := pathfind.SegmentsIntersect(tt.a1, tt.a2, tt.b1, tt.b2)
+			if pt != tt.wantPt || exists != tt.wantExists {
+				t.Errorf("SegmentsIntersect(%v, %v, %v, %v) = %v, %v, want %v, %v",
+					tt.a1, tt.a2, tt.b1, tt.b2, pt, exists, tt.wantPt, tt.wantExists)
+			}
+		})
+	}
+}
+
+func TestPathfinderPathRegionCrossings(t *testing.T) {
+	zone := []pathfind.Point{pathfind.Pt(10, 10), pathfind.Pt(20, 10), pathfind.Pt(20, 20), pathfind.Pt(10, 20)}
+	tests := []struct {
+		name string
+		path []pathfind.Point
+		want []pathfind.Point
+	}{
+		{
+			name: "through the zone",
+			path: []pathfind.Point{pathfind.Pt(0, 15), pathfind.Pt(30, 15)},
+			want: []pathfind.Point{pathfind.Pt(10, 15), pathfind.Pt(20, 15)},
+		},
+		{
+			name: "through the zone backwards",
+			path: []pathfind.Point{pathfind.Pt(30, 15), pathfind.Pt(0, 15)},
+			want: []pathfind.Point{pathfind.Pt(20, 15), pathfind.Pt(10, 15)},
+		},
+		{
+			name: "enter, turn inside and leave",
+			path: []pathfind.Point{pathfind.Pt(15, 0), pathfind.Pt(15, 15), pathfind.Pt(30, 15)},
+			want: []pathfind.Point{pathfind.Pt(15, 10), pathfind.Pt(20, 15)},
+		},
+		{
+			name: "passing by",
+			path: []pathfind.Point{pathfind.Pt(0, 5), pathfind.Pt(30, 5)},
+			want: nil,
+		},
+		{
+			name: "touching a corner",
+			path: []pathfind.Point{pathfind.Pt(0, 0), pathfind.Pt(10, 10), pathfind.Pt(0, 20)},
+			want: nil,
+		},
+	}
+	pathfinder := pathfind.NewPathfinder(polygonO)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pathfinder.PathRegionCrossings(tt.path, zone)
+			if !pathNearEq(got, tt.want) {
+				t.Errorf("PathRegionCrossings(%v)\n got: %v\nwant: %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import "slices"
+
+// StraightenPath shortens path by relinking: in each pass, every inner
+// waypoint is removed whose neighbours are in line of sight of each other.
+// Passes are repeated until no waypoint can be removed or the limit of the
+// WithSmoothingIterations option is reached. The first and last waypoints
+// are kept. Segments of path that were in the accessible area stay inside
+// it, so StraightenPath can be used for paths from other sources, e.g. paths
+// that were edited by hand or recorded from an agent's movement.
+//
+// The paths returned by Path are already as short as possible, so
+// StraightenPath returns them unchanged. The result is a new slice; path
+// is not modified.
+func (p *Pathfinder) StraightenPath(path []Point) []Point {
+	path = slices.Clone(path)
+	for pass := 0; p.opts.smoothingIters <= 0 || pass < p.opts.smoothingIters; pass++ {
+		n := len(path)
+		path = p.relinkPass(path)
+		if len(path) == n {
+			break
+		}
+	}
+	return path
+}
+
+// relinkPass removes the inner waypoints of path whose predecessor in the
+// result and successor are in line of sight of each other. It reuses the
+// backing array of path.
+func (p *Pathfinder) relinkPass(path []Point) []Point {
+	if len(path) < 3 {
+		return path
+	}
+	res := path[:1]
+	for i := 1; i < len(path)-1; i++ {
+		if !p.inSight(res[len(res)-1], path[i+1]) {
+			res = append(res, path[i])
+		}
+	}
+	return append(res, path[len(path)-1])
+}
+
+// ValidatePath reports whether path stays within the accessible area: all
+// of its points must lie inside of it and each point must be in line of
+// sight of the next one. With the WithClearance option each segment must
+// also keep the clearance to the polygon edges. This is useful as an
+// assertion after custom post-processing of a path, e.g. smoothing or
+// simplification, to make sure that no shortcut clips a hole. A point on
+// a polygon outline counts as inside, like for Contains. An empty path is
+// not valid.
+func (p *Pathfinder) ValidatePath(path []Point) bool {
+	if len(path) == 0 {
+		return false
+	}
+	for i, pt := range path {
+		if !pt.isFinite() || !p.Contains(pt) {
+			return false
+		}
+		if i > 0 && !p.visible(path[i-1], pt) {
+			return false
+		}
+	}
+	return true
+}
+
+// SimplifyPath removes the waypoints of path that deviate by at most
+// epsilon from the polyline of the remaining waypoints, using the
+// Ramer–Douglas–Peucker algorithm. With a small epsilon it only drops
+// redundant waypoints that lie on or very close to the straight line
+// between their neighbours, e.g. to reduce animation keyframes; an epsilon
+// of 0 drops exactly collinear waypoints only. The first and last waypoints
+// are kept.
+//
+// SimplifyPath does not know about the polygons, so a large epsilon can cut
+// corners that route around an obstacle. The WithSimplification option
+// simplifies the paths of a Pathfinder without leaving the accessible area.
+// The result is a new slice; path is not modified.
+func SimplifyPath(path []Point, epsilon float64) []Point {
+	return keepWaypoints(path, simplifyMask(path, epsilon, nil))
+}
+
+// simplifyPath applies the WithSimplification option to path, if it is set.
+func (p *Pathfinder) simplifyPath(path []Point) []Point {
+	if !p.opts.simplify {
+		return path
+	}
+	return keepWaypoints(path, p.simplifyMask(path))
+}
+
+// simplifyMask marks the waypoints of path that are kept by the
+// WithSimplification option. Shortcuts are only taken if they are in line
+// of sight.
+func (p *Pathfinder) simplifyMask(path []Point) []bool {
+	return simplifyMask(path, p.opts.simplifyEps, p.visible)
+}
+
+// simplifyMask marks the waypoints of path that are kept by the
+// Ramer–Douglas–Peucker algorithm with tolerance epsilon. If visible is not
+// nil, a shortcut between two waypoints is only taken if visible reports
+// true for them.
+func simplifyMask(path []Point, epsilon float64, visible func(a, b Point) bool) []bool {
+	keep := make([]bool, len(path))
+	if len(path) == 0 {
+		return keep
+	}
+	keep[0], keep[len(path)-1] = true, true
+	type span struct{ first, last int }
+	stack := []span{{0, len(path) - 1}}
+	for len(stack) > 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if s.last-s.first < 2 {
+			continue
+		}
+		farthest, dist := s.first+1, -1.0
+		for i := s.first + 1; i < s.last; i++ {
+			if d := segmentPointDist(path[s.first], path[s.last], path[i]); d > dist {
+				farthest, dist = i, d
+			}
+		}
+		if dist <= epsilon && (visible == nil || visible(path[s.first], path[s.last])) {
+			continue
+		}
+		keep[farthest] = true
+		stack = append(stack, span{s.first, farthest}, span{farthest, s.last})
+	}
+	return keep
+}
+
+// maxTensionSteps is the number of times SmoothPath halves the tangents of
+// a spline segment that leaves the accessible area before it falls back to
+// the straight segment, unless set by WithSplineTensionSteps.
+const maxTensionSteps = 8
+
+// SmoothPath fits a Catmull-Rom spline through the waypoints of path, e.g.
+// for a vehicle that cannot turn on the spot, and returns it as a denser
+// polyline with samplesPerSegment points for each segment of path plus the
+// last waypoint. The curve passes through all waypoints of path.
+//
+// Near concave corners the curve could bulge through a wall. Therefore the
+// tangents of each spline segment whose polyline leaves the accessible area
+// are halved repeatedly, which pulls the curve towards the straight
+// segment, until the segment stays inside. The number of halvings is
+// limited by WithSplineTensionSteps; if the curve still leaves the area,
+// the straight segment is used. The result is a new slice; path is not
+// modified. For samplesPerSegment less than 1, a copy of path is returned.
+func (p *Pathfinder) SmoothPath(path []Point, samplesPerSegment int) []Point {
+	if samplesPerSegment < 1 || len(path) < 2 {
+		return slices.Clone(path)
+	}
+	steps := maxTensionSteps
+	if p.opts.tensionSteps > 0 {
+		steps = p.opts.tensionSteps
+	}
+	n := len(path)
+	tangents := make([]Point, n)
+	for i := range path {
+		prev, next := path[max(i-1, 0)], path[min(i+1, n-1)]
+		tangents[i] = next.Sub(prev)
+		if i > 0 && i < n-1 {
+			tangents[i] = Point{X: tangents[i].X / 2, Y: tangents[i].Y / 2}
+		}
+	}
+	res := make([]Point, 0, (n-1)*samplesPerSegment+1)
+	segment := make([]Point, samplesPerSegment+1)
+	for i := 1; i < n; i++ {
+		a, b := path[i-1], path[i]
+		ma, mb := tangents[i-1], tangents[i]
+		for step, f := 0, 1.0; ; step, f = step+1, f/2 {
+			if step == steps {
+				f = 0
+			}
+			for k := range segment {
+				segment[k] = hermite(a, b, ma, mb, f, float64(k)/float64(samplesPerSegment))
+			}
+			if f == 0 || p.polylineVisible(segment) {
+				break
+			}
+		}
+		res = append(res, segment[:samplesPerSegment]...)
+	}
+	return append(res, path[n-1])
+}
+
+// hermite returns the point at parameter t of the cubic Hermite curve from
+// a to b with the tangents ma and mb scaled by f.
+func hermite(a, b, ma, mb Point, f, t float64) Point {
+	t2, t3 := t*t, t*t*t
+	h00 := 2*t3 - 3*t2 + 1
+	h10 := (t3 - 2*t2 + t) * f
+	h01 := -2*t3 + 3*t2
+	h11 := (t3 - t2) * f
+	return Point{
+		X: h00*a.X + h10*ma.X + h01*b.X + h11*mb.X,
+		Y: h00*a.Y + h10*ma.Y + h01*b.Y + h11*mb.Y,
+	}
+}
+
+// polylineVisible reports whether all segments of the polyline are in line
+// of sight.
+func (p *Pathfinder) polylineVisible(polyline []Point) bool {
+	for i := 1; i < len(polyline); i++ {
+		if !p.visible(polyline[i-1], polyline[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// keepWaypoints returns a new slice with the waypoints of path marked in keep.
+func keepWaypoints[T any](path []T, keep []bool) []T {
+	if path == nil {
+		return nil
+	}
+	res := make([]T, 0, len(path))
+	for i, pt := range path {
+		if keep[i] {
+			res = append(res, pt)
+		}
+	}
+	return res
+}
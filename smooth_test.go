@@ -0,0 +1,281 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+func TestPathfinderStraightenPath(t *testing.T) {
+	// A detour around the diamond of polygonO. The waypoint (5,30) can
+	// only be removed after (5,15) has been removed in the first pass.
+	detour := []pathfind.Point{
+		pathfind.Pt(30, 5),
+		pathfind.Pt(30, 30),
+		pathfind.Pt(5, 30),
+		pathfind.Pt(5, 15),
+		pathfind.Pt(10, 35),
+	}
+	tests := []struct {
+		name string
+		opts []pathfind.Option
+		path []pathfind.Point
+		want []pathfind.Point
+	}{
+		{
+			name: "until unchanged",
+			path: detour,
+			want: []pathfind.Point{pathfind.Pt(30, 5), pathfind.Pt(30, 30), pathfind.Pt(10, 35)},
+		},
+		{
+			name: "single pass",
+			opts: []pathfind.Option{pathfind.WithSmoothingIterations(1)},
+			path: detour,
+			want: []pathfind.Point{pathfind.Pt(30, 5), pathfind.Pt(30, 30), pathfind.Pt(5, 30), pathfind.Pt(10, 35)},
+		},
+		{
+			name: "direct line of sight",
+			path: []pathfind.Point{pathfind.Pt(5, 5), pathfind.Pt(15, 5), pathfind.Pt(5, 15), pathfind.Pt(35, 5)},
+			want: []pathfind.Point{pathfind.Pt(5, 5), pathfind.Pt(35, 5)},
+		},
+		{
+			name: "too short",
+			path: []pathfind.Point{pathfind.Pt(5, 5), pathfind.Pt(35, 5)},
+			want: []pathfind.Point{pathfind.Pt(5, 5), pathfind.Pt(35, 5)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pathfinder := pathfind.NewPathfinder(polygonO, tt.opts...)
+			orig := append([]pathfind.Point(nil), tt.path...)
+			got := pathfinder.StraightenPath(tt.path)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("StraightenPath(%v)\n got: %v\nwant: %v", tt.path, got, tt.want)
+			}
+			if !reflect.DeepEqual(tt.path, orig) {
+				t.Errorf("StraightenPath modified its argument: %v", tt.path)
+			}
+		})
+	}
+}
+
+func TestSimplifyPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    []pathfind.Point
+		epsilon float64
+		want    []pathfind.Point
+	}{
+		{
+			name:    "collinear waypoints",
+			path:    []pathfind.Point{pathfind.Pt(0, 0), pathfind.Pt(5, 5), pathfind.Pt(10, 10), pathfind.Pt(20, 10)},
+			epsilon: 0,
+			want:    []pathfind.Point{pathfind.Pt(0, 0), pathfind.Pt(10, 10), pathfind.Pt(20, 10)},
+		},
+		{
+			name:    "nearly collinear waypoint",
+			path:    []pathfind.Point{pathfind.Pt(0, 0), pathfind.Pt(10, 0.001), pathfind.Pt(20, 0), pathfind.Pt(20, 10)},
+			epsilon: 0.01,
+			want:    []pathfind.Point{pathfind.Pt(0, 0), pathfind.Pt(20, 0), pathfind.Pt(20, 10)},
+		},
+		{
+			name:    "corner beyond epsilon",
+			path:    []pathfind.Point{pathfind.Pt(0, 0), pathfind.Pt(10, 1), pathfind.Pt(20, 0)},
+			epsilon: 0.5,
+			want:    []pathfind.Point{pathfind.Pt(0, 0), pathfind.Pt(10, 1), pathfind.Pt(20, 0)},
+		},
+		{
+			name:    "farthest waypoint kept first",
+			path:    []pathfind.Point{pathfind.Pt(0, 0), pathfind.Pt(5, 1), pathfind.Pt(10, 5), pathfind.Pt(15, 1), pathfind.Pt(20, 0)},
+			epsilon: 2,
+			want:    []pathfind.Point{pathfind.Pt(0, 0), pathfind.Pt(10, 5), pathfind.Pt(20, 0)},
+		},
+		{
+			name:    "too short",
+			path:    []pathfind.Point{pathfind.Pt(0, 0), pathfind.Pt(20, 0)},
+			epsilon: 1,
+			want:    []pathfind.Point{pathfind.Pt(0, 0), pathfind.Pt(20, 0)},
+		},
+		{
+			name:    "nil",
+			path:    nil,
+			epsilon: 1,
+			want:    nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pathfind.SimplifyPath(tt.path, tt.epsilon)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SimplifyPath(%v, %g)\n got: %v\nwant: %v", tt.path, tt.epsilon, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathfinderWithSimplification(t *testing.T) {
+	// The tops of the two notches lie on a straight line, so the path
+	// passes four collinear corners.
+	polygons := [][]pathfind.Point{{
+		pathfind.Pt(0, 0), pathfind.Pt(10, 0), pathfind.Pt(10, 10), pathfind.Pt(15, 10),
+		pathfind.Pt(15, 0), pathfind.Pt(25, 0), pathfind.Pt(25, 10), pathfind.Pt(30, 10),
+		pathfind.Pt(30, 0), pathfind.Pt(40, 0), pathfind.Pt(40, 20), pathfind.Pt(0, 20),
+	}}
+	start, dest := pathfind.Pt(5, 5), pathfind.Pt(35, 5)
+	tests := []struct {
+		name    string
+		epsilon float64
+		want    int
+	}{
+		{name: "collinear corners dropped", epsilon: 0.01, want: 4},
+		{name: "no corner cutting", epsilon: 100, want: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pathfinder := pathfind.NewPathfinder(polygons, pathfind.WithSimplification(tt.epsilon))
+			got := pathfinder.Path(start, dest)
+			if len(got) != tt.want {
+				t.Fatalf("Path(%v, %v) = %v, want %d waypoints", start, dest, got, tt.want)
+			}
+			if d := pathfind.PathDeviation(got, pathfind.NewPathfinder(polygons).Path(start, dest)); d > 0.01 {
+				t.Errorf("Path(%v, %v) = %v deviates by %g from the unsimplified path", start, dest, got, d)
+			}
+		})
+	}
+}
+
+func TestPathfinderSmoothPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		polygons [][]pathfind.Point
+		opts     []pathfind.Option
+		path     []pathfind.Point
+		samples  int
+	}{
+		{
+			name:     "around two corners",
+			polygons: polygonU,
+			path:     []pathfind.Point{pathfind.Pt(5, 5), pathfind.Pt(10, 10), pathfind.Pt(20, 10), pathfind.Pt(25, 5)},
+			samples:  8,
+		},
+		{
+			name:     "tight turns around walls",
+			polygons: polygonS,
+			path:     []pathfind.Point{pathfind.Pt(5, 5), pathfind.Pt(20, 39), pathfind.Pt(22, 39), pathfind.Pt(40, 1), pathfind.Pt(42, 1), pathfind.Pt(55, 35)},
+			samples:  10,
+		},
+		{
+			name:     "straight segments only",
+			polygons: polygonS,
+			opts:     []pathfind.Option{pathfind.WithSplineTensionSteps(1)},
+			path:     []pathfind.Point{pathfind.Pt(5, 5), pathfind.Pt(20, 39), pathfind.Pt(22, 39), pathfind.Pt(40, 1), pathfind.Pt(42, 1), pathfind.Pt(55, 35)},
+			samples:  10,
+		},
+		{
+			name:     "single segment",
+			polygons: polygonU,
+			path:     []pathfind.Point{pathfind.Pt(5, 5), pathfind.Pt(5, 15)},
+			samples:  4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pathfinder := pathfind.NewPathfinder(tt.polygons, tt.opts...)
+			got := pathfinder.SmoothPath(tt.path, tt.samples)
+			if want := (len(tt.path)-1)*tt.samples + 1; len(got) != want {
+				t.Fatalf("SmoothPath returned %d points, want %d", len(got), want)
+			}
+			for i, pt := range tt.path {
+				if got[i*tt.samples] != pt {
+					t.Errorf("SmoothPath point %d = %v, want waypoint %v", i*tt.samples, got[i*tt.samples], pt)
+				}
+			}
+			for i := 1; i < len(got); i++ {
+				if seg := pathfinder.Path(got[i-1], got[i]); len(seg) != 2 {
+					t.Errorf("SmoothPath segment from %v to %v leaves the accessible area", got[i-1], got[i])
+				}
+			}
+		})
+	}
+	if got := pathfind.NewPathfinder(polygonU).SmoothPath([]pathfind.Point{pathfind.Pt(5, 5), pathfind.Pt(5, 15)}, 0); len(got) != 2 {
+		t.Errorf("SmoothPath with 0 samples = %v, want copy of path", got)
+	}
+
+	path := []pathfind.Point{pathfind.Pt(5, 5), pathfind.Pt(20, 39), pathfind.Pt(22, 39), pathfind.Pt(40, 1), pathfind.Pt(42, 1), pathfind.Pt(55, 35)}
+	want := pathfind.NewPathfinder(polygonS).SmoothPath(path, 10)
+	if got := pathfind.NewPathfinder(polygonS, pathfind.WithSmoothingIterations(1)).SmoothPath(path, 10); !reflect.DeepEqual(got, want) {
+		t.Errorf("SmoothPath with WithSmoothingIterations(1)\n got: %v\nwant: %v", got, want)
+	}
+	if got := pathfind.NewPathfinder(polygonS, pathfind.WithSplineTensionSteps(1)).SmoothPath(path, 10); reflect.DeepEqual(got, want) {
+		t.Errorf("SmoothPath with WithSplineTensionSteps(1) = %v, want straighter segments than with the default", got)
+	}
+}
+
+func TestPathfinderValidatePath(t *testing.T) {
+	tests := []struct {
+		name string
+		path []pathfind.Point
+		want bool
+	}{
+		{
+			name: "path found by Path",
+			path: pathfind.NewPathfinder(polygonU).Path(pathfind.Pt(5, 5), pathfind.Pt(25, 5)),
+			want: true,
+		},
+		{
+			name: "single point inside",
+			path: []pathfind.Point{pathfind.Pt(5, 5)},
+			want: true,
+		},
+		{
+			name: "along an outline",
+			path: []pathfind.Point{pathfind.Pt(0, 5), pathfind.Pt(0, 15)},
+			want: true,
+		},
+		{
+			name: "shortcut through a hole",
+			path: []pathfind.Point{pathfind.Pt(5, 5), pathfind.Pt(25, 5)},
+			want: false,
+		},
+		{
+			name: "point outside",
+			path: []pathfind.Point{pathfind.Pt(5, 5), pathfind.Pt(5, 15), pathfind.Pt(5, 25)},
+			want: false,
+		},
+		{
+			name: "NaN point",
+			path: []pathfind.Point{pathfind.Pt(5, 5), pathfind.Pt(math.NaN(), 15)},
+			want: false,
+		},
+		{
+			name: "empty path",
+			path: nil,
+			want: false,
+		},
+	}
+	pathfinder := pathfind.NewPathfinder(polygonU)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pathfinder.ValidatePath(tt.path); got != tt.want {
+				t.Errorf("ValidatePath(%v) = %t, want %t", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathfinderValidatePathClearance(t *testing.T) {
+	pathfinder := pathfind.NewPathfinder(polygonU, pathfind.WithClearance(1))
+	if path := pathfinder.Path(pathfind.Pt(5, 5), pathfind.Pt(25, 5)); !pathfinder.ValidatePath(path) {
+		t.Errorf("ValidatePath(%v) = false for path found by Path, want true", path)
+	}
+	grazing := []pathfind.Point{pathfind.Pt(5, 5), pathfind.Pt(10.5, 10.5), pathfind.Pt(19.5, 10.5), pathfind.Pt(25, 5)}
+	if pathfinder.ValidatePath(grazing) {
+		t.Errorf("ValidatePath(%v) = true for path closer than the clearance to the corners, want false", grazing)
+	}
+}
@@ -0,0 +1,547 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/fzipp/pathfind/internal/poly"
+)
+
+// svgFlatness is the maximum distance in SVG user units between a Bézier
+// curve and the line segments approximating it.
+const svgFlatness = 0.25
+
+// FromSVG reads the shapes of an SVG document, as authored for example with
+// Inkscape, and creates a Pathfinder from them.
+//
+// The following SVG features are supported:
+//   - <rect> elements (rounded corners are ignored),
+//   - <polygon> elements,
+//   - <path> elements with the commands M, L, H, V, C, S, Q, T and Z in
+//     absolute and relative form. Bézier curves are flattened into line
+//     segments. Elliptical arcs (A) are not supported and cause an error.
+//   - The transform attribute with matrix, translate, scale, rotate, skewX
+//     and skewY on shapes and groups.
+//   - The fill-rule property as attribute or in the style attribute,
+//     inherited from groups. The subpaths of a path with the nonzero rule
+//     (the SVG default) that do not change whether the area is filled are
+//     dropped; with the evenodd rule all subpaths are kept.
+//
+// Shapes inside <defs>, <clipPath>, <mask>, <pattern>, <marker> and <symbol>
+// elements are ignored, as are all other elements, colors and strokes.
+// Like all polygons of a Pathfinder, the resulting polygons from all shapes
+// are nested by the even-odd rule: polygons inside an area polygon are
+// holes, polygons inside a hole are areas again.
+func FromSVG(r io.Reader, opts ...Option) (*Pathfinder, error) {
+	polygons, err := decodeSVG(r)
+	if err != nil {
+		return nil, err
+	}
+	return NewPathfinder(polygons, opts...), nil
+}
+
+// svgState is the inherited state of an SVG element.
+type svgState struct {
+	m       affine
+	evenOdd bool
+	skip    bool
+}
+
+func decodeSVG(r io.Reader) ([][]Point, error) {
+	var polygons [][]Point
+	dec := xml.NewDecoder(r)
+	state := svgState{m: identity}
+	var stack []svgState
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not parse SVG: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			stack = append(stack, state)
+			switch t.Name.Local {
+			case "defs", "clipPath", "mask", "pattern", "marker", "symbol":
+				state.skip = true
+			}
+			if state.skip {
+				continue
+			}
+			if tr := svgAttr(t, "transform"); tr != "" {
+				m, err := parseTransform(tr)
+				if err != nil {
+					return nil, err
+				}
+				state.m = state.m.mul(m)
+			}
+			if rule := svgProperty(t, "fill-rule"); rule != "" {
+				state.evenOdd = rule == "evenodd"
+			}
+			rings, err := svgShape(t)
+			if err != nil {
+				return nil, fmt.Errorf("invalid SVG <%s> element: %w", t.Name.Local, err)
+			}
+			for i, ring := range rings {
+				for j, pt := range ring {
+					ring[j] = state.m.apply(pt)
+				}
+				rings[i] = openRing(ring)
+			}
+			if !state.evenOdd {
+				rings = nonzeroRings(rings)
+			}
+			for _, ring := range rings {
+				if len(ring) < 3 {
+					continue
+				}
+				if orientation(ring) < 0 {
+					slices.Reverse(ring)
+				}
+				polygons = append(polygons, ring)
+			}
+		case xml.EndElement:
+			if len(stack) > 0 {
+				state = stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+	return polygons, nil
+}
+
+// svgShape returns the rings of a shape element in its local coordinates.
+func svgShape(el xml.StartElement) ([][]Point, error) {
+	switch el.Name.Local {
+	case "rect":
+		var v [4]float64
+		for i, name := range []string{"x", "y", "width", "height"} {
+			s := svgAttr(el, name)
+			if s == "" {
+				continue
+			}
+			f, err := strconv.ParseFloat(strings.TrimSuffix(s, "px"), 64)
+			if err != nil {
+				return nil, fmt.Errorf("attribute %s: %w", name, err)
+			}
+			v[i] = f
+		}
+		x, y, w, h := v[0], v[1], v[2], v[3]
+		if w <= 0 || h <= 0 {
+			return nil, nil
+		}
+		return [][]Point{{Pt(x, y), Pt(x+w, y), Pt(x+w, y+h), Pt(x, y+h)}}, nil
+	case "polygon":
+		nums, err := parseNumbers(svgAttr(el, "points"))
+		if err != nil {
+			return nil, fmt.Errorf("attribute points: %w", err)
+		}
+		var ring []Point
+		for i := 0; i+1 < len(nums); i += 2 {
+			ring = append(ring, Pt(nums[i], nums[i+1]))
+		}
+		return [][]Point{ring}, nil
+	case "path":
+		rings, err := parsePathData(svgAttr(el, "d"))
+		if err != nil {
+			return nil, fmt.Errorf("attribute d: %w", err)
+		}
+		return rings, nil
+	}
+	return nil, nil
+}
+
+// svgAttr returns the value of the attribute with the given local name.
+func svgAttr(el xml.StartElement, name string) string {
+	for _, a := range el.Attr {
+		if a.Name.Local == name {
+			return strings.TrimSpace(a.Value)
+		}
+	}
+	return ""
+}
+
+// svgProperty returns the value of a presentation property, which can be
+// given in the style attribute or as an attribute of its own.
+func svgProperty(el xml.StartElement, name string) string {
+	for _, decl := range strings.Split(svgAttr(el, "style"), ";") {
+		k, v, ok := strings.Cut(decl, ":")
+		if ok && strings.TrimSpace(k) == name {
+			return strings.TrimSpace(v)
+		}
+	}
+	return svgAttr(el, name)
+}
+
+// nonzeroRings drops the rings that do not affect the filled area of a shape
+// under the nonzero fill rule, e.g. an inner ring with the same winding as
+// the outer ring. The remaining rings describe the filled area under the
+// even-odd rule, assuming that the rings do not intersect each other.
+func nonzeroRings(rings [][]Point) [][]Point {
+	var kept [][]Point
+	for i, ring := range rings {
+		if len(ring) < 3 {
+			continue
+		}
+		outside := 0
+		for j, other := range rings {
+			if i != j && len(other) >= 3 && ringContains(other, ring[0]) {
+				outside += orientation(other)
+			}
+		}
+		inside := outside + orientation(ring)
+		if (outside == 0) != (inside == 0) {
+			kept = append(kept, ring)
+		}
+	}
+	return kept
+}
+
+// ringContains reports whether pt lies inside ring by the even-odd rule.
+func ringContains(ring []Point, pt Point) bool {
+	return poly.Polygon(ps2vs(ring)).Contains(p2v(pt), false)
+}
+
+// orientation returns +1 for counterclockwise rings, for a y axis pointing
+// up, and -1 for clockwise rings. Rings without area count as
+// counterclockwise.
+func orientation(ring []Point) int {
+	return poly.Polygon(ps2vs(ring)).Orientation()
+}
+
+// affine is a 2D affine transformation matrix
+//
+//	| a c e |
+//	| b d f |
+//	| 0 0 1 |
+//
+// stored as [a b c d e f], as in the SVG matrix transform.
+type affine [6]float64
+
+var identity = affine{1, 0, 0, 1, 0, 0}
+
+// mul returns the transformation that applies n first and then m.
+func (m affine) mul(n affine) affine {
+	return affine{
+		m[0]*n[0] + m[2]*n[1],
+		m[1]*n[0] + m[3]*n[1],
+		m[0]*n[2] + m[2]*n[3],
+		m[1]*n[2] + m[3]*n[3],
+		m[0]*n[4] + m[2]*n[5] + m[4],
+		m[1]*n[4] + m[3]*n[5] + m[5],
+	}
+}
+
+func (m affine) apply(p Point) Point {
+	return Point{
+		X: m[0]*p.X + m[2]*p.Y + m[4],
+		Y: m[1]*p.X + m[3]*p.Y + m[5],
+	}
+}
+
+// parseTransform parses the value of an SVG transform attribute,
+// e.g. "translate(10,20) rotate(45)".
+func parseTransform(s string) (affine, error) {
+	m := identity
+	for s = strings.TrimSpace(s); s != ""; s = strings.TrimLeft(s, " \t\r\n,") {
+		open := strings.IndexByte(s, '(')
+		end := strings.IndexByte(s, ')')
+		if open < 0 || end < open {
+			return m, fmt.Errorf("invalid transform %q", s)
+		}
+		name := strings.TrimSpace(s[:open])
+		args, err := parseNumbers(s[open+1 : end])
+		if err != nil {
+			return m, fmt.Errorf("invalid transform %s: %w", name, err)
+		}
+		t, err := transformFunc(name, args)
+		if err != nil {
+			return m, err
+		}
+		m = m.mul(t)
+		s = s[end+1:]
+	}
+	return m, nil
+}
+
+func transformFunc(name string, args []float64) (affine, error) {
+	n := len(args)
+	switch {
+	case name == "matrix" && n == 6:
+		return affine(args), nil
+	case name == "translate" && (n == 1 || n == 2):
+		args = append(args, 0)
+		return affine{1, 0, 0, 1, args[0], args[1]}, nil
+	case name == "scale" && (n == 1 || n == 2):
+		args = append(args, args[0])
+		return affine{args[0], 0, 0, args[1], 0, 0}, nil
+	case name == "rotate" && (n == 1 || n == 3):
+		a := args[0] * math.Pi / 180
+		sin, cos := math.Sincos(a)
+		r := affine{cos, sin, -sin, cos, 0, 0}
+		if n == 3 {
+			cx, cy := args[1], args[2]
+			r = affine{1, 0, 0, 1, cx, cy}.mul(r).mul(affine{1, 0, 0, 1, -cx, -cy})
+		}
+		return r, nil
+	case name == "skewX" && n == 1:
+		return affine{1, 0, math.Tan(args[0] * math.Pi / 180), 1, 0, 0}, nil
+	case name == "skewY" && n == 1:
+		return affine{1, math.Tan(args[0] * math.Pi / 180), 0, 1, 0, 0}, nil
+	}
+	return identity, fmt.Errorf("unsupported transform %s with %d arguments", name, n)
+}
+
+// parseNumbers parses a list of numbers separated by whitespace and/or
+// commas, as used in SVG attributes.
+func parseNumbers(s string) ([]float64, error) {
+	sc := numberScanner{s: s}
+	var nums []float64
+	for sc.skipSeparators(); !sc.done(); sc.skipSeparators() {
+		f, err := sc.number()
+		if err != nil {
+			return nil, err
+		}
+		nums = append(nums, f)
+	}
+	return nums, nil
+}
+
+// parsePathData parses the d attribute of an SVG path element and returns
+// its subpaths as rings.
+func parsePathData(d string) ([][]Point, error) {
+	sc := numberScanner{s: d}
+	var (
+		rings     [][]Point
+		ring      []Point
+		cur, init Point
+		ctrl      Point // last control point for the S and T commands
+		cmd, last byte
+	)
+	flush := func() {
+		if len(ring) > 0 {
+			rings = append(rings, ring)
+		}
+		ring = nil
+	}
+	for sc.skipSeparators(); !sc.done(); sc.skipSeparators() {
+		if c := sc.s[sc.i]; isLetter(c) {
+			cmd = c
+			sc.i++
+		} else if cmd == 0 {
+			return nil, fmt.Errorf("path data must start with a command, found %q", c)
+		}
+		rel := cmd >= 'a'
+		origin := Point{}
+		if rel {
+			origin = cur
+		}
+		args := func(n int) ([]float64, error) {
+			vs := make([]float64, n)
+			for i := range vs {
+				sc.skipSeparators()
+				f, err := sc.number()
+				if err != nil {
+					return nil, fmt.Errorf("command %c: %w", cmd, err)
+				}
+				vs[i] = f
+			}
+			return vs, nil
+		}
+		point := func(x, y float64) Point {
+			return origin.Add(Pt(x, y))
+		}
+		var next Point
+		switch cmd {
+		case 'M', 'm':
+			a, err := args(2)
+			if err != nil {
+				return nil, err
+			}
+			flush()
+			next = point(a[0], a[1])
+			init = next
+			ring = []Point{next}
+			// Subsequent coordinate pairs are implicit lineto commands.
+			if cmd == 'M' {
+				cmd = 'L'
+			} else {
+				cmd = 'l'
+			}
+		case 'L', 'l':
+			a, err := args(2)
+			if err != nil {
+				return nil, err
+			}
+			next = point(a[0], a[1])
+			ring = append(ring, next)
+		case 'H', 'h':
+			a, err := args(1)
+			if err != nil {
+				return nil, err
+			}
+			next = Pt(origin.X+a[0], cur.Y)
+			ring = append(ring, next)
+		case 'V', 'v':
+			a, err := args(1)
+			if err != nil {
+				return nil, err
+			}
+			next = Pt(cur.X, origin.Y+a[0])
+			ring = append(ring, next)
+		case 'C', 'c', 'S', 's':
+			var c1 Point
+			n := 6
+			if cmd == 'S' || cmd == 's' {
+				n = 4
+				c1 = cur
+				if last == 'C' || last == 'c' || last == 'S' || last == 's' {
+					c1 = cur.Add(cur.Sub(ctrl))
+				}
+			}
+			a, err := args(n)
+			if err != nil {
+				return nil, err
+			}
+			if n == 6 {
+				c1 = point(a[0], a[1])
+				a = a[2:]
+			}
+			ctrl = point(a[0], a[1])
+			next = point(a[2], a[3])
+			ring = flattenCubic(ring, cur, c1, ctrl, next, 0)
+		case 'Q', 'q', 'T', 't':
+			var q Point
+			if cmd == 'T' || cmd == 't' {
+				q = cur
+				if last == 'Q' || last == 'q' || last == 'T' || last == 't' {
+					q = cur.Add(cur.Sub(ctrl))
+				}
+				a, err := args(2)
+				if err != nil {
+					return nil, err
+				}
+				next = point(a[0], a[1])
+			} else {
+				a, err := args(4)
+				if err != nil {
+					return nil, err
+				}
+				q = point(a[0], a[1])
+				next = point(a[2], a[3])
+			}
+			ctrl = q
+			// Elevate the quadratic curve to a cubic one.
+			c1 := lerp(cur, q, 2.0/3)
+			c2 := lerp(next, q, 2.0/3)
+			ring = flattenCubic(ring, cur, c1, c2, next, 0)
+		case 'Z', 'z':
+			flush()
+			next = init
+		case 'A', 'a':
+			return nil, errors.New("elliptical arc commands are not supported")
+		default:
+			return nil, fmt.Errorf("unknown command %q", cmd)
+		}
+		cur, last = next, cmd
+		if cmd == 'Z' || cmd == 'z' {
+			// A command letter must follow.
+			cmd = 0
+		}
+	}
+	flush()
+	return rings, nil
+}
+
+// flattenCubic appends the end points of line segments approximating the
+// cubic Bézier curve from p0 to p3 with control points p1 and p2 to ring.
+func flattenCubic(ring []Point, p0, p1, p2, p3 Point, depth int) []Point {
+	if depth >= 16 || (segmentPointDist(p0, p3, p1) <= svgFlatness && segmentPointDist(p0, p3, p2) <= svgFlatness) {
+		return append(ring, p3)
+	}
+	// Subdivide with de Casteljau's algorithm.
+	mid := func(a, b Point) Point { return lerp(a, b, 0.5) }
+	p01, p12, p23 := mid(p0, p1), mid(p1, p2), mid(p2, p3)
+	p012, p123 := mid(p01, p12), mid(p12, p23)
+	m := mid(p012, p123)
+	ring = flattenCubic(ring, p0, p01, p012, m, depth+1)
+	return flattenCubic(ring, m, p123, p23, p3, depth+1)
+}
+
+// lerp returns the linear interpolation between a and b by amount t.
+func lerp(a, b Point, t float64) Point {
+	return Point{X: a.X + (b.X-a.X)*t, Y: a.Y + (b.Y-a.Y)*t}
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// numberScanner scans numbers in SVG attribute values, where separators
+// between numbers are optional if the numbers are unambiguous, as in
+// "10-2.5.5" for 10, -2.5 and .5.
+type numberScanner struct {
+	s string
+	i int
+}
+
+func (sc *numberScanner) done() bool {
+	return sc.i >= len(sc.s)
+}
+
+func (sc *numberScanner) skipSeparators() {
+	for !sc.done() && strings.IndexByte(" \t\r\n,", sc.s[sc.i]) >= 0 {
+		sc.i++
+	}
+}
+
+func (sc *numberScanner) number() (float64, error) {
+	start := sc.i
+	if !sc.done() && (sc.s[sc.i] == '+' || sc.s[sc.i] == '-') {
+		sc.i++
+	}
+	digits := sc.digits()
+	if !sc.done() && sc.s[sc.i] == '.' {
+		sc.i++
+		digits += sc.digits()
+	}
+	if digits > 0 && !sc.done() && (sc.s[sc.i] == 'e' || sc.s[sc.i] == 'E') {
+		exp := sc.i
+		sc.i++
+		if !sc.done() && (sc.s[sc.i] == '+' || sc.s[sc.i] == '-') {
+			sc.i++
+		}
+		if sc.digits() == 0 {
+			sc.i = exp
+		}
+	}
+	if digits == 0 {
+		sc.i = start
+		if sc.done() {
+			return 0, errors.New("missing number")
+		}
+		return 0, fmt.Errorf("invalid number at %q", sc.s[start:])
+	}
+	return strconv.ParseFloat(sc.s[start:sc.i], 64)
+}
+
+func (sc *numberScanner) digits() int {
+	n := 0
+	for !sc.done() && sc.s[sc.i] >= '0' && sc.s[sc.i] <= '9' {
+		sc.i++
+		n++
+	}
+	return n
+}
@@ -0,0 +1,131 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+func TestFromSVG(t *testing.T) {
+	tests := []struct {
+		name  string
+		svg   string
+		start pathfind.Point
+		dest  pathfind.Point
+		want  []pathfind.Point
+	}{
+		{
+			name: "rect with polygon hole",
+			svg: `<svg xmlns="http://www.w3.org/2000/svg">
+				<rect x="0" y="0" width="40" height="40"/>
+				<polygon points="10,10 30,10 30,30 10,30"/>
+			</svg>`,
+			start: pathfind.Pt(5, 15),
+			dest:  pathfind.Pt(35, 15),
+			want:  []pathfind.Point{pathfind.Pt(5, 15), pathfind.Pt(10, 10), pathfind.Pt(30, 10), pathfind.Pt(35, 15)},
+		},
+		{
+			name: "path with even-odd hole and group transform",
+			svg: `<svg xmlns="http://www.w3.org/2000/svg">
+				<g transform="translate(100,0)">
+					<path fill-rule="evenodd" d="M0,0 H40 V40 H0 Z m10,10 h20 v20 h-20 z"/>
+				</g>
+			</svg>`,
+			start: pathfind.Pt(105, 15),
+			dest:  pathfind.Pt(135, 15),
+			want:  []pathfind.Point{pathfind.Pt(105, 15), pathfind.Pt(110, 10), pathfind.Pt(130, 10), pathfind.Pt(135, 15)},
+		},
+		{
+			name: "nonzero rule drops inner ring with same winding",
+			svg: `<svg xmlns="http://www.w3.org/2000/svg">
+				<path style="fill:#000;fill-rule:nonzero" d="M0,0 L40,0 L40,40 L0,40 Z M10,10 L30,10 L30,30 L10,30 Z"/>
+			</svg>`,
+			start: pathfind.Pt(5, 20),
+			dest:  pathfind.Pt(35, 20),
+			want:  []pathfind.Point{pathfind.Pt(5, 20), pathfind.Pt(35, 20)},
+		},
+		{
+			name: "nonzero rule keeps inner ring with opposite winding",
+			svg: `<svg xmlns="http://www.w3.org/2000/svg">
+				<path d="M0,0 L40,0 L40,40 L0,40 Z M10,10 L10,30 L30,30 L30,10 Z"/>
+			</svg>`,
+			start: pathfind.Pt(5, 15),
+			dest:  pathfind.Pt(35, 15),
+			want:  []pathfind.Point{pathfind.Pt(5, 15), pathfind.Pt(10, 10), pathfind.Pt(30, 10), pathfind.Pt(35, 15)},
+		},
+		{
+			name: "shapes in defs are ignored",
+			svg: `<svg xmlns="http://www.w3.org/2000/svg">
+				<defs><rect x="10" y="10" width="20" height="20"/></defs>
+				<rect width="40" height="40"/>
+			</svg>`,
+			start: pathfind.Pt(5, 20),
+			dest:  pathfind.Pt(35, 20),
+			want:  []pathfind.Point{pathfind.Pt(5, 20), pathfind.Pt(35, 20)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pathfinder, err := pathfind.FromSVG(strings.NewReader(tt.svg))
+			if err != nil {
+				t.Fatalf("FromSVG: unexpected error: %v", err)
+			}
+			got := pathfinder.Path(tt.start, tt.dest)
+			if !pathNearEq(got, tt.want) {
+				t.Errorf("Path(%v, %v)\n got: %v\nwant: %v", tt.start, tt.dest, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromSVGCurves(t *testing.T) {
+	// A circle of radius 50 made of four cubic Bézier curves around a
+	// square hole.
+	svg := `<svg xmlns="http://www.w3.org/2000/svg">
+		<path d="M100,50 C100,77.6 77.6,100 50,100 S0,77.6 0,50 S22.4,0 50,0 S100,22.4 100,50 Z"/>
+		<path d="M40,40 h20 v20 h-20 z"/>
+	</svg>`
+	pathfinder, err := pathfind.FromSVG(strings.NewReader(svg))
+	if err != nil {
+		t.Fatalf("FromSVG: unexpected error: %v", err)
+	}
+	start, dest := pathfind.Pt(50, 10), pathfind.Pt(50, 90)
+	region := pathfinder.ReachableRegion(start)
+	if len(region) != 2 {
+		t.Fatalf("got %d polygons, want 2", len(region))
+	}
+	if n := len(region[0]); n < 16 {
+		t.Errorf("circle flattened into %d vertices, want at least 16", n)
+	}
+	path := pathfinder.Path(start, dest)
+	if len(path) != 4 {
+		t.Errorf("Path(%v, %v) = %v, want a path around the hole", start, dest, path)
+	}
+}
+
+func TestFromSVGErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		svg  string
+	}{
+		{name: "malformed XML", svg: `<svg><rect></svg>`},
+		{name: "arc", svg: `<svg><path d="M0,0 A10,10 0 0 1 20,0 Z"/></svg>`},
+		{name: "missing command", svg: `<svg><path d="0,0 10,0 10,10"/></svg>`},
+		{name: "missing coordinate", svg: `<svg><path d="M0,0 L10"/></svg>`},
+		{name: "invalid points", svg: `<svg><polygon points="0,0 10,x 10,10"/></svg>`},
+		{name: "invalid transform", svg: `<svg><rect width="1" height="1" transform="spin(10)"/></svg>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := pathfind.FromSVG(strings.NewReader(tt.svg))
+			if err == nil {
+				t.Errorf("FromSVG(%q): expected error, got nil", tt.svg)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+)
+
+// svgSize is the width or height in pixels, whichever is larger, of the
+// SVG images written by WriteSVG.
+const svgSize = 800
+
+// WriteSVG writes an SVG image of the Pathfinder and the given path to w for
+// debugging. The image shows the area polygons in light gray, the holes in
+// dark gray, the walls as black lines, the weighted regions and preferred
+// areas as translucent overlays, the concave vertices of the visibility
+// graph as blue dots and the path as a red polyline. The view covers the
+// bounding rectangle of the polygons with a margin of 5% of its larger side.
+// The path can be nil, e.g. to inspect the visibility graph nodes alone;
+// parts of it outside of the view are cut off.
+//
+// The image uses the coordinates of the polygons as SVG user units, so the
+// Y axis points down, as in the documents read by FromSVG.
+func (p *Pathfinder) WriteSVG(w io.Writer, path []Point) error {
+	v := p.view()
+	x, y := v.min.X, v.min.Y
+	width, height := v.max.X-v.min.X, v.max.Y-v.min.Y
+	scale := svgSize / max(width, height)
+	dot := max(width, height) / 200
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="%s %s %s %s">`+"\n",
+		svgNum(width*scale), svgNum(height*scale), svgNum(x), svgNum(y), svgNum(width), svgNum(height))
+
+	order := p.drawOrder()
+	for _, i := range order {
+		if len(p.polygonSet[i]) == 0 {
+			continue
+		}
+		fill := "#e0e0e0"
+		if p.depths[i]%2 == 1 {
+			fill = "#606060"
+		}
+		writeSVGPolygon(bw, p.polygons[i], `fill="`+fill+`" stroke="#000000"`)
+	}
+	for _, w := range p.opts.wallPolylines {
+		if vs := w.vertices(); vs != nil {
+			fmt.Fprintf(bw, `<polyline points="%s" fill="none" stroke="#000000" vector-effect="non-scaling-stroke"/>`+"\n", svgPoints(vs))
+		}
+	}
+	for _, i := range order {
+		if len(p.polygonSet[i]) == 0 && len(p.polygons[i]) > 0 {
+			writeSVGPolygon(bw, p.polygons[i], `fill="#40a040" fill-opacity="0.4" stroke="#208020"`)
+		}
+	}
+	for _, v := range p.concaveVertices {
+		fmt.Fprintf(bw, `<circle cx="%s" cy="%s" r="%s" fill="#2060e0"/>`+"\n", svgNum(v.X), svgNum(v.Y), svgNum(dot))
+	}
+	if len(path) > 0 {
+		fmt.Fprintf(bw, `<polyline points="%s" fill="none" stroke="#e02020" stroke-width="2" vector-effect="non-scaling-stroke"/>`+"\n", svgPoints(path))
+	}
+	bw.WriteString("</svg>\n")
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("could not write SVG: %w", err)
+	}
+	return nil
+}
+
+// view returns the rectangle shown by WriteSVG and Render: the bounding
+// rectangle of the polygons with a margin of 5% of its larger side.
+func (p *Pathfinder) view() rect {
+	b := boundingRect(p.polygons)
+	if len(p.polygons) == 0 || b.min.X > b.max.X {
+		b = rect{}
+	}
+	margin := max(b.max.X-b.min.X, b.max.Y-b.min.Y, 1) * 0.05
+	return rect{
+		min: Point{X: b.min.X - margin, Y: b.min.Y - margin},
+		max: Point{X: b.max.X + margin, Y: b.max.Y + margin},
+	}
+}
+
+// drawOrder returns the polygon indices sorted from the outermost to the
+// innermost nesting level. Drawing the polygons in this order paints each
+// hole over its area and each island over its hole.
+func (p *Pathfinder) drawOrder() []int {
+	order := make([]int, len(p.polygons))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(i, j int) int {
+		return cmp.Compare(p.depths[i], p.depths[j])
+	})
+	return order
+}
+
+// writeSVGPolygon writes a polygon element with the given attributes.
+func writeSVGPolygon(bw *bufio.Writer, polygon []Point, attrs string) {
+	fmt.Fprintf(bw, `<polygon points="%s" %s vector-effect="non-scaling-stroke"/>`+"\n", svgPoints(openRing(polygon)), attrs)
+}
+
+// svgPoints formats points for the points attribute of an SVG element.
+func svgPoints(points []Point) string {
+	var buf []byte
+	for i, pt := range points {
+		if i > 0 {
+			buf = append(buf, ' ')
+		}
+		buf = strconv.AppendFloat(buf, pt.X, 'g', -1, 64)
+		buf = append(buf, ',')
+		buf = strconv.AppendFloat(buf, pt.Y, 'g', -1, 64)
+	}
+	return string(buf)
+}
+
+// svgNum formats a number for an SVG attribute.
+func svgNum(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,44 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+func TestPathfinderWriteSVG(t *testing.T) {
+	pathfinder := pathfind.NewPathfinder(polygonU)
+	path := []pathfind.Point{pathfind.Pt(5, 5), pathfind.Pt(10, 10), pathfind.Pt(20, 10), pathfind.Pt(25, 5)}
+	var buf bytes.Buffer
+	if err := pathfinder.WriteSVG(&buf, path); err != nil {
+		t.Fatalf("WriteSVG returned error: %v", err)
+	}
+	svg := buf.String()
+	for _, want := range []string{
+		`viewBox="-1.5 -1.5 33 23"`,
+		`<polyline points="5,5 10,10 20,10 25,5"`,
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("WriteSVG output does not contain %s:\n%s", want, svg)
+		}
+	}
+	if got := strings.Count(svg, "<circle"); got != 2 {
+		t.Errorf("WriteSVG output has %d vertex dots, want 2:\n%s", got, svg)
+	}
+
+	// The polygons of the image describe the same accessible area.
+	decoded, err := pathfind.FromSVG(strings.NewReader(svg))
+	if err != nil {
+		t.Fatalf("FromSVG of WriteSVG output returned error: %v", err)
+	}
+	if got, want := decoded.NavigableArea(), pathfinder.NavigableArea(); math.Abs(got-want) > 1e-9 {
+		t.Errorf("navigable area of WriteSVG output = %g, want %g", got, want)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import "context"
+
+// Tag returns the tag of the polygon with index i as given by the WithTags
+// option, or the empty string if the polygon has no tag.
+func (p *Pathfinder) Tag(i int) string {
+	if i < 0 || i >= len(p.opts.tags) {
+		return ""
+	}
+	return p.opts.tags[i]
+}
+
+// PathFiltered finds the shortest path from start to dest like Path, but
+// only within area polygons whose tag is accepted by the allowed function.
+// Area polygons with other tags are treated as obstacles for this query.
+// Tags are attached to polygons with the WithTags option.
+//
+// Since polygons are nested, each point belongs to exactly one area polygon
+// and a path never leaves the area polygon of its start point. Therefore
+// PathFiltered returns nil if start or the clamped dest lie in a disallowed
+// area, and otherwise the same path as Path. No graph has to be rebuilt.
+func (p *Pathfinder) PathFiltered(start, dest Point, allowed func(tag string) bool) []Point {
+	if !allFinite(start, dest) || !p.allowedAt(start, allowed) || !p.allowedAt(p.clampDest(start, dest), allowed) {
+		return nil
+	}
+	s := p.getScratch()
+	defer p.scratchPool.Put(s)
+	path, _, _ := p.findPath(context.Background(), start, dest, s)
+	return path
+}
+
+// allowedAt reports whether pt lies in an area polygon with an allowed tag.
+func (p *Pathfinder) allowedAt(pt Point, allowed func(tag string) bool) bool {
+	area, ok := p.regionOf(pt)
+	return ok && allowed(p.Tag(area))
+}
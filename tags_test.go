@@ -0,0 +1,52 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+func TestPathfinderPathFiltered(t *testing.T) {
+	pathfinder := pathfind.NewPathfinder(polygonIslands, pathfind.WithTags([]string{
+		"land", "", "water", "", "land",
+	}))
+	onLand := func(tag string) bool { return tag == "land" }
+	tests := []struct {
+		name        string
+		start, dest pathfind.Point
+		wantPath    bool
+	}{
+		{"within allowed area", pathfind.Pt(2, 2), pathfind.Pt(28, 38), true},
+		{"within disallowed area", pathfind.Pt(42, 2), pathfind.Pt(78, 38), false},
+		{"allowed island inside disallowed area", pathfind.Pt(55, 15), pathfind.Pt(65, 25), true},
+		{"dest clamped into allowed area", pathfind.Pt(2, 2), pathfind.Pt(-5, 20), true},
+		{"dest clamped into disallowed area", pathfind.Pt(42, 2), pathfind.Pt(85, 20), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pathfinder.PathFiltered(tt.start, tt.dest, onLand)
+			if (got != nil) != tt.wantPath {
+				t.Fatalf("PathFiltered(%v, %v) = %v, want path: %v", tt.start, tt.dest, got, tt.wantPath)
+			}
+			if want := pathfinder.Path(tt.start, tt.dest); got != nil && !pathNearEq(got, want) {
+				t.Errorf("PathFiltered(%v, %v)\n got: %v\nwant: %v", tt.start, tt.dest, got, want)
+			}
+		})
+	}
+}
+
+func TestPathfinderTag(t *testing.T) {
+	pathfinder := pathfind.NewPathfinder(polygonIslands, pathfind.WithTags([]string{"a", "b"}))
+	for i, want := range []string{"a", "b", "", "", "", ""} {
+		if got := pathfinder.Tag(i); got != want {
+			t.Errorf("Tag(%d) = %q, want %q", i, got, want)
+		}
+	}
+	if got := pathfinder.Tag(-1); got != "" {
+		t.Errorf("Tag(-1) = %q, want empty tag", got)
+	}
+}
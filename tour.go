@@ -0,0 +1,181 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import (
+	"math"
+	"slices"
+)
+
+// heldKarpMaxStops is the largest number of stops for which OptimalTour
+// determines the exact order.
+const heldKarpMaxStops = 10
+
+// OptimalTour finds a short route from start that visits all stops, e.g.
+// the deliveries of a courier, in the order that minimizes the total length
+// of the route. The route ends at the last stop visited and does not return
+// to start. Stops outside of the polygon set are clamped like the
+// destination of Path.
+//
+// The lengths of the paths between all pairs of points are determined on
+// the visibility graph. With weighted regions or WithMetric, the costs of
+// the paths as for Path take the place of their lengths. For up to 10
+// stops the optimal order is computed exactly with the Held-Karp
+// algorithm, whose cost grows exponentially with the number of stops. For
+// more stops the order is built by visiting the nearest unvisited stop
+// next and then improved with 2-opt moves, which reverse parts of the
+// order while this shortens the route. This order is usually close to,
+// but not guaranteed to be, the optimal one.
+//
+// OptimalTour returns nil if there are no stops or if a stop cannot be
+// reached from start.
+func (p *Pathfinder) OptimalTour(start Point, stops []Point) []Point {
+	if len(stops) == 0 || !allFinite(start) || !allFinite(stops...) {
+		return nil
+	}
+	level := containmentLevel(p.polygonSet, start)
+	nodes := make([]Point, 0, len(stops)+1)
+	nodes = append(nodes, start)
+	for _, s := range stops {
+		s = p.clamp(s)
+		if containmentLevel(p.polygonSet, s) != level {
+			return nil
+		}
+		nodes = append(nodes, s)
+	}
+	vis := copyGraph(p.cachedGraph)
+	for i, n := range nodes {
+		p.linkIntoGraph(vis, n, nodes[:i])
+	}
+	travelCost, _ := p.travelCost()
+	cost := make([][]float64, len(nodes))
+	prevs := make([]map[Point]Point, len(nodes))
+	for i, n := range nodes {
+		var dist map[Point]float64
+		dist, prevs[i] = dijkstra(vis, []Point{n}, travelCost, nil)
+		cost[i] = make([]float64, len(nodes))
+		for j, m := range nodes {
+			d, ok := dist[m]
+			if !ok {
+				return nil
+			}
+			cost[i][j] = d
+		}
+	}
+
+	var order []int
+	if len(stops) <= heldKarpMaxStops {
+		order = heldKarp(cost)
+	} else {
+		order = twoOpt(cost, nearestNeighbourTour(cost))
+	}
+	route := []Point{start}
+	for k := 1; k < len(order); k++ {
+		leg := tracePath(prevs[order[k-1]], nodes[order[k]])
+		offsetPath(p.polygonSet, leg, p.opts.margin)
+		route = append(route, leg[1:]...)
+	}
+	return route
+}
+
+// heldKarp returns the order of the nodes, starting with node 0 and
+// visiting all other nodes, that minimizes the sum of the costs between
+// consecutive nodes.
+func heldKarp(cost [][]float64) []int {
+	n := len(cost) - 1
+	full := 1<<n - 1
+	// best[mask][j] is the least cost of visiting the nodes in mask,
+	// starting at node 0 and ending at node j+1.
+	best := make([][]float64, full+1)
+	prev := make([][]int, full+1)
+	for mask := range best {
+		best[mask] = make([]float64, n)
+		prev[mask] = make([]int, n)
+		for j := range n {
+			best[mask][j] = math.Inf(1)
+		}
+	}
+	for j := range n {
+		best[1<<j][j] = cost[0][j+1]
+		prev[1<<j][j] = -1
+	}
+	for mask := 1; mask <= full; mask++ {
+		for j := range n {
+			if mask&(1<<j) == 0 || math.IsInf(best[mask][j], 1) {
+				continue
+			}
+			for k := range n {
+				if mask&(1<<k) != 0 {
+					continue
+				}
+				next := mask | 1<<k
+				if c := best[mask][j] + cost[j+1][k+1]; c < best[next][k] {
+					best[next][k] = c
+					prev[next][k] = j
+				}
+			}
+		}
+	}
+	last := 0
+	for j := range n {
+		if best[full][j] < best[full][last] {
+			last = j
+		}
+	}
+	order := make([]int, 0, n+1)
+	for mask, j := full, last; j >= 0; {
+		order = append(order, j+1)
+		mask, j = mask&^(1<<j), prev[mask][j]
+	}
+	order = append(order, 0)
+	slices.Reverse(order)
+	return order
+}
+
+// nearestNeighbourTour returns an order of the nodes that starts with node
+// 0 and always continues with the unvisited node of the least cost.
+func nearestNeighbourTour(cost [][]float64) []int {
+	visited := make([]bool, len(cost))
+	order := []int{0}
+	visited[0] = true
+	for len(order) < len(cost) {
+		cur, next := order[len(order)-1], -1
+		for j := range cost {
+			if !visited[j] && (next < 0 || cost[cur][j] < cost[cur][next]) {
+				next = j
+			}
+		}
+		visited[next] = true
+		order = append(order, next)
+	}
+	return order
+}
+
+// twoOpt improves the order of the nodes, which starts with the fixed node
+// 0 and has an open end, by reversing parts of it as long as this reduces
+// the sum of the costs between consecutive nodes. The costs must be
+// symmetric.
+func twoOpt(cost [][]float64, order []int) []int {
+	const eps = 1e-9
+	n := len(order)
+	for improved := true; improved; {
+		improved = false
+		for i := 1; i < n-1; i++ {
+			for k := i + 1; k < n; k++ {
+				// Reversing order[i:k+1] replaces the edges before i and
+				// after k, if any.
+				delta := cost[order[i-1]][order[k]] - cost[order[i-1]][order[i]]
+				if k+1 < n {
+					delta += cost[order[i]][order[k+1]] - cost[order[k]][order[k+1]]
+				}
+				if delta < -eps {
+					slices.Reverse(order[i : k+1])
+					improved = true
+				}
+			}
+		}
+	}
+	return order
+}
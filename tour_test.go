@@ -0,0 +1,77 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+func TestPathfinderOptimalTour(t *testing.T) {
+	pathfinder := pathfind.NewPathfinder(polygonU)
+	tests := []struct {
+		name  string
+		start pathfind.Point
+		stops []pathfind.Point
+		want  []pathfind.Point
+	}{
+		{
+			name:  "exact order",
+			start: pathfind.Pt(5, 5),
+			stops: []pathfind.Point{pathfind.Pt(25, 5), pathfind.Pt(25, 15), pathfind.Pt(5, 15)},
+			want:  []pathfind.Point{pathfind.Pt(5, 5), pathfind.Pt(5, 15), pathfind.Pt(25, 15), pathfind.Pt(25, 5)},
+		},
+		{
+			name:  "around corners",
+			start: pathfind.Pt(25, 5),
+			stops: []pathfind.Point{pathfind.Pt(5, 5), pathfind.Pt(25, 15)},
+			want:  []pathfind.Point{pathfind.Pt(25, 5), pathfind.Pt(25, 15), pathfind.Pt(10, 10), pathfind.Pt(5, 5)},
+		},
+		{
+			name:  "start outside",
+			start: pathfind.Pt(15, 5),
+			stops: []pathfind.Point{pathfind.Pt(25, 5), pathfind.Pt(5, 5)},
+			want:  nil,
+		},
+		{
+			name:  "no stops",
+			start: pathfind.Pt(5, 5),
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pathfinder.OptimalTour(tt.start, tt.stops)
+			if !pathNearEq(got, tt.want) {
+				t.Errorf("OptimalTour(%v, %v)\n got: %v\nwant: %v", tt.start, tt.stops, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathfinderOptimalTourHeuristic(t *testing.T) {
+	// Stops on a circle in random order: the shortest route goes around.
+	const n, r = 14, 15.0
+	center := pathfind.Pt(20, 20)
+	stops := make([]pathfind.Point, n)
+	for i := range stops {
+		stops[i] = pathfind.Pt(center.X, center.Y-r).Rotate(center, 2*math.Pi*float64(i)/n)
+	}
+	rng := rand.New(rand.NewPCG(1, 2))
+	rng.Shuffle(len(stops), func(i, j int) { stops[i], stops[j] = stops[j], stops[i] })
+	start := pathfind.Pt(20, 3)
+
+	route := pathfind.NewPathfinder(polygonSquare).OptimalTour(start, stops)
+	if len(route) != n+1 {
+		t.Fatalf("OptimalTour returned %d points, want %d: %v", len(route), n+1, route)
+	}
+	want := 2 + (n-1)*2*r*math.Sin(math.Pi/n)
+	if got := pathLength(route); math.Abs(got-want) > 1e-9 {
+		t.Errorf("OptimalTour route length = %g, want %g: %v", got, want, route)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import (
+	"math"
+	"slices"
+)
+
+// Transform returns a new polygon set with the transformation function f
+// applied to each vertex of polygons, e.g. to translate, scale or rotate
+// a map or an obstacle template before passing it to NewPathfinder.
+// The polygons passed in are not modified.
+func Transform(polygons [][]Point, f func(Point) Point) [][]Point {
+	return convert(polygons, func(ps []Point) []Point {
+		return convert(ps, f)
+	})
+}
+
+// NormalizeWinding returns a copy of polygons in which the vertices of the
+// area polygons run counterclockwise and those of the holes clockwise, for
+// a y axis pointing up as in GeoJSON; for a y axis pointing down, e.g. in
+// screen coordinates, the directions are swapped. Areas and holes are told
+// apart by the nesting rule of NewPathfinder. The Pathfinder itself accepts
+// polygons of any winding, but other tools may expect a consistent one.
+// Closed rings stay closed, and polygons without area, e.g. walls, are
+// copied unchanged. The polygons passed in are not modified.
+func NormalizeWinding(polygons [][]Point) [][]Point {
+	ps := convert(polygons, toPolygon)
+	_, depths := polygonNesting(ps)
+	res := make([][]Point, len(polygons))
+	for i, ring := range polygons {
+		ring = slices.Clone(ring)
+		if len(ps[i]) >= 3 {
+			want := +1
+			if depths[i]%2 == 1 {
+				want = -1
+			}
+			if ps[i].Orientation() != want {
+				slices.Reverse(ring)
+			}
+		}
+		res[i] = ring
+	}
+	return res
+}
+
+// Densify returns a new polygon set in which each edge of polygons that is
+// longer than maxEdge is subdivided into equal segments no longer than
+// maxEdge by adding collinear vertices. This is useful for boundaries that
+// approximate curves with long straight edges, so that the polygon outline
+// can follow the intended shape more closely. The polygons passed in are
+// not modified, and closed rings stay closed. If maxEdge is not positive,
+// the polygons are copied unchanged.
+//
+// The added vertices of area polygons are never turning points of a path,
+// but those of holes are, so densifying holes enlarges the visibility graph
+// and increases the cost of NewPathfinder.
+func Densify(polygons [][]Point, maxEdge float64) [][]Point {
+	return convert(polygons, func(ps []Point) []Point {
+		closed := len(ps) > 1 && isClosedRing(ps)
+		ring := openRing(ps)
+		if maxEdge <= 0 || len(ring) < 2 {
+			return slices.Clone(ps)
+		}
+		edges := len(ring)
+		if edges == 2 {
+			// A wall has only one edge, not a way back.
+			edges = 1
+		}
+		var res []Point
+		for i := range edges {
+			a, b := ring[i], ring[(i+1)%len(ring)]
+			n := max(1, math.Ceil(nodeDist(a, b)/maxEdge))
+			for k := 0.0; k < n; k++ {
+				res = append(res, lerp(a, b, k/n))
+			}
+		}
+		if edges == 1 {
+			res = append(res, ring[1])
+		}
+		if closed {
+			res = append(res, res[0])
+		}
+		return res
+	})
+}
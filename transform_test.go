@@ -0,0 +1,143 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"math"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+func TestTransform(t *testing.T) {
+	square := [][]pathfind.Point{
+		{pathfind.Pt(0, 0), pathfind.Pt(2, 0), pathfind.Pt(2, 2), pathfind.Pt(0, 2)},
+	}
+	tests := []struct {
+		name string
+		f    func(pathfind.Point) pathfind.Point
+		want [][]pathfind.Point
+	}{
+		{
+			name: "translate",
+			f: func(p pathfind.Point) pathfind.Point {
+				return p.Add(pathfind.Pt(10, 5))
+			},
+			want: [][]pathfind.Point{
+				{pathfind.Pt(10, 5), pathfind.Pt(12, 5), pathfind.Pt(12, 7), pathfind.Pt(10, 7)},
+			},
+		},
+		{
+			name: "rotate",
+			f: func(p pathfind.Point) pathfind.Point {
+				return p.Rotate(pathfind.Pt(1, 1), math.Pi)
+			},
+			want: [][]pathfind.Point{
+				{pathfind.Pt(2, 2), pathfind.Pt(0, 2), pathfind.Pt(0, 0), pathfind.Pt(2, 0)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pathfind.Transform(square, tt.f)
+			if len(got) != len(tt.want) || !pathNearEq(got[0], tt.want[0]) {
+				t.Errorf("Transform(%v)\n got: %v\nwant: %v", square, got, tt.want)
+			}
+		})
+	}
+	if want := pathfind.Pt(0, 0); square[0][0] != want {
+		t.Errorf("Transform modified its input: %v", square)
+	}
+}
+
+func TestDensify(t *testing.T) {
+	tests := []struct {
+		name     string
+		polygons [][]pathfind.Point
+		maxEdge  float64
+		want     [][]pathfind.Point
+	}{
+		{
+			name: "open ring",
+			polygons: [][]pathfind.Point{
+				{pathfind.Pt(0, 0), pathfind.Pt(4, 0), pathfind.Pt(4, 2)},
+			},
+			maxEdge: 2,
+			want: [][]pathfind.Point{
+				{pathfind.Pt(0, 0), pathfind.Pt(2, 0), pathfind.Pt(4, 0), pathfind.Pt(4, 2), pathfind.Pt(8.0/3, 4.0/3), pathfind.Pt(4.0/3, 2.0/3)},
+			},
+		},
+		{
+			name: "closed ring",
+			polygons: [][]pathfind.Point{
+				{pathfind.Pt(0, 0), pathfind.Pt(3, 0), pathfind.Pt(0, 1), pathfind.Pt(0, 0)},
+			},
+			maxEdge: 1.6,
+			want: [][]pathfind.Point{
+				{pathfind.Pt(0, 0), pathfind.Pt(1.5, 0), pathfind.Pt(3, 0), pathfind.Pt(1.5, 0.5), pathfind.Pt(0, 1), pathfind.Pt(0, 0)},
+			},
+		},
+		{
+			name: "wall",
+			polygons: [][]pathfind.Point{
+				{pathfind.Pt(0, 0), pathfind.Pt(0, 3)},
+			},
+			maxEdge: 1,
+			want: [][]pathfind.Point{
+				{pathfind.Pt(0, 0), pathfind.Pt(0, 1), pathfind.Pt(0, 2), pathfind.Pt(0, 3)},
+			},
+		},
+		{
+			name: "no limit",
+			polygons: [][]pathfind.Point{
+				{pathfind.Pt(0, 0), pathfind.Pt(4, 0), pathfind.Pt(4, 2)},
+			},
+			maxEdge: 0,
+			want: [][]pathfind.Point{
+				{pathfind.Pt(0, 0), pathfind.Pt(4, 0), pathfind.Pt(4, 2)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pathfind.Densify(tt.polygons, tt.maxEdge)
+			if len(got) != len(tt.want) || !pathNearEq(got[0], tt.want[0]) {
+				t.Errorf("Densify(%v, %g)\n got: %v\nwant: %v", tt.polygons, tt.maxEdge, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeWinding(t *testing.T) {
+	polygons := [][]pathfind.Point{
+		// Clockwise area, for a y axis pointing up, as a closed ring.
+		{pathfind.Pt(0, 0), pathfind.Pt(0, 40), pathfind.Pt(40, 40), pathfind.Pt(40, 0), pathfind.Pt(0, 0)},
+		// Counterclockwise hole.
+		{pathfind.Pt(20, 10), pathfind.Pt(30, 20), pathfind.Pt(20, 30), pathfind.Pt(10, 20)},
+		// Counterclockwise island inside the hole.
+		{pathfind.Pt(19, 19), pathfind.Pt(21, 19), pathfind.Pt(21, 21), pathfind.Pt(19, 21)},
+		// Wall.
+		{pathfind.Pt(5, 5), pathfind.Pt(5, 15)},
+	}
+	orig := slices.Clone(polygons[0])
+	want := [][]pathfind.Point{
+		{pathfind.Pt(0, 0), pathfind.Pt(40, 0), pathfind.Pt(40, 40), pathfind.Pt(0, 40), pathfind.Pt(0, 0)},
+		{pathfind.Pt(10, 20), pathfind.Pt(20, 30), pathfind.Pt(30, 20), pathfind.Pt(20, 10)},
+		{pathfind.Pt(19, 19), pathfind.Pt(21, 19), pathfind.Pt(21, 21), pathfind.Pt(19, 21)},
+		{pathfind.Pt(5, 5), pathfind.Pt(5, 15)},
+	}
+	got := pathfind.NormalizeWinding(polygons)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NormalizeWinding(%v)\n got: %v\nwant: %v", polygons, got, want)
+	}
+	if !reflect.DeepEqual(polygons[0], orig) {
+		t.Errorf("NormalizeWinding modified its argument: %v", polygons[0])
+	}
+	if again := pathfind.NormalizeWinding(got); !reflect.DeepEqual(again, want) {
+		t.Errorf("NormalizeWinding of normalized polygons\n got: %v\nwant: %v", again, want)
+	}
+}
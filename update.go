@@ -0,0 +1,459 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import (
+	"math"
+	"slices"
+
+	"github.com/fzipp/pathfind/internal/poly"
+)
+
+// AddPolygon adds a polygon to the polygon set of the Pathfinder, e.g. an
+// obstacle that is spawned during play. The new polygon gets the next
+// polygon index and is nested like the polygons passed to NewPathfinder:
+// a polygon added inside an area polygon is a hole. With the option
+// WithInvertedNesting, the frame around the polygons remains the last
+// polygon: the new polygon takes its index, and the frame is moved behind
+// it and recomputed if the new polygon extends the bounding rectangle of
+// the polygons.
+//
+// Only the polygons affected by the new polygon are reclassified: the new
+// polygon itself and the polygons inside of it, which change from area to
+// hole or vice versa. Likewise, only the edges of the visibility graph
+// near the new polygon are re-evaluated, unless the frame changes. The
+// result is the same as creating a new Pathfinder with the extended polygon
+// set.
+//
+// AddPolygon must not be called concurrently with other methods of the
+// Pathfinder.
+func (p *Pathfinder) AddPolygon(ps []Point) {
+	ps = dedupeVertices(ps)
+	if p.opts.autoClose {
+		ps = closeRing(ps)
+	}
+	k := len(p.polygons)
+	if p.opts.invertedNesting {
+		k--
+	}
+	box := boundingRect([][]Point{ps})
+	p.polygons = slices.Insert(slices.Clip(p.polygons), k, ps)
+	p.polygonSet = slices.Insert(p.polygonSet, k, toPolygon(ps))
+	p.boxes = slices.Insert(p.boxes, k, box)
+	if p.regions != nil {
+		p.regions = slices.Insert(p.regions, k, nil)
+		p.weights = slices.Insert(p.weights, k, 0)
+	}
+	p.concaveOf = slices.Insert(p.concaveOf, k, nil)
+	framed := p.opts.invertedNesting && p.updateFrame()
+	p.concaveOf[k] = turningPoints(p.polygons, p.polygonSet, k, p.opts)
+	p.reclassifyInside(p.polygonSet[k], len(p.polygonSet))
+	if framed {
+		p.updateGraph(nil)
+		return
+	}
+	p.nestAdded(k)
+	p.updateGraph(&box)
+}
+
+// RemovePolygon removes the polygon with the given index from the polygon
+// set of the Pathfinder, e.g. an obstacle that is despawned during play.
+// The indices of the polygons after it, and of their tags and weights,
+// decrease by one.
+// Invalid indices are ignored, as is the index of the frame added by
+// WithInvertedNesting, which is recomputed if the removal shrinks the
+// bounding rectangle of the polygons.
+//
+// As with AddPolygon, only the polygons inside of the removed polygon are
+// reclassified and only the edges of the visibility graph near it are
+// re-evaluated, unless the frame changes. The result is the same as
+// creating a new Pathfinder with the reduced polygon set.
+//
+// RemovePolygon must not be called concurrently with other methods of the
+// Pathfinder.
+func (p *Pathfinder) RemovePolygon(index int) {
+	n := len(p.polygons)
+	if p.opts.invertedNesting {
+		n--
+	}
+	if index < 0 || index >= n {
+		return
+	}
+	removed := p.polygonSet[index]
+	box := p.boxes[index]
+	p.nestRemoved(index)
+	p.polygons = slices.Delete(slices.Clone(p.polygons), index, index+1)
+	p.polygonSet = slices.Delete(p.polygonSet, index, index+1)
+	p.concaveOf = slices.Delete(p.concaveOf, index, index+1)
+	p.boxes = slices.Delete(p.boxes, index, index+1)
+	if p.regions != nil {
+		p.regions = slices.Delete(p.regions, index, index+1)
+		p.weights = slices.Delete(p.weights, index, index+1)
+	}
+	if index < len(p.opts.tags) {
+		p.opts.tags = slices.Delete(slices.Clone(p.opts.tags), index, index+1)
+	}
+	if len(p.opts.regionWeights) > 0 {
+		weights := make(map[int]float64, len(p.opts.regionWeights))
+		for i, w := range p.opts.regionWeights {
+			switch {
+			case i < index:
+				weights[i] = w
+			case i > index:
+				weights[i-1] = w
+			}
+		}
+		p.opts.regionWeights = weights
+	}
+	p.reclassifyInside(removed, len(p.polygonSet))
+	if p.opts.invertedNesting && p.updateFrame() {
+		p.updateGraph(nil)
+		return
+	}
+	p.updateGraph(&box)
+}
+
+// updateFrame recomputes the frame that WithInvertedNesting adds as the
+// last polygon around the other polygons and reports whether it changed.
+// The polygons inside of the frame keep their nesting, but the edges of the
+// visibility graph near the old frame are affected.
+func (p *Pathfinder) updateFrame() bool {
+	last := len(p.polygons) - 1
+	frame := outerFrame(boundingRect(p.polygons[:last]))
+	if slices.Equal(frame, p.polygons[last]) {
+		return false
+	}
+	p.polygons[last] = frame
+	p.polygonSet[last] = toPolygon(frame)
+	p.boxes[last] = boundingRect([][]Point{frame})
+	p.concaveOf[last] = turningPoints(p.polygons, p.polygonSet, last, p.opts)
+	return true
+}
+
+// reclassifyInside recomputes the turning points of the first n polygons
+// whose first vertex lies inside of the polygon changed, which has been added
+// or removed, because they change from area to hole or vice versa.
+func (p *Pathfinder) reclassifyInside(changed poly.Polygon, n int) {
+	if len(changed) == 0 {
+		return
+	}
+	for i, q := range p.polygonSet[:n] {
+		if len(q) > 0 && changed.Contains(q[0], false) {
+			p.concaveOf[i] = turningPoints(p.polygons, p.polygonSet, i, p.opts)
+		}
+	}
+}
+
+// nestAdded updates the nesting of the polygons for the polygon that was
+// inserted into the polygon set at index k. Only the polygons inside of it
+// change: they move one level deeper, and the new polygon becomes the
+// parent of those that were its siblings.
+func (p *Pathfinder) nestAdded(k int) {
+	for i, parent := range p.parents {
+		if parent >= k {
+			p.parents[i] = parent + 1
+		}
+	}
+	p.parents = slices.Insert(p.parents, k, -1)
+	p.depths = slices.Insert(p.depths, k, 0)
+	added := p.polygonSet[k]
+	if len(added) == 0 {
+		return
+	}
+	parent, depth := -1, 0
+	for j, q := range p.polygonSet {
+		if j != k && q.Contains(added[0], false) {
+			depth++
+		}
+	}
+	for j, q := range p.polygonSet {
+		if j != k && p.depths[j] == depth-1 && q.Contains(added[0], false) {
+			parent = j
+			break
+		}
+	}
+	for i, q := range p.polygonSet {
+		if i != k && len(q) > 0 && added.Contains(q[0], false) {
+			if p.parents[i] == parent {
+				p.parents[i] = k
+			}
+			p.depths[i]++
+		}
+	}
+	p.parents[k], p.depths[k] = parent, depth
+}
+
+// nestRemoved updates the nesting of the polygons for the removal of the
+// polygon with the given index, before it is removed from the polygon set.
+// The polygons inside of it move one level up, and its children become
+// children of its parent.
+func (p *Pathfinder) nestRemoved(index int) {
+	removed := p.polygonSet[index]
+	for i, q := range p.polygonSet {
+		if i != index && len(q) > 0 && len(removed) > 0 && removed.Contains(q[0], false) {
+			if p.parents[i] == index {
+				p.parents[i] = p.parents[index]
+			}
+			p.depths[i]--
+		}
+	}
+	p.parents = slices.Delete(p.parents, index, index+1)
+	p.depths = slices.Delete(p.depths, index, index+1)
+	for i, parent := range p.parents {
+		if parent > index {
+			p.parents[i] = parent - 1
+		}
+	}
+}
+
+// updateGraph derives the concave vertices, the cached visibility graph
+// and the spatial index from the polygon set and the concave vertices of
+// each polygon. If changed is nil, it derives the nesting of the polygons
+// as well. Otherwise, only the polygons within the rectangle changed were
+// modified since the last update, their nesting has been updated with
+// nestAdded or nestRemoved, and only the edges of the visibility graph
+// whose line segments meet the rectangle are re-evaluated.
+func (p *Pathfinder) updateGraph(changed *rect) {
+	oldVertices, oldGraph, edited := p.concaveVertices, p.cachedGraph, p.edited
+	if changed == nil {
+		p.parents, p.depths = polygonNesting(p.polygonSet)
+	}
+	var concave []Point
+	if p.opts.pruneUnreachable {
+		concave = p.reachableVertices()
+	} else {
+		concave = slices.Concat(p.concaveOf...)
+	}
+	concave = append(concave, p.wallTurningPoints()...)
+	if p.opts.clearance > 0 {
+		p.walls = p.edges()
+		concave = slices.DeleteFunc(concave, func(v Point) bool {
+			return !strictlyInside(p.polygonSet, v) || !p.hasClearance(v, v)
+		})
+	}
+	p.concaveVertices = concave
+	if changed == nil || edited {
+		// The index of an edited graph contains the linked nodes.
+		p.index = buildIndex(p.polygons, concave, p.opts)
+	} else {
+		p.updateIndex(oldVertices)
+	}
+	if changed == nil || edited || p.opts.clearance > 0 {
+		// With a clearance, any edge can be affected by the walls of
+		// a changed polygon. Edges of an edited graph cannot be reused.
+		p.cachedGraph = visibilityGraph(p.polygonSet, p.wallRings, concave)
+		p.edited = false
+	} else {
+		p.cachedGraph = updatedVisibilityGraph(p.polygonSet, p.wallRings, p.index, concave, oldVertices, oldGraph, *changed)
+	}
+	if p.opts.clearance > 0 {
+		for a, adj := range p.cachedGraph {
+			p.cachedGraph[a] = slices.DeleteFunc(adj, func(b Point) bool {
+				return !p.hasClearance(a, b)
+			})
+		}
+	}
+	sortAdjacency(p.cachedGraph)
+	p.components = nil
+	p.generation++
+}
+
+// updateIndex updates the spatial index, which contains the old vertices,
+// to contain the concave vertices instead. Only the vertices that differ
+// are removed and inserted. If a vertex cannot be inserted, because it lies
+// outside of the bounds of a quad tree, the index is rebuilt.
+func (p *Pathfinder) updateIndex(oldVertices []Point) {
+	counts := make(map[Point]int)
+	for _, v := range oldVertices {
+		counts[v]--
+	}
+	for _, v := range p.concaveVertices {
+		counts[v]++
+	}
+	for v, n := range counts {
+		for ; n < 0; n++ {
+			p.index.remove(v)
+		}
+		for ; n > 0; n-- {
+			if !p.index.insert(v) {
+				p.index = buildIndex(p.polygons, p.concaveVertices, p.opts)
+				return
+			}
+		}
+	}
+}
+
+// updatedVisibilityGraph is like visibilityGraph, but takes the edges
+// between the old vertices from the old graph, unless their line segments
+// meet the rectangle changed, which contains the polygons that were
+// modified since the old graph was built. The line of sight between two
+// points can only change if their line segment meets this rectangle. The
+// pairs of old vertices to re-evaluate are looked up with the spatial
+// index of the points, so only the vertices in the shadow of the rectangle
+// are visited.
+func updatedVisibilityGraph(ps, walls poly.PolygonSet, index spatialIndex, points, oldPoints []Point, old graph[Point], changed rect) graph[Point] {
+	// The rectangle is padded to be safe from the tolerances of the
+	// line of sight test.
+	const eps = 1e-4
+	changed = rect{
+		min: Point{X: changed.min.X - eps, Y: changed.min.Y - eps},
+		max: Point{X: changed.max.X + eps, Y: changed.max.Y + eps},
+	}
+	wasVertex := make(map[Point]bool, len(oldPoints))
+	for _, pt := range oldPoints {
+		wasVertex[pt] = true
+	}
+	isVertex := make(map[Point]bool, len(points))
+	for _, pt := range points {
+		isVertex[pt] = true
+	}
+	boxes := polygonBoxes(ps)
+	visible := func(a, b Point) bool {
+		return inLineOfSightBoxed(ps, boxes, p2v(a), p2v(b)) && !crossesWall(walls, p2v(a), p2v(b))
+	}
+
+	vis := make(graph[Point])
+	for a, adj := range old {
+		if !isVertex[a] {
+			continue
+		}
+		for _, b := range adj {
+			if isVertex[b] && !segmentMeetsRect(a, b, changed) {
+				vis.link(a, b)
+			}
+		}
+	}
+	bounds := boundingRect([][]Point{points})
+	var found []Point
+	for _, a := range points {
+		if !wasVertex[a] {
+			// A new vertex is evaluated with all others, in both
+			// directions unless the other one is new as well.
+			for _, b := range points {
+				if b == a {
+					continue
+				}
+				if visible(a, b) {
+					vis.link(a, b)
+				}
+				if wasVertex[b] && visible(b, a) {
+					vis.link(b, a)
+				}
+			}
+			continue
+		}
+		found = found[:0]
+		index.query(shadowRect(a, changed, bounds), &found)
+		for _, b := range found {
+			if b != a && wasVertex[b] && segmentMeetsRect(a, b, changed) && visible(a, b) {
+				vis.link(a, b)
+			}
+		}
+	}
+	return vis
+}
+
+// shadowRect returns a rectangle within bounds that contains all points b
+// within bounds for which the line segment from a to b meets the rectangle
+// r: the bounding rectangle of r and of the part of bounds that r hides
+// from a.
+func shadowRect(a Point, r, bounds rect) rect {
+	if r.contains(a) {
+		return bounds
+	}
+	corners := []Point{r.min, {X: r.max.X, Y: r.min.Y}, r.max, {X: r.min.X, Y: r.max.Y}}
+	// The extreme corners of r as seen from a span the shadow.
+	var left, right Point
+	spanned := false
+	for _, c := range corners {
+		for _, d := range corners {
+			if cross(c.Sub(a), d.Sub(a)) < 0 {
+				continue
+			}
+			extreme := true
+			for _, e := range corners {
+				if cross(c.Sub(a), e.Sub(a)) < 0 || cross(e.Sub(a), d.Sub(a)) < 0 {
+					extreme = false
+					break
+				}
+			}
+			if extreme {
+				right, left, spanned = c, d, true
+			}
+		}
+	}
+	if !spanned {
+		return bounds
+	}
+	s := r
+	extend := func(pt Point) {
+		s.min = Point{X: min(s.min.X, pt.X), Y: min(s.min.Y, pt.Y)}
+		s.max = Point{X: max(s.max.X, pt.X), Y: max(s.max.Y, pt.Y)}
+	}
+	for _, c := range corners {
+		if exit, ok := rayExit(a, c.Sub(a), bounds); ok {
+			extend(exit)
+		}
+	}
+	for _, q := range []Point{bounds.min, {X: bounds.max.X, Y: bounds.min.Y}, bounds.max, {X: bounds.min.X, Y: bounds.max.Y}} {
+		if cross(right.Sub(a), q.Sub(a)) >= 0 && cross(q.Sub(a), left.Sub(a)) >= 0 {
+			extend(q)
+		}
+	}
+	return rect{
+		min: Point{X: max(s.min.X, bounds.min.X), Y: max(s.min.Y, bounds.min.Y)},
+		max: Point{X: min(s.max.X, bounds.max.X), Y: min(s.max.Y, bounds.max.Y)},
+	}
+}
+
+// rayExit returns the point where the ray from o in direction d leaves the
+// rectangle r. The result is false if the ray does not leave r ahead of o.
+func rayExit(o, d Point, r rect) (Point, bool) {
+	t := math.Inf(1)
+	for _, axis := range [][3]float64{
+		{d.X, r.min.X - o.X, r.max.X - o.X},
+		{d.Y, r.min.Y - o.Y, r.max.Y - o.Y},
+	} {
+		switch {
+		case axis[0] > 0:
+			t = min(t, axis[2]/axis[0])
+		case axis[0] < 0:
+			t = min(t, axis[1]/axis[0])
+		}
+	}
+	if math.IsInf(t, 1) || t < 0 {
+		return Point{}, false
+	}
+	return o.Add(d.Mul(t)), true
+}
+
+// segmentMeetsRect reports whether the line segment from a to b has a point
+// in common with the rectangle r.
+func segmentMeetsRect(a, b Point, r rect) bool {
+	// Clip the segment against the slabs of the rectangle.
+	d := b.Sub(a)
+	lo, hi := 0.0, 1.0
+	for _, axis := range [][4]float64{
+		{a.X, d.X, r.min.X, r.max.X},
+		{a.Y, d.Y, r.min.Y, r.max.Y},
+	} {
+		o, dir, rmin, rmax := axis[0], axis[1], axis[2], axis[3]
+		if dir == 0 {
+			if o < rmin || o > rmax {
+				return false
+			}
+			continue
+		}
+		t0, t1 := (rmin-o)/dir, (rmax-o)/dir
+		if t0 > t1 {
+			t0, t1 = t1, t0
+		}
+		lo, hi = max(lo, t0), min(hi, t1)
+		if lo > hi {
+			return false
+		}
+	}
+	return true
+}
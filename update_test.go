@@ -0,0 +1,161 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+func TestPathfinderAddPolygon(t *testing.T) {
+	tests := []struct {
+		name        string
+		polygons    [][]pathfind.Point
+		added       []pathfind.Point
+		start, dest pathfind.Point
+	}{
+		{
+			name:     "hole in area",
+			polygons: polygonSquare,
+			added:    polygonO[1],
+			start:    pathfind.Pt(15, 10),
+			dest:     pathfind.Pt(30, 30),
+		},
+		{
+			name:     "island in hole",
+			polygons: polygonIslands[:4],
+			added:    polygonIslands[4],
+			start:    pathfind.Pt(42, 20),
+			dest:     pathfind.Pt(78, 20),
+		},
+		{
+			name: "polygon around hole",
+			polygons: [][]pathfind.Point{
+				polygonO[0],
+				{pathfind.Pt(18, 18), pathfind.Pt(22, 18), pathfind.Pt(22, 22), pathfind.Pt(18, 22)},
+			},
+			added: polygonO[1],
+			start: pathfind.Pt(5, 20),
+			dest:  pathfind.Pt(35, 22),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			incremental := pathfind.NewPathfinder(tt.polygons)
+			incremental.Path(tt.start, tt.dest)
+			incremental.AddPolygon(tt.added)
+			full := pathfind.NewPathfinder(append(tt.polygons[:len(tt.polygons):len(tt.polygons)], tt.added))
+
+			got := incremental.Path(tt.start, tt.dest)
+			want := full.Path(tt.start, tt.dest)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Path(%v, %v) after AddPolygon\n got: %v\nwant: %v", tt.start, tt.dest, got, want)
+			}
+			if g, w := incremental.VisibilityGraph(), full.VisibilityGraph(); !reflect.DeepEqual(g, w) {
+				t.Errorf("VisibilityGraph() after AddPolygon\n got: %v\nwant: %v", g, w)
+			}
+		})
+	}
+}
+
+func TestPathfinderRemovePolygon(t *testing.T) {
+	tests := []struct {
+		name        string
+		polygons    [][]pathfind.Point
+		removed     int
+		start, dest pathfind.Point
+	}{
+		{
+			name:     "hole in area",
+			polygons: polygonO,
+			removed:  1,
+			start:    pathfind.Pt(5, 20),
+			dest:     pathfind.Pt(35, 22),
+		},
+		{
+			name:     "hole around island",
+			polygons: polygonIslands,
+			removed:  3,
+			start:    pathfind.Pt(42, 20),
+			dest:     pathfind.Pt(60, 20),
+		},
+		{
+			name:     "first polygon",
+			polygons: polygonIslands,
+			removed:  0,
+			start:    pathfind.Pt(42, 20),
+			dest:     pathfind.Pt(78, 20),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			incremental := pathfind.NewPathfinder(tt.polygons)
+			incremental.Path(tt.start, tt.dest)
+			incremental.RemovePolygon(tt.removed)
+			full := pathfind.NewPathfinder(slices.Delete(slices.Clone(tt.polygons), tt.removed, tt.removed+1))
+
+			got := incremental.Path(tt.start, tt.dest)
+			want := full.Path(tt.start, tt.dest)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Path(%v, %v) after RemovePolygon\n got: %v\nwant: %v", tt.start, tt.dest, got, want)
+			}
+			if g, w := incremental.VisibilityGraph(), full.VisibilityGraph(); !reflect.DeepEqual(g, w) {
+				t.Errorf("VisibilityGraph() after RemovePolygon\n got: %v\nwant: %v", g, w)
+			}
+		})
+	}
+}
+
+func TestPathfinderAddRemovePolygonInvertedNesting(t *testing.T) {
+	square := func(x, y, size float64) []pathfind.Point {
+		return []pathfind.Point{pathfind.Pt(x, y), pathfind.Pt(x+size, y), pathfind.Pt(x+size, y+size), pathfind.Pt(x, y+size)}
+	}
+	// The diamond of polygonO, an obstacle within its bounding rectangle
+	// and one far outside of it, which extends the frame.
+	diamond, inside, outside := polygonO[1], square(11, 26, 3), square(50, 50, 5)
+	obstacles := [][]pathfind.Point{diamond}
+	pathfinder := pathfind.NewPathfinder(obstacles, pathfind.WithInvertedNesting())
+	check := func(op string) {
+		t.Helper()
+		full := pathfind.NewPathfinder(obstacles, pathfind.WithInvertedNesting())
+		gotMin, gotMax := pathfinder.Bounds()
+		wantMin, wantMax := full.Bounds()
+		if gotMin != wantMin || gotMax != wantMax {
+			t.Errorf("Bounds() after %s = %v, %v, want %v, %v", op, gotMin, gotMax, wantMin, wantMax)
+		}
+		if g, w := pathfinder.StaticVisibilityGraph(), full.StaticVisibilityGraph(); !reflect.DeepEqual(g, w) {
+			t.Errorf("StaticVisibilityGraph() after %s\n got: %v\nwant: %v", op, g, w)
+		}
+		for i, obstacle := range obstacles {
+			if pt := obstacle[0].Add(obstacle[2]).Mul(0.5); !pathfinder.InHole(pt, i) {
+				t.Errorf("InHole(%v, %d) after %s = false, want true", pt, i, op)
+			}
+		}
+		for _, dest := range []pathfind.Point{pathfind.Pt(30, 30), pathfind.Pt(12, 30), pathfind.Pt(60, 60)} {
+			start := pathfind.Pt(15, 10)
+			if got, want := pathfinder.Path(start, dest), full.Path(start, dest); !pathNearEq(got, want) {
+				t.Errorf("Path(%v, %v) after %s\n got: %v\nwant: %v", start, dest, op, got, want)
+			}
+		}
+	}
+
+	pathfinder.AddPolygon(inside)
+	obstacles = append(obstacles, inside)
+	check("AddPolygon inside")
+	pathfinder.AddPolygon(outside)
+	obstacles = append(obstacles, outside)
+	check("AddPolygon outside")
+	pathfinder.RemovePolygon(len(obstacles))
+	check("RemovePolygon of the frame")
+	pathfinder.RemovePolygon(2)
+	obstacles = obstacles[:2]
+	check("RemovePolygon outside")
+	pathfinder.RemovePolygon(0)
+	obstacles = obstacles[1:]
+	check("RemovePolygon of the first obstacle")
+}
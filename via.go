@@ -0,0 +1,283 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import (
+	"context"
+	"math"
+	"slices"
+)
+
+// PathVia finds the shortest path from start to dest that passes through
+// the gate, the line segment from gateA to gateB, e.g. a doorway that
+// a mission requires to pass. The point where the path passes the gate is
+// chosen to minimize the total length of the path, so the result is usually
+// shorter than two Path calls through a fixed point such as the middle of
+// the gate.
+//
+// The path is returned as a single path from start via the gate point to
+// the clamped dest. PathVia returns nil if the gate cannot be reached from
+// start or dest.
+func (p *Pathfinder) PathVia(start, dest, gateA, gateB Point) []Point {
+	if !allFinite(start, dest, gateA, gateB) {
+		return nil
+	}
+	dest = p.clampDest(start, dest)
+	level := containmentLevel(p.polygonSet, start)
+	if containmentLevel(p.polygonSet, dest) != level {
+		return nil
+	}
+	vis := copyGraph(p.cachedGraph)
+	p.linkIntoGraph(vis, start, nil)
+	p.linkIntoGraph(vis, dest, []Point{start})
+	cost, _ := p.travelCost()
+	distS, prevS := dijkstra(vis, []Point{start}, cost, nil)
+	distD, prevD := dijkstra(vis, []Point{dest}, cost, nil)
+
+	// The gate can only be passed where it lies in the accessible area, and
+	// a node can only reach the part of it that no edge hides. These parts
+	// are worked out once for each node, and only the nodes that see some
+	// part of the gate are considered.
+	parts := p.accessibleParts(gateA, gateB, level)
+	if len(parts) == 0 {
+		return nil
+	}
+	edges := p.edges()
+	seenS := gateVisibility(distS, gateA, gateB, parts, edges)
+	seenD := gateVisibility(distD, gateA, gateB, parts, edges)
+
+	// The shortest path through the gate is straight between the last node
+	// before and the first node after the gate, so the gate point is where
+	// this line meets the part of the gate both nodes see, or the nearest
+	// end point of this part.
+	g := gateB.Sub(gateA)
+	best := math.Inf(1)
+	var bestX, bestU, bestW Point
+	for u, su := range seenS {
+		for w, sw := range seenD {
+			t, ok := gateParam(u, w, gateA, gateB)
+			for _, part := range intersectParts(su, sw) {
+				ts := []float64{part[0], part[1]}
+				if ok {
+					ts = ts[:1]
+					ts[0] = max(part[0], min(part[1], t))
+				}
+				for _, t := range ts {
+					x := gateA.Add(g.Mul(t))
+					total := distS[u] + cost(u, x) + cost(x, w) + distD[w]
+					if total < best &&
+						(u == x || p.inSight(u, x)) &&
+						(w == x || p.inSight(x, w)) {
+						best = total
+						bestX, bestU, bestW = x, u, w
+					}
+				}
+			}
+		}
+	}
+	if math.IsInf(best, 1) {
+		return nil
+	}
+
+	path := tracePath(prevS, bestU)
+	if bestX != bestU {
+		path = append(path, bestX)
+	}
+	back := tracePath(prevD, bestW)
+	slices.Reverse(back)
+	if back[0] == bestX {
+		back = back[1:]
+	}
+	path = append(path, back...)
+	offsetPath(p.polygonSet, path, p.opts.margin)
+	return path
+}
+
+// gateParam returns the parameter t of the point a+t(b-a) on the line
+// through the gate from a to b that minimizes the sum of the distances to
+// u and w. If u and w lie on the same side of the gate, w is mirrored at
+// the line through the gate first. The result is false if the straight line
+// between u and w is parallel to the gate.
+func gateParam(u, w, a, b Point) (float64, bool) {
+	g := b.Sub(a)
+	su, sw := cross(g, u.Sub(a)), cross(g, w.Sub(a))
+	if su*sw > 0 {
+		// Mirror w at the line through the gate.
+		t := w.Sub(a).Dot(g) / g.Dot(g)
+		foot := a.Add(g.Mul(t))
+		w = foot.Add(foot.Sub(w))
+	}
+	d := w.Sub(u)
+	denom := cross(g, d)
+	if denom == 0 {
+		return 0, false
+	}
+	return cross(u.Sub(a), d) / denom, true
+}
+
+// gateVisibility returns the parts of the gate from a to b that each node
+// of dist sees, for the nodes that see some part of it. The parts are
+// given as intervals of the parameter t of the points a+t(b-a), starting
+// with the accessible parts of the gate.
+func gateVisibility(dist map[Point]float64, a, b Point, parts [][2]float64, edges [][2]Point) map[Point][][2]float64 {
+	seen := make(map[Point][][2]float64)
+	for u := range dist {
+		if vp := visibleParts(u, a, b, parts, edges); len(vp) > 0 {
+			seen[u] = vp
+		}
+	}
+	return seen
+}
+
+// accessibleParts returns the parts of the line segment from a to b that
+// lie in the accessible area of the given containment level, or in any
+// accessible area if level is negative, as sorted, disjoint intervals of
+// the parameter t of the points a+t(b-a).
+func (p *Pathfinder) accessibleParts(a, b Point, level int) [][2]float64 {
+	g := b.Sub(a)
+	ts := []float64{0, 1}
+	for _, e := range p.edges() {
+		ed := e[1].Sub(e[0])
+		denom := cross(g, ed)
+		if denom == 0 {
+			continue
+		}
+		w := e[0].Sub(a)
+		t, s := cross(w, ed)/denom, cross(w, g)/denom
+		if t > 0 && t < 1 && s >= 0 && s <= 1 {
+			ts = append(ts, t)
+		}
+	}
+	slices.Sort(ts)
+	var parts [][2]float64
+	for i := 1; i < len(ts); i++ {
+		lo, hi := ts[i-1], ts[i]
+		if lo == hi {
+			continue
+		}
+		mid := a.Add(g.Mul((lo + hi) / 2))
+		if l := containmentLevel(p.polygonSet, mid); l%2 == 0 || (level >= 0 && l != level) {
+			continue
+		}
+		if n := len(parts); n > 0 && parts[n-1][1] == lo {
+			parts[n-1][1] = hi
+			continue
+		}
+		parts = append(parts, [2]float64{lo, hi})
+	}
+	return parts
+}
+
+// visibleParts returns what remains of the parts of the line segment from
+// a to b after removing what any of the edges hides from u.
+func visibleParts(u, a, b Point, parts [][2]float64, edges [][2]Point) [][2]float64 {
+	g := b.Sub(a)
+	parts = slices.Clone(parts)
+	for _, e := range edges {
+		lo, hi, ok := shadow(u, a, g, e[0], e[1])
+		if !ok {
+			continue
+		}
+		var rest [][2]float64
+		for _, part := range parts {
+			if part[1] <= lo || part[0] >= hi {
+				rest = append(rest, part)
+				continue
+			}
+			if part[0] < lo {
+				rest = append(rest, [2]float64{part[0], lo})
+			}
+			if part[1] > hi {
+				rest = append(rest, [2]float64{hi, part[1]})
+			}
+		}
+		parts = rest
+		if len(parts) == 0 {
+			break
+		}
+	}
+	return parts
+}
+
+// shadow returns the open interval of the parameter t for which the line
+// segment from u to the point a+tg properly crosses the edge from c to d.
+// The result is false if the edge hides no point of the line.
+func shadow(u, a, g, c, d Point) (lo, hi float64, ok bool) {
+	cu, du := c.Sub(u), d.Sub(u)
+	switch w := cross(cu, du); {
+	case w == 0:
+		return 0, 0, false
+	case w < 0:
+		c, d, cu, du = d, c, du, cu
+	}
+	au, dc := a.Sub(u), d.Sub(c)
+	// The hidden points lie between the rays from u through c and d, and
+	// beyond the edge as seen from u. Each condition is h0 + h1·t > 0.
+	lo, hi = math.Inf(-1), math.Inf(1)
+	for _, h := range [][2]float64{
+		{cross(cu, au), cross(cu, g)},
+		{cross(au, du), cross(g, du)},
+		{-cross(dc, a.Sub(c)), -cross(dc, g)},
+	} {
+		switch {
+		case h[1] > 0:
+			lo = max(lo, -h[0]/h[1])
+		case h[1] < 0:
+			hi = min(hi, -h[0]/h[1])
+		case h[0] <= 0:
+			return 0, 0, false
+		}
+	}
+	return lo, hi, lo < hi
+}
+
+// intersectParts returns the intersection of two sorted lists of disjoint
+// intervals.
+func intersectParts(p, q [][2]float64) [][2]float64 {
+	var parts [][2]float64
+	for i, j := 0, 0; i < len(p) && j < len(q); {
+		lo, hi := max(p[i][0], q[j][0]), min(p[i][1], q[j][1])
+		if lo <= hi {
+			parts = append(parts, [2]float64{lo, hi})
+		}
+		if p[i][1] < q[j][1] {
+			i++
+		} else {
+			j++
+		}
+	}
+	return parts
+}
+
+// PathThrough finds a path that visits the given points in order, e.g. the
+// checkpoints of a courier route. It concatenates the shortest paths between
+// consecutive points, where the point at which two legs meet appears only
+// once. A point outside of the polygon set is clamped like the destination
+// of Path, and the next leg starts at the clamped point.
+//
+// PathThrough returns nil if fewer than two points are given or if any leg
+// has no path. Unlike Path, it does not update the graph returned by
+// VisibilityGraph.
+func (p *Pathfinder) PathThrough(points ...Point) []Point {
+	if len(points) < 2 {
+		return nil
+	}
+	s := p.getScratch()
+	defer p.scratchPool.Put(s)
+	var path []Point
+	start := points[0]
+	for _, dest := range points[1:] {
+		leg, _, _ := p.findPath(context.Background(), start, dest, s)
+		if leg == nil {
+			return nil
+		}
+		if path != nil {
+			leg = leg[1:]
+		}
+		path = append(path, leg...)
+		start = path[len(path)-1]
+	}
+	return path
+}
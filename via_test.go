@@ -0,0 +1,152 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+func TestPathfinderPathVia(t *testing.T) {
+	tests := []struct {
+		name         string
+		polygons     [][]pathfind.Point
+		start        pathfind.Point
+		dest         pathfind.Point
+		gateA, gateB pathfind.Point
+		want         []pathfind.Point
+	}{
+		{
+			name:     "through the end of the gate",
+			polygons: polygonSquare,
+			start:    pathfind.Pt(5, 5),
+			dest:     pathfind.Pt(35, 5),
+			gateA:    pathfind.Pt(20, 20),
+			gateB:    pathfind.Pt(20, 40),
+			want: []pathfind.Point{
+				pathfind.Pt(5, 5),
+				pathfind.Pt(20, 20),
+				pathfind.Pt(35, 5),
+			},
+		},
+		{
+			name:     "start and dest on the same side of the gate",
+			polygons: polygonSquare,
+			start:    pathfind.Pt(5, 30),
+			dest:     pathfind.Pt(15, 35),
+			gateA:    pathfind.Pt(20, 0),
+			gateB:    pathfind.Pt(20, 40),
+			want: []pathfind.Point{
+				pathfind.Pt(5, 30),
+				pathfind.Pt(20, 33.75),
+				pathfind.Pt(15, 35),
+			},
+		},
+		{
+			name:     "gate in the wide passage",
+			polygons: polygonTwoPassages,
+			start:    pathfind.Pt(20, 10),
+			dest:     pathfind.Pt(80, 10),
+			gateA:    pathfind.Pt(50, 50),
+			gateB:    pathfind.Pt(50, 60),
+			want: []pathfind.Point{
+				pathfind.Pt(20, 10),
+				pathfind.Pt(40, 50),
+				pathfind.Pt(50, 50),
+				pathfind.Pt(60, 50),
+				pathfind.Pt(80, 10),
+			},
+		},
+		{
+			name:     "grid of holes",
+			polygons: gridOfHoles(10),
+			start:    pathfind.Pt(50, 1),
+			dest:     pathfind.Pt(51, 99),
+			gateA:    pathfind.Pt(48, 50),
+			gateB:    pathfind.Pt(52, 50),
+			want: []pathfind.Point{
+				pathfind.Pt(50, 1),
+				pathfind.Pt(50.5, 50),
+				pathfind.Pt(51, 99),
+			},
+		},
+		{
+			name:     "gate outside",
+			polygons: polygonSquare,
+			start:    pathfind.Pt(5, 5),
+			dest:     pathfind.Pt(35, 5),
+			gateA:    pathfind.Pt(50, 0),
+			gateB:    pathfind.Pt(50, 10),
+			want:     nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pathfinder := pathfind.NewPathfinder(tt.polygons)
+			got := pathfinder.PathVia(tt.start, tt.dest, tt.gateA, tt.gateB)
+			if !pathNearEq(got, tt.want) {
+				t.Errorf("PathVia(%v, %v, %v, %v)\n got: %v\nwant: %v",
+					tt.start, tt.dest, tt.gateA, tt.gateB, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathfinderPathThrough(t *testing.T) {
+	tests := []struct {
+		name   string
+		points []pathfind.Point
+		want   []pathfind.Point
+	}{
+		{
+			name:   "single leg",
+			points: []pathfind.Point{pathfind.Pt(5, 5), pathfind.Pt(25, 5)},
+			want:   []pathfind.Point{pathfind.Pt(5, 5), pathfind.Pt(10, 10), pathfind.Pt(20, 10), pathfind.Pt(25, 5)},
+		},
+		{
+			name:   "back and forth",
+			points: []pathfind.Point{pathfind.Pt(5, 5), pathfind.Pt(25, 5), pathfind.Pt(5, 15)},
+			want: []pathfind.Point{
+				pathfind.Pt(5, 5), pathfind.Pt(10, 10), pathfind.Pt(20, 10), pathfind.Pt(25, 5),
+				pathfind.Pt(20, 10), pathfind.Pt(5, 15),
+			},
+		},
+		{
+			name:   "clamped checkpoint",
+			points: []pathfind.Point{pathfind.Pt(5, 5), pathfind.Pt(5, 25), pathfind.Pt(25, 15)},
+			want:   []pathfind.Point{pathfind.Pt(5, 5), pathfind.Pt(5, 20), pathfind.Pt(25, 15)},
+		},
+		{
+			name:   "unreachable start",
+			points: []pathfind.Point{pathfind.Pt(15, 5), pathfind.Pt(25, 5), pathfind.Pt(5, 5)},
+			want:   nil,
+		},
+		{
+			name:   "single point",
+			points: []pathfind.Point{pathfind.Pt(5, 5)},
+			want:   nil,
+		},
+	}
+	pathfinder := pathfind.NewPathfinder(polygonU)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pathfinder.PathThrough(tt.points...)
+			if !pathNearEq(got, tt.want) {
+				t.Errorf("PathThrough(%v)\n got: %v\nwant: %v", tt.points, got, tt.want)
+			}
+		})
+	}
+}
+
+func BenchmarkPathfinderPathVia(b *testing.B) {
+	pathfinder := pathfind.NewPathfinder(gridOfHoles(10))
+	start, dest := pathfind.Pt(1, 1), pathfind.Pt(99, 1)
+	gateA, gateB := pathfind.Pt(48, 50), pathfind.Pt(52, 50)
+	b.ReportAllocs()
+	for range b.N {
+		pathfinder.PathVia(start, dest, gateA, gateB)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import (
+	"math"
+	"slices"
+)
+
+// visibilityAngleEps is the angular offset of the rays that are cast just
+// past each polygon vertex to find the walls behind it.
+const visibilityAngleEps = 1e-5
+
+// VisibilityPolygon returns the region that is visible from the viewpoint
+// from, e.g. for rendering a light cone or the area revealed by a field of
+// view. The region is returned as a ring of points in the same orientation
+// as the polygons the Pathfinder expects, i.e. counterclockwise in
+// a coordinate system where the y axis points up.
+//
+// The region is computed by an angular sweep: a ray is cast towards each
+// polygon vertex and just past it on either side, and the nearest polygon
+// edge hit by each ray becomes a point of the ring. A viewpoint on or very
+// close to a polygon outline is moved slightly into the accessible area
+// first. VisibilityPolygon returns nil if from lies outside the accessible
+// area.
+func (p *Pathfinder) VisibilityPolygon(from Point) []Point {
+	if !from.isFinite() || !p.polygonSet.Contains(p2v(from)) {
+		return nil
+	}
+	if !strictlyInside(p.polygonSet, from) {
+		from = ensureInside(p.polygonSet, from, p.opts.margin)
+	}
+	edges := p.edges()
+
+	var angles []float64
+	for _, e := range edges {
+		d := e[0].Sub(from)
+		a := math.Atan2(d.Y, d.X)
+		angles = append(angles, a-visibilityAngleEps, a, a+visibilityAngleEps)
+	}
+	slices.Sort(angles)
+
+	var ring []Point
+	for _, a := range angles {
+		sin, cos := math.Sincos(a)
+		hit, ok := castRay(from, Point{X: cos, Y: sin}, edges)
+		if !ok {
+			continue
+		}
+		if len(ring) > 0 && nodeDist(ring[len(ring)-1], hit) < p.opts.margin {
+			continue
+		}
+		ring = append(ring, hit)
+	}
+	if len(ring) > 1 && nodeDist(ring[0], ring[len(ring)-1]) < p.opts.margin {
+		ring = ring[:len(ring)-1]
+	}
+	return ring
+}
+
+// Raycast returns the point where the straight line from from to to first
+// hits a polygon edge, e.g. to test whether a guard can see the player and,
+// if not, where the line of sight ends. The result blocked is false if the
+// line reaches to without hitting an edge. Edges are hit at their end
+// points as well, so a line that only touches a polygon vertex is blocked.
+// Edges through from itself are ignored. Unlike CanSee, Raycast does not
+// check whether the line lies within the accessible area. If a coordinate
+// of from or to is NaN or infinite, the line is blocked and hit is the zero
+// Point.
+func (p *Pathfinder) Raycast(from, to Point) (hit Point, blocked bool) {
+	if !allFinite(from, to) {
+		return Point{}, true
+	}
+	hit, ok := castRay(from, to.Sub(from), p.edges())
+	if !ok || from.Dist(hit) > from.Dist(to) {
+		return Point{}, false
+	}
+	return hit, true
+}
+
+// edges returns the edges that bound the accessible area: the edges of all
+// polygons except the weighted regions and preferred areas, and the
+// segments of the walls.
+func (p *Pathfinder) edges() [][2]Point {
+	return append(wallEdges(p.polygons, p.polygonSet), p.wallSegments()...)
+}
+
+// castRay returns the point where the ray from o in direction d first hits
+// one of the edges. The result is false if the ray hits no edge.
+func castRay(o, d Point, edges [][2]Point) (Point, bool) {
+	nearest := math.Inf(1)
+	for _, e := range edges {
+		ed := e[1].Sub(e[0])
+		denom := cross(d, ed)
+		if denom == 0 {
+			continue
+		}
+		w := e[0].Sub(o)
+		t := cross(w, ed) / denom
+		s := cross(w, d) / denom
+		if t > 0 && s >= 0 && s <= 1 && t < nearest {
+			nearest = t
+		}
+	}
+	if math.IsInf(nearest, 1) {
+		return Point{}, false
+	}
+	return Point{X: o.X + nearest*d.X, Y: o.Y + nearest*d.Y}, true
+}
@@ -0,0 +1,129 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+func TestPathfinderVisibilityPolygon(t *testing.T) {
+	tests := []struct {
+		name     string
+		polygons [][]pathfind.Point
+		from     pathfind.Point
+		wantArea float64
+	}{
+		{
+			name:     "convex area",
+			polygons: polygonSquare,
+			from:     pathfind.Pt(10, 10),
+			wantArea: 1600,
+		},
+		{
+			name:     "on the outline",
+			polygons: polygonSquare,
+			from:     pathfind.Pt(0, 10),
+			wantArea: 1600,
+		},
+		{
+			// The square minus the diamond and its shadow, which reaches
+			// from the back faces of the diamond to the walls.
+			name:     "shadow behind hole",
+			polygons: polygonO,
+			from:     pathfind.Pt(5, 20),
+			wantArea: 850,
+		},
+		{
+			name:     "outside",
+			polygons: polygonSquare,
+			from:     pathfind.Pt(50, 10),
+			wantArea: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pathfinder := pathfind.NewPathfinder(tt.polygons)
+			got := pathfinder.VisibilityPolygon(tt.from)
+			if area := ringArea(got); math.Abs(area-tt.wantArea) > 0.1 {
+				t.Errorf("VisibilityPolygon(%v) = %v with area %g, want area %g",
+					tt.from, got, area, tt.wantArea)
+			}
+		})
+	}
+}
+
+func TestPathfinderRaycast(t *testing.T) {
+	tests := []struct {
+		name        string
+		from        pathfind.Point
+		to          pathfind.Point
+		wantHit     pathfind.Point
+		wantBlocked bool
+	}{
+		{
+			name:        "clear",
+			from:        pathfind.Pt(5, 5),
+			to:          pathfind.Pt(35, 5),
+			wantBlocked: false,
+		},
+		{
+			name:        "hole edge",
+			from:        pathfind.Pt(5, 15),
+			to:          pathfind.Pt(35, 15),
+			wantHit:     pathfind.Pt(15, 15),
+			wantBlocked: true,
+		},
+		{
+			name:        "hole vertex",
+			from:        pathfind.Pt(5, 20),
+			to:          pathfind.Pt(35, 20),
+			wantHit:     pathfind.Pt(10, 20),
+			wantBlocked: true,
+		},
+		{
+			name:        "outer edge",
+			from:        pathfind.Pt(20, 35),
+			to:          pathfind.Pt(20, 50),
+			wantHit:     pathfind.Pt(20, 40),
+			wantBlocked: true,
+		},
+		{
+			name:        "short of the hole",
+			from:        pathfind.Pt(5, 15),
+			to:          pathfind.Pt(12, 15),
+			wantBlocked: false,
+		},
+		{
+			name:        "zero length",
+			from:        pathfind.Pt(5, 5),
+			to:          pathfind.Pt(5, 5),
+			wantBlocked: false,
+		},
+	}
+	pathfinder := pathfind.NewPathfinder(polygonO)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hit, blocked := pathfinder.Raycast(tt.from, tt.to)
+			if blocked != tt.wantBlocked || hit.Dist(tt.wantHit) > 1e-9 {
+				t.Errorf("Raycast(%v, %v) = %v, %v, want %v, %v",
+					tt.from, tt.to, hit, blocked, tt.wantHit, tt.wantBlocked)
+			}
+		})
+	}
+}
+
+// ringArea returns the signed area of a ring, positive for the orientation
+// of the polygons the Pathfinder expects.
+func ringArea(ring []pathfind.Point) float64 {
+	var a float64
+	for i, p := range ring {
+		q := ring[(i+1)%len(ring)]
+		a += p.X*q.Y - q.X*p.Y
+	}
+	return a / 2
+}
@@ -0,0 +1,166 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import (
+	"slices"
+
+	"github.com/fzipp/geom"
+	"github.com/fzipp/pathfind/internal/poly"
+)
+
+// A WallPolyline is an open polyline that blocks paths, e.g. a fence, given
+// to a Pathfinder with WithWalls. Paths cannot cross any of its segments,
+// including at the vertices where the segments meet, but go around its
+// ends.
+//
+// Walls are not part of the polygon set: they are never treated as an area
+// or a hole, so they do not change which points are accessible, e.g. for
+// Contains or NavigableArea. A wall only blocks the lines of sight between
+// points of the accessible area, and parts of it outside of the accessible
+// area have no effect. Paths turn around a wall at the distance given by
+// WithMargin, or WithClearance if set: at two corners of a square cap
+// beyond each end and at the outer side of each bend.
+type WallPolyline []Point
+
+// maxMiter limits the distance of the turning points at the bends of a
+// WallPolyline from the polyline at sharp turns, as a multiple of their
+// distance from the segments.
+const maxMiter = 10
+
+// vertices returns the vertices of the wall without consecutive repeated
+// vertices, or nil if the wall has fewer than two distinct vertices.
+func (w WallPolyline) vertices() []Point {
+	vs := slices.CompactFunc(slices.Clone(w), func(a, b Point) bool { return p2v(a) == p2v(b) })
+	if len(vs) < 2 {
+		return nil
+	}
+	return vs
+}
+
+// ring returns the wall as a ring that runs along the polyline and back on
+// itself, or nil if the wall has fewer than two distinct vertices. The ring
+// encloses no area. Its IsCrossedBy method reports whether a line segment
+// crosses the wall, just as for a polygon with two vertices: a line
+// through a vertex where two segments meet crosses the wall if it passes
+// from one side to the other, while a line touching an end does not.
+func (w WallPolyline) ring() poly.Polygon {
+	vs := w.vertices()
+	if vs == nil {
+		return nil
+	}
+	ring := ps2vs(vs)
+	for i := len(vs) - 2; i > 0; i-- {
+		ring = append(ring, p2v(vs[i]))
+	}
+	return ring
+}
+
+// turningPoints returns the points at which paths turn around the wall, at
+// the distance d from it: the corners of a square cap beyond each end and
+// the corner at the outer side of each bend.
+func (w WallPolyline) turningPoints(d float64) []Point {
+	vs := w.vertices()
+	n := len(vs)
+	if n < 2 {
+		return nil
+	}
+	corners := func(end, prev Point) []Point {
+		e := end.Sub(prev).Norm()
+		side := leftNormal(e)
+		return []Point{end.Add(e.Add(side).Mul(d)), end.Add(e.Sub(side).Mul(d))}
+	}
+	pts := corners(vs[0], vs[1])
+	for i := 1; i < n-1; i++ {
+		in, out := vs[i].Sub(vs[i-1]), vs[i+1].Sub(vs[i])
+		turn := cross(in, out)
+		if turn == 0 {
+			continue
+		}
+		// The corner moves along the bisector of the normals of the
+		// adjacent segments, so that it keeps the distance d from both.
+		n1, n2 := leftNormal(in), leftNormal(out)
+		miter := n1.Add(n2).Mul(d / max(1+n1.Dot(n2), 2/maxMiter))
+		if turn > 0 {
+			// A left turn: the outer side of the bend is on the right.
+			miter = miter.Mul(-1)
+		}
+		pts = append(pts, vs[i].Add(miter))
+	}
+	return append(pts, corners(vs[n-1], vs[n-2])...)
+}
+
+// leftNormal returns the unit vector perpendicular to d, rotated by 90
+// degrees counterclockwise for a y axis pointing up.
+func leftNormal(d Point) Point {
+	d = d.Norm()
+	return Point{X: -d.Y, Y: d.X}
+}
+
+// wallRings returns the rings of the walls as returned by their ring
+// method, without the walls with fewer than two distinct vertices.
+func wallRings(walls []WallPolyline) poly.PolygonSet {
+	var rings poly.PolygonSet
+	for _, w := range walls {
+		if r := w.ring(); r != nil {
+			rings = append(rings, r)
+		}
+	}
+	return rings
+}
+
+// crossesWall reports whether the line segment from a to b crosses any of
+// the walls, given as rings by wallRings.
+func crossesWall(walls poly.PolygonSet, a, b geom.Vec2) bool {
+	ls := poly.LineSeg{A: a, B: b}
+	for _, w := range walls {
+		if w.IsCrossedBy(ls) {
+			return true
+		}
+	}
+	return false
+}
+
+// inSight reports whether b is in line of sight of a: the line segment
+// between them stays within the polygon set and does not cross a wall.
+func (p *Pathfinder) inSight(a, b Point) bool {
+	va, vb := p2v(a), p2v(b)
+	return inLineOfSight(p.polygonSet, va, vb) && !crossesWall(p.wallRings, va, vb)
+}
+
+// wallTurningPoints returns the turning points of the walls that lie
+// strictly inside the accessible area.
+func (p *Pathfinder) wallTurningPoints() []Point {
+	var pts []Point
+	for _, w := range p.opts.wallPolylines {
+		for _, pt := range w.turningPoints(p.wallDistance()) {
+			if strictlyInside(p.polygonSet, pt) {
+				pts = append(pts, pt)
+			}
+		}
+	}
+	return pts
+}
+
+// wallDistance returns the distance at which paths turn around the walls:
+// the clearance if set, else the margin.
+func (p *Pathfinder) wallDistance() float64 {
+	if p.opts.clearance > 0 {
+		return p.opts.clearance
+	}
+	return p.opts.margin
+}
+
+// wallSegments returns the line segments of the walls.
+func (p *Pathfinder) wallSegments() [][2]Point {
+	var segs [][2]Point
+	for _, w := range p.opts.wallPolylines {
+		vs := w.vertices()
+		for i := 1; i < len(vs); i++ {
+			segs = append(segs, [2]Point{vs[i-1], vs[i]})
+		}
+	}
+	return segs
+}
@@ -0,0 +1,142 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+func TestPathfinderWithWalls(t *testing.T) {
+	room := [][]pathfind.Point{polygonO[0]}
+	fenceL := pathfind.WallPolyline{pathfind.Pt(20, 5), pathfind.Pt(20, 30), pathfind.Pt(35, 30)}
+	zigzag := pathfind.WallPolyline{pathfind.Pt(5, 20), pathfind.Pt(15, 25), pathfind.Pt(25, 15), pathfind.Pt(35, 20)}
+	tests := []struct {
+		name  string
+		walls []pathfind.WallPolyline
+		start pathfind.Point
+		dest  pathfind.Point
+		want  []pathfind.Point
+	}{
+		{
+			name:  "around the end of a wall",
+			walls: []pathfind.WallPolyline{fenceL},
+			start: pathfind.Pt(10, 15),
+			dest:  pathfind.Pt(30, 15),
+			want: []pathfind.Point{
+				pathfind.Pt(10, 15),
+				pathfind.Pt(20, 5),
+				pathfind.Pt(20, 5),
+				pathfind.Pt(30, 15),
+			},
+		},
+		{
+			name:  "not through the joint of two segments",
+			walls: []pathfind.WallPolyline{fenceL},
+			start: pathfind.Pt(10, 35),
+			dest:  pathfind.Pt(30, 20),
+			want: []pathfind.Point{
+				pathfind.Pt(10, 35),
+				pathfind.Pt(35, 30),
+				pathfind.Pt(30, 20),
+			},
+		},
+		{
+			name:  "zigzag wall",
+			walls: []pathfind.WallPolyline{zigzag},
+			start: pathfind.Pt(2, 30),
+			dest:  pathfind.Pt(38, 10),
+			want: []pathfind.Point{
+				pathfind.Pt(2, 30),
+				pathfind.Pt(5, 20),
+				pathfind.Pt(38, 10),
+			},
+		},
+		{
+			name:  "same side of the wall",
+			walls: []pathfind.WallPolyline{zigzag},
+			start: pathfind.Pt(10, 30),
+			dest:  pathfind.Pt(30, 30),
+			want:  []pathfind.Point{pathfind.Pt(10, 30), pathfind.Pt(30, 30)},
+		},
+		{
+			name:  "wall with a single distinct vertex",
+			walls: []pathfind.WallPolyline{{pathfind.Pt(20, 5), pathfind.Pt(20, 5)}},
+			start: pathfind.Pt(20, 2),
+			dest:  pathfind.Pt(20, 8),
+			want:  []pathfind.Point{pathfind.Pt(20, 2), pathfind.Pt(20, 8)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pathfinder := pathfind.NewPathfinder(room, pathfind.WithWalls(tt.walls...))
+			got := pathfinder.Path(tt.start, tt.dest)
+			if !pathNearEq(got, tt.want) {
+				t.Errorf("Path(%v, %v) = %v, want %v", tt.start, tt.dest, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathfinderWallsOutsideOfArea(t *testing.T) {
+	square := []pathfind.Point{pathfind.Pt(0, 0), pathfind.Pt(100, 0), pathfind.Pt(100, 100), pathfind.Pt(0, 100)}
+	hole := []pathfind.Point{pathfind.Pt(40, 40), pathfind.Pt(60, 40), pathfind.Pt(60, 60), pathfind.Pt(40, 60)}
+	tests := []struct {
+		name     string
+		wall     pathfind.WallPolyline
+		outside  pathfind.Point
+		start    pathfind.Point
+		dest     pathfind.Point
+		wantPath []pathfind.Point
+	}{
+		{
+			name:    "wall crossing a hole",
+			wall:    pathfind.WallPolyline{pathfind.Pt(50, 20), pathfind.Pt(50, 50)},
+			outside: pathfind.Pt(50, 45),
+			start:   pathfind.Pt(44, 27),
+			dest:    pathfind.Pt(55, 24),
+			wantPath: []pathfind.Point{
+				pathfind.Pt(44, 27),
+				pathfind.Pt(50, 20),
+				pathfind.Pt(55, 24),
+			},
+		},
+		{
+			name:    "wall crossing the outer boundary",
+			wall:    pathfind.WallPolyline{pathfind.Pt(90, 70), pathfind.Pt(120, 70)},
+			outside: pathfind.Pt(110, 70),
+			start:   pathfind.Pt(95, 65),
+			dest:    pathfind.Pt(96, 78),
+			wantPath: []pathfind.Point{
+				pathfind.Pt(95, 65),
+				pathfind.Pt(90, 70),
+				pathfind.Pt(96, 78),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polygons := [][]pathfind.Point{square, hole}
+			plain := pathfind.NewPathfinder(polygons)
+			pathfinder := pathfind.NewPathfinder(polygons, pathfind.WithWalls(tt.wall))
+			if pathfinder.Contains(tt.outside) {
+				t.Errorf("Contains(%v) = true, want false", tt.outside)
+			}
+			if got, want := pathfinder.ContainmentLevel(tt.outside), plain.ContainmentLevel(tt.outside); got != want {
+				t.Errorf("ContainmentLevel(%v) = %d, want %d", tt.outside, got, want)
+			}
+			if got, want := pathfinder.NavigableArea(), plain.NavigableArea(); got != want {
+				t.Errorf("NavigableArea() = %g, want %g", got, want)
+			}
+			if got := pathfinder.Path(tt.outside, tt.outside.Add(pathfind.Pt(1, 0))); got != nil {
+				t.Errorf("Path(%v, ...) from outside = %v, want nil", tt.outside, got)
+			}
+			if got := pathfinder.Path(tt.start, tt.dest); !pathNearEq(got, tt.wantPath) {
+				t.Errorf("Path(%v, %v) = %v, want %v", tt.start, tt.dest, got, tt.wantPath)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import (
+	"context"
+	"math"
+	"slices"
+)
+
+// A Waypoint is a point of a path returned by PathDetailed, together with
+// the polygon corner that it was derived from.
+type Waypoint struct {
+	Point Point
+	// PolygonIndex is the index of the polygon, as passed to NewPathfinder,
+	// at whose corner the path turns. It is -1 for the start and
+	// destination of the path and for nodes added with Graph.Link.
+	PolygonIndex int
+	// EdgeIndex is the index of the corner within the polygon. The path
+	// turns between the polygon edges with the indices EdgeIndex-1 and
+	// EdgeIndex, where edge i leads from vertex i to vertex i+1 (wrapping
+	// around). With WithClearance it is the corner nearest to the waypoint.
+	// It is -1 if PolygonIndex is -1.
+	EdgeIndex int
+}
+
+// PathDetailed is like Path, but reports for each waypoint the polygon
+// corner it was derived from, e.g. for a navigation debugger. The points
+// of the waypoints are the same as those of the path returned by Path.
+//
+// Like PathBatch, PathDetailed does not update the graph returned by
+// VisibilityGraph.
+func (p *Pathfinder) PathDetailed(start, dest Point) []Waypoint {
+	s := p.getScratch()
+	defer p.scratchPool.Put(s)
+	path, _, _ := p.searchPath(context.Background(), start, dest, math.Inf(1), s)
+	if path == nil {
+		return nil
+	}
+	waypoints := make([]Waypoint, len(path))
+	for i, pt := range path {
+		waypoints[i] = Waypoint{Point: pt, PolygonIndex: -1, EdgeIndex: -1}
+		if i > 0 && i < len(path)-1 {
+			waypoints[i].PolygonIndex, waypoints[i].EdgeIndex = p.cornerOf(pt)
+		}
+	}
+	offsetPath(p.polygonSet, path, p.opts.margin)
+	for i, pt := range path {
+		waypoints[i].Point = pt
+	}
+	if p.opts.simplify {
+		waypoints = keepWaypoints(waypoints, p.simplifyMask(path))
+	}
+	return waypoints
+}
+
+// cornerOf returns the indices of the polygon and its vertex from which the
+// visibility graph node n was derived, or -1, -1 if n is not derived from
+// a polygon corner.
+func (p *Pathfinder) cornerOf(n Point) (polygon, vertex int) {
+	for i, nodes := range p.concaveOf {
+		if !slices.Contains(nodes, n) {
+			continue
+		}
+		vertex, best := -1, 0.0
+		for j, v := range openRing(p.polygons[i]) {
+			if d := nodeDist(v, n); vertex < 0 || d < best {
+				vertex, best = j, d
+			}
+		}
+		return i, vertex
+	}
+	return -1, -1
+}
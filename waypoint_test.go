@@ -0,0 +1,68 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+func TestPathfinderPathDetailed(t *testing.T) {
+	tests := []struct {
+		name        string
+		polygons    [][]pathfind.Point
+		start       pathfind.Point
+		dest        pathfind.Point
+		wantCorners [][2]int
+	}{
+		{
+			name:        "corners of area polygon",
+			polygons:    polygonU,
+			start:       pathfind.Pt(5, 5),
+			dest:        pathfind.Pt(25, 5),
+			wantCorners: [][2]int{{-1, -1}, {0, 2}, {0, 3}, {-1, -1}},
+		},
+		{
+			name:        "corner of hole",
+			polygons:    polygonO,
+			start:       pathfind.Pt(12, 8),
+			dest:        pathfind.Pt(12, 32),
+			wantCorners: [][2]int{{-1, -1}, {1, 3}, {-1, -1}},
+		},
+		{
+			name:        "straight line",
+			polygons:    polygonO,
+			start:       pathfind.Pt(5, 5),
+			dest:        pathfind.Pt(5, 35),
+			wantCorners: [][2]int{{-1, -1}, {-1, -1}},
+		},
+		{
+			name:     "no path",
+			polygons: polygonU,
+			start:    pathfind.Pt(15, 5),
+			dest:     pathfind.Pt(25, 5),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pathfinder := pathfind.NewPathfinder(tt.polygons)
+			got := pathfinder.PathDetailed(tt.start, tt.dest)
+			path := pathfinder.Path(tt.start, tt.dest)
+			if len(got) != len(tt.wantCorners) || len(got) != len(path) {
+				t.Fatalf("PathDetailed(%v, %v) = %v, want %d waypoints along %v",
+					tt.start, tt.dest, got, len(tt.wantCorners), path)
+			}
+			for i, w := range got {
+				if w.Point != path[i] {
+					t.Errorf("waypoint %d: point = %v, want %v", i, w.Point, path[i])
+				}
+				if corner := [2]int{w.PolygonIndex, w.EdgeIndex}; corner != tt.wantCorners[i] {
+					t.Errorf("waypoint %d: polygon and edge index = %v, want %v", i, corner, tt.wantCorners[i])
+				}
+			}
+		})
+	}
+}
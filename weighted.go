@@ -0,0 +1,198 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import (
+	"math"
+	"slices"
+
+	"github.com/fzipp/geom"
+	"github.com/fzipp/pathfind/internal/poly"
+)
+
+// A WeightedPolygon is a polygon for NewWeightedPathfinder together with
+// the cost multiplier of travelling through it.
+type WeightedPolygon struct {
+	Points []Point
+	// Weight is the cost multiplier for path segments inside the polygon,
+	// e.g. 3 for mud that is three times as slow to cross as open ground,
+	// or 0.5 for a road. A polygon with a weight that is not positive, such
+	// as the zero value, is a regular area polygon or hole.
+	Weight float64
+}
+
+// NewWeightedPathfinder creates a Pathfinder like NewPathfinder, where some
+// of the polygons are weighted regions, e.g. slow terrain such as mud or
+// water that paths may cross at a higher cost, or fast terrain such as
+// roads. The cost of a path segment is the sum of the lengths of its pieces
+// inside and outside of the weighted regions, each multiplied by the
+// weight of the region it lies in, or by 1 outside of the regions. Path
+// finds the path of the least cost, which may be a longer route around
+// expensive terrain.
+//
+// Weighted regions are overlays: they are not part of the polygon set that
+// bounds the accessible area and neither form areas nor holes themselves.
+// Their vertices inside the accessible area are additional turning points
+// for paths. Where weighted regions overlap, the weight of the polygon that
+// comes last applies. A path segment that runs along the outline of
+// a region gets the lower of the weights on both sides. The indices of the
+// polygons, e.g. for WithTags, are the indices in areas.
+//
+// The options WithPreferredAreas and WithMetric are combined with the
+// weights: preferred areas are weighted regions with the discount as
+// weight, and the weights multiply the cost given by the metric.
+func NewWeightedPathfinder(areas []WeightedPolygon, opts ...Option) *Pathfinder {
+	polygons := make([][]Point, len(areas))
+	weights := make(map[int]float64)
+	for i, area := range areas {
+		polygons[i] = area.Points
+		if area.Weight > 0 {
+			weights[i] = area.Weight
+		}
+	}
+	return NewPathfinder(polygons, append(slices.Clip(opts), withRegionWeights(weights))...)
+}
+
+// takeRegions removes the polygons with the given weights from the polygon
+// set ps and returns them in a set of the same size, in which all other
+// polygons are nil, together with their weights. Invalid indices are
+// ignored.
+func takeRegions(ps poly.PolygonSet, weights map[int]float64) (poly.PolygonSet, []float64) {
+	if len(weights) == 0 {
+		return nil, nil
+	}
+	regions := make(poly.PolygonSet, len(ps))
+	ws := make([]float64, len(ps))
+	for i, w := range weights {
+		if i >= 0 && i < len(ps) {
+			regions[i], ps[i] = ps[i], nil
+			ws[i] = w
+		}
+	}
+	return regions, ws
+}
+
+// verticesInside returns the vertices of polygon that lie strictly inside
+// the polygon set ps.
+func verticesInside(ps poly.PolygonSet, polygon poly.Polygon) []Point {
+	var vs []Point
+	for _, v := range polygon {
+		if pt := v2p(v); strictlyInside(ps, pt) {
+			vs = append(vs, pt)
+		}
+	}
+	return vs
+}
+
+// hasRegions reports whether the Pathfinder was created with weighted
+// regions or preferred areas.
+func (p *Pathfinder) hasRegions() bool {
+	return p.regions != nil
+}
+
+// minWeight returns the smallest cost multiplier of the accessible area:
+// the smallest weight of the weighted regions, but at most 1.
+func (p *Pathfinder) minWeight() float64 {
+	m := 1.0
+	for i, region := range p.regions {
+		if len(region) > 0 {
+			m = min(m, p.weights[i])
+		}
+	}
+	return m
+}
+
+// straightIsCheapest reports whether the straight line is the cheapest
+// connection between two points in line of sight of each other. With
+// weighted regions or the costs given by WithMetric it may not be, so the
+// connection is left to the search.
+func (p *Pathfinder) straightIsCheapest() bool {
+	return !p.hasRegions() && p.opts.cost == nil
+}
+
+// travelCost returns the cost and the heuristic function for the path
+// search: the functions given by WithMetric, or the Euclidean distance,
+// weighted within the weighted regions. All searches on the visibility
+// graph use them, so that their results agree with Path.
+func (p *Pathfinder) travelCost() (cost, heuristic func(a, b Point) float64) {
+	cost, heuristic = nodeDist, nodeDist
+	if p.opts.cost != nil {
+		cost, heuristic = p.opts.cost, p.opts.heuristic
+	}
+	if !p.hasRegions() {
+		return cost, heuristic
+	}
+	base, baseHeuristic := cost, heuristic
+	cost = func(a, b Point) float64 {
+		l := nodeDist(a, b)
+		if l == 0 {
+			return base(a, b)
+		}
+		return base(a, b) * p.weightedLength(a, b) / l
+	}
+	minWeight := p.minWeight()
+	heuristic = func(a, b Point) float64 {
+		return baseHeuristic(a, b) * minWeight
+	}
+	return cost, heuristic
+}
+
+// weightedLength returns the length of the line segment from a to b, with
+// the lengths of its pieces inside of weighted regions multiplied by their
+// weights. The segment is split at its intersections with the outlines of
+// the regions, and each piece is classified by its midpoint.
+func (p *Pathfinder) weightedLength(a, b Point) float64 {
+	ts := p.regionSplits(a, b)
+	length := nodeDist(a, b)
+	var weighted float64
+	for i := 1; i < len(ts); i++ {
+		mid := p2v(lerp(a, b, (ts[i-1]+ts[i])/2))
+		weighted += (ts[i] - ts[i-1]) * length * p.weightAt(mid)
+	}
+	return weighted
+}
+
+// regionSplits returns the parameters t of the points a+t(b-a) at which the
+// line segment from a to b crosses the outlines of the weighted regions, in
+// ascending order and enclosed by 0 and 1.
+func (p *Pathfinder) regionSplits(a, b Point) []float64 {
+	d := b.Sub(a)
+	ts := []float64{0, 1}
+	for _, region := range p.regions {
+		for i := range region {
+			e1, e2 := v2p(region[i]), v2p(region[(i+1)%len(region)])
+			ed := e2.Sub(e1)
+			denom := cross(d, ed)
+			if denom == 0 {
+				continue
+			}
+			w := e1.Sub(a)
+			t := cross(w, ed) / denom
+			s := cross(w, d) / denom
+			if t > 0 && t < 1 && s >= 0 && s <= 1 {
+				ts = append(ts, t)
+			}
+		}
+	}
+	slices.Sort(ts)
+	return ts
+}
+
+// weightAt returns the cost multiplier at pt: the weight of the last
+// weighted region that contains pt, or 1 outside of all regions. On the
+// outline of a region the lower of the weights on both sides applies.
+func (p *Pathfinder) weightAt(pt geom.Vec2) float64 {
+	w, outline := 1.0, math.Inf(1)
+	for i, region := range p.regions {
+		switch {
+		case len(region) == 0:
+		case region.Contains(pt, false):
+			w = p.weights[i]
+		case region.Contains(pt, true):
+			outline = min(outline, p.weights[i])
+		}
+	}
+	return min(w, outline)
+}
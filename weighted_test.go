@@ -0,0 +1,115 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+func TestNewWeightedPathfinder(t *testing.T) {
+	ground := pathfind.WeightedPolygon{
+		Points: []pathfind.Point{pathfind.Pt(0, 0), pathfind.Pt(100, 0), pathfind.Pt(100, 100), pathfind.Pt(0, 100)},
+	}
+	mud := []pathfind.Point{pathfind.Pt(40, 10), pathfind.Pt(60, 10), pathfind.Pt(60, 80), pathfind.Pt(40, 80)}
+	river := []pathfind.Point{pathfind.Pt(40, 0), pathfind.Pt(60, 0), pathfind.Pt(60, 100), pathfind.Pt(40, 100)}
+	tests := []struct {
+		name     string
+		region   pathfind.WeightedPolygon
+		want     []pathfind.Point
+		wantArea float64
+	}{
+		{
+			name:     "detour around expensive terrain",
+			region:   pathfind.WeightedPolygon{Points: mud, Weight: 5},
+			want:     []pathfind.Point{pathfind.Pt(10, 50), pathfind.Pt(40, 80), pathfind.Pt(60, 80), pathfind.Pt(90, 50)},
+			wantArea: 10000,
+		},
+		{
+			name:     "crossing cheaper than detour",
+			region:   pathfind.WeightedPolygon{Points: mud, Weight: 1.2},
+			want:     []pathfind.Point{pathfind.Pt(10, 50), pathfind.Pt(90, 50)},
+			wantArea: 10000,
+		},
+		{
+			name:     "crossing without alternative",
+			region:   pathfind.WeightedPolygon{Points: river, Weight: 5},
+			want:     []pathfind.Point{pathfind.Pt(10, 50), pathfind.Pt(90, 50)},
+			wantArea: 10000,
+		},
+		{
+			name:     "zero weight makes a hole",
+			region:   pathfind.WeightedPolygon{Points: mud},
+			want:     []pathfind.Point{pathfind.Pt(10, 50), pathfind.Pt(40, 80), pathfind.Pt(60, 80), pathfind.Pt(90, 50)},
+			wantArea: 8600,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pathfinder := pathfind.NewWeightedPathfinder([]pathfind.WeightedPolygon{ground, tt.region})
+			got := pathfinder.Path(pathfind.Pt(10, 50), pathfind.Pt(90, 50))
+			if !pathNearEq(got, tt.want) {
+				t.Errorf("Path = %v, want %v", got, tt.want)
+			}
+			if got := pathfinder.NavigableArea(); got != tt.wantArea {
+				t.Errorf("NavigableArea() = %g, want %g", got, tt.wantArea)
+			}
+		})
+	}
+}
+
+func TestPathfinderWeightedDetourFarFromDirectLine(t *testing.T) {
+	ground := pathfind.WeightedPolygon{
+		Points: []pathfind.Point{pathfind.Pt(0, 0), pathfind.Pt(100, 0), pathfind.Pt(100, 100), pathfind.Pt(0, 100)},
+	}
+	swamp := pathfind.WeightedPolygon{
+		Points: []pathfind.Point{pathfind.Pt(48, 5), pathfind.Pt(52, 5), pathfind.Pt(52, 90), pathfind.Pt(48, 90)},
+		Weight: 100,
+	}
+	pathfinder := pathfind.NewWeightedPathfinder([]pathfind.WeightedPolygon{ground, swamp})
+	start, dest := pathfind.Pt(45, 50), pathfind.Pt(55, 50)
+	got := pathfinder.Path(start, dest)
+	want := []pathfind.Point{start, pathfind.Pt(48, 90), pathfind.Pt(52, 90), dest}
+	if !pathNearEq(got, want) {
+		t.Errorf("Path(%v, %v)\n got: %v\nwant: %v", start, dest, got, want)
+	}
+}
+
+func TestPathfinderWeightedSearchesMatchPath(t *testing.T) {
+	ground := []pathfind.Point{pathfind.Pt(0, 0), pathfind.Pt(100, 0), pathfind.Pt(100, 100), pathfind.Pt(0, 100)}
+	mud := []pathfind.Point{pathfind.Pt(40, 10), pathfind.Pt(60, 10), pathfind.Pt(60, 80), pathfind.Pt(40, 80)}
+	pathfinder := pathfind.NewWeightedPathfinder([]pathfind.WeightedPolygon{
+		{Points: ground},
+		{Points: mud, Weight: 5},
+	})
+	start, dest := pathfind.Pt(10, 50), pathfind.Pt(90, 50)
+	want := []pathfind.Point{start, pathfind.Pt(40, 80), pathfind.Pt(60, 80), dest}
+	if got := pathfinder.Path(start, dest); !pathNearEq(got, want) {
+		t.Fatalf("Path(%v, %v)\n got: %v\nwant: %v", start, dest, got, want)
+	}
+
+	tests := []struct {
+		name string
+		got  []pathfind.Point
+	}{
+		{name: "KPaths", got: firstPath(pathfinder.KPaths(start, dest, 2))},
+		{name: "OptimalTour", got: pathfinder.OptimalTour(start, []pathfind.Point{dest})},
+		{name: "PathWithDoors", got: pathfinder.PathWithDoors(start, dest, nil)},
+		{name: "PathWithin", got: pathfinder.PathWithin(start, dest, ground)},
+	}
+	for _, tt := range tests {
+		if !pathNearEq(tt.got, want) {
+			t.Errorf("%s\n got: %v\nwant: %v", tt.name, tt.got, want)
+		}
+	}
+}
+
+func firstPath(paths [][]pathfind.Point) []pathfind.Point {
+	if len(paths) == 0 {
+		return nil
+	}
+	return paths[0]
+}
@@ -0,0 +1,100 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import (
+	"container/heap"
+	"math"
+)
+
+// WidestPath finds the path from start to dest with the maximum clearance,
+// i.e. among all paths it chooses the one whose narrowest spot is widest.
+// This suits large vehicles that should take the most open route rather
+// than the shortest one. The second result is the clearance at the
+// bottleneck of the path. Like in Path, dest is clamped to the polygon set.
+// The result is (nil, 0) if no path exists.
+//
+// The clearance of a path segment is its distance to the nearest polygon
+// edge, where the edges adjoining the polygon corners that the segment
+// connects are not taken into account. Since paths lead along polygon
+// corners, the clearance approximates the width of the passages the path
+// leads through.
+func (p *Pathfinder) WidestPath(start, dest Point) ([]Point, float64) {
+	if !allFinite(start, dest) {
+		return nil, 0
+	}
+	dest = p.clampDest(start, dest)
+	if containmentLevel(p.polygonSet, start) != containmentLevel(p.polygonSet, dest) {
+		return nil, 0
+	}
+	vis := copyGraph(p.cachedGraph)
+	p.linkIntoGraph(vis, start, []Point{dest})
+	p.linkIntoGraph(vis, dest, nil)
+
+	// Maximin variant of Dijkstra's algorithm: the queue is ordered by
+	// negated bottleneck clearance, so the widest reachable node comes first.
+	best := map[Point]float64{start: math.Inf(1)}
+	prev := make(map[Point]Point)
+	settled := make(map[Point]bool)
+	pq := &distQueue{{node: start, dist: math.Inf(-1)}}
+	for pq.Len() > 0 {
+		n := heap.Pop(pq).(distItem).node
+		if settled[n] {
+			continue
+		}
+		settled[n] = true
+		if n == dest {
+			break
+		}
+		for _, nb := range vis[n] {
+			if settled[nb] {
+				continue
+			}
+			w := min(best[n], p.clearance(n, nb))
+			if old, ok := best[nb]; !ok || w > old {
+				best[nb] = w
+				prev[nb] = n
+				heap.Push(pq, distItem{node: nb, dist: -w})
+			}
+		}
+	}
+	if !settled[dest] {
+		return nil, 0
+	}
+	path := tracePath(prev, dest)
+	offsetPath(p.polygonSet, path, p.opts.margin)
+	return path, best[dest]
+}
+
+// clearance returns the distance of the line segment from a to b to the
+// nearest polygon edge that does not end in a or b.
+func (p *Pathfinder) clearance(a, b Point) float64 {
+	c := math.Inf(1)
+	for _, polygon := range p.polygonSet {
+		for i := range polygon {
+			e := polygon.Edge(i)
+			e1, e2 := v2p(e.A), v2p(e.B)
+			if e1 == a || e1 == b || e2 == a || e2 == b {
+				continue
+			}
+			c = min(c, segmentDist(a, b, e1, e2))
+		}
+	}
+	return c
+}
+
+// segmentDist returns the distance between the line segment from a1 to a2
+// and the line segment from b1 to b2.
+func segmentDist(a1, a2, b1, b2 Point) float64 {
+	if _, ok := SegmentsIntersect(a1, a2, b1, b2); ok {
+		return 0
+	}
+	return min(
+		segmentPointDist(a1, a2, b1),
+		segmentPointDist(a1, a2, b2),
+		segmentPointDist(b1, b2, a1),
+		segmentPointDist(b1, b2, a2),
+	)
+}
@@ -0,0 +1,52 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+// A rectangle with a rectangular hole, leaving a narrow passage above and a
+// wide passage below the hole.
+//
+//	 0,0 >-------------------+ 100,0
+//	     |      >-----+      |
+//	     |  s   |     |   d  |
+//	     |      |     |      |
+//	     |      +-----+      |
+//	     |                   |
+//	0,60 +-------------------+ 100,60
+var polygonTwoPassages = [][]pathfind.Point{
+	{pathfind.Pt(0, 0), pathfind.Pt(100, 0), pathfind.Pt(100, 60), pathfind.Pt(0, 60)},
+	{pathfind.Pt(40, 5), pathfind.Pt(60, 5), pathfind.Pt(60, 50), pathfind.Pt(40, 50)},
+}
+
+func TestPathfinderWidestPath(t *testing.T) {
+	pathfinder := pathfind.NewPathfinder(polygonTwoPassages)
+	start, dest := pathfind.Pt(20, 10), pathfind.Pt(80, 10)
+
+	shortest := pathfinder.Path(start, dest)
+	wantShortest := []pathfind.Point{start, pathfind.Pt(40, 5), pathfind.Pt(60, 5), dest}
+	if !pathNearEq(shortest, wantShortest) {
+		t.Fatalf("Path(%v, %v)\n got: %v\nwant: %v", start, dest, shortest, wantShortest)
+	}
+
+	got, clearance := pathfinder.WidestPath(start, dest)
+	want := []pathfind.Point{start, pathfind.Pt(40, 50), pathfind.Pt(60, 50), dest}
+	if !pathNearEq(got, want) || clearance != 10 {
+		t.Errorf("WidestPath(%v, %v)\n got: %v, %g\nwant: %v, %g",
+			start, dest, got, clearance, want, 10.0)
+	}
+}
+
+func TestPathfinderWidestPathNoPath(t *testing.T) {
+	pathfinder := pathfind.NewPathfinder(polygonU)
+	got, clearance := pathfinder.WidestPath(pathfind.Pt(15, 0), pathfind.Pt(15, 5))
+	if got != nil || clearance != 0 {
+		t.Errorf("WidestPath from outside = %v, %g, want nil, 0", got, clearance)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind
+
+import "github.com/fzipp/pathfind/internal/poly"
+
+// PathWithin finds the shortest path from start to dest like Path, but the
+// path must not leave the polygon region, e.g. the room that a guard
+// patrols, even if a shorter path through adjacent rooms exists. The
+// accessible area for this query is the intersection of the polygon set of
+// the Pathfinder with region. The Pathfinder is not modified.
+//
+// PathWithin returns nil if start or the clamped dest lie outside of region
+// or if no path exists within region.
+func (p *Pathfinder) PathWithin(start, dest Point, region []Point) []Point {
+	r := normalizedHoles(poly.PolygonSet{ps2vs(region)})
+	if len(r) == 0 {
+		return nil
+	}
+	start, dest, ok := p.prepareQuery(start, dest)
+	if !ok || !r.Contains(p2v(start)) || !r.Contains(p2v(dest)) {
+		return nil
+	}
+	visible := func(a, b Point) bool {
+		return p.visible(a, b) && inLineOfSight(r, p2v(a), p2v(b))
+	}
+	if p.straightIsCheapest() && visible(start, dest) {
+		return p.finishPath(p.polygonSet, []Point{start, dest}, visible)
+	}
+
+	// The shortest path can only turn at the concave vertices of the
+	// intersection, which are the concave vertices of either polygon set
+	// that lie inside the other one.
+	var nodes []Point
+	inside := make(map[Point]bool)
+	for _, v := range p.concaveVertices {
+		if r.Contains(p2v(v)) {
+			nodes = append(nodes, v)
+			inside[v] = true
+		}
+	}
+	vis := make(graph[Point])
+	for a, adj := range p.cachedGraph {
+		if !inside[a] {
+			continue
+		}
+		for _, b := range adj {
+			if inside[b] && inLineOfSight(r, p2v(a), p2v(b)) {
+				vis.link(a, b)
+			}
+		}
+	}
+	for _, a := range verticesOfType(r[0], concave) {
+		if !p.polygonSet.Contains(p2v(a)) || inside[a] {
+			continue
+		}
+		for _, b := range nodes {
+			if visible(a, b) {
+				vis.link(a, b).link(b, a)
+			}
+		}
+		nodes = append(nodes, a)
+	}
+	for _, b := range append(nodes, dest) {
+		if visible(start, b) {
+			vis.link(start, b).link(b, start)
+		}
+	}
+	for _, b := range nodes {
+		if visible(dest, b) {
+			vis.link(dest, b).link(b, dest)
+		}
+	}
+
+	cost, heuristic := p.travelCost()
+	var search aStar
+	path := search.findPath(vis, start, dest, cost, heuristic)
+	offsetPath(r, path, p.opts.margin)
+	return p.finishPath(p.polygonSet, path, visible)
+}
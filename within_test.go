@@ -0,0 +1,83 @@
+// Copyright 2023 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pathfind_test
+
+import (
+	"testing"
+
+	"github.com/fzipp/pathfind"
+)
+
+func TestPathfinderPathWithin(t *testing.T) {
+	// The region is a U-shaped corridor along the top, left and bottom
+	// edges of polygonO, excluding the shorter route right of the diamond.
+	//
+	//	>-----------+
+	//	|  +--------+
+	//	|  |  / \
+	//	|  | +   +
+	//	|  |  \ /
+	//	|  +--------+
+	//	+-----------+
+	region := []pathfind.Point{
+		pathfind.Pt(0, 0),
+		pathfind.Pt(40, 0),
+		pathfind.Pt(40, 8),
+		pathfind.Pt(8, 8),
+		pathfind.Pt(8, 32),
+		pathfind.Pt(40, 32),
+		pathfind.Pt(40, 40),
+		pathfind.Pt(0, 40),
+	}
+	tests := []struct {
+		name  string
+		start pathfind.Point
+		dest  pathfind.Point
+		want  []pathfind.Point
+	}{
+		{
+			name:  "Path stays inside region",
+			start: pathfind.Pt(25, 5),
+			dest:  pathfind.Pt(25, 35),
+			want: []pathfind.Point{
+				pathfind.Pt(25, 5),
+				pathfind.Pt(8, 8),
+				pathfind.Pt(8, 32),
+				pathfind.Pt(25, 35),
+			},
+		},
+		{
+			name:  "Direct connection inside region",
+			start: pathfind.Pt(5, 5),
+			dest:  pathfind.Pt(5, 35),
+			want: []pathfind.Point{
+				pathfind.Pt(5, 5),
+				pathfind.Pt(5, 35),
+			},
+		},
+		{
+			name:  "Dest outside region",
+			start: pathfind.Pt(25, 5),
+			dest:  pathfind.Pt(35, 20),
+			want:  nil,
+		},
+		{
+			name:  "Start outside region",
+			start: pathfind.Pt(35, 20),
+			dest:  pathfind.Pt(25, 35),
+			want:  nil,
+		},
+	}
+	pathfinder := pathfind.NewPathfinder(polygonO)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pathfinder.PathWithin(tt.start, tt.dest, region)
+			if !pathNearEq(got, tt.want) {
+				t.Errorf("PathWithin(%v, %v, %v)\n got: %v\nwant: %v",
+					tt.start, tt.dest, region, got, tt.want)
+			}
+		})
+	}
+}